@@ -15,7 +15,14 @@
 // Package backend implements Zero Knowledge Proof systems: it consumes circuit compiled with gnark/frontend.
 package backend
 
-import "github.com/consensys/gnark/constraint/solver"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/consensys/gnark/constraint/solver"
+)
 
 // ID represent a unique ID for a proving scheme
 type ID uint16
@@ -54,12 +61,19 @@ type ProverOption func(*ProverConfig) error
 // ProverConfig is the configuration for the prover with the options applied.
 type ProverConfig struct {
 	SolverOpts []solver.Option
+	Ctx        context.Context // checked between the phases of the proof computation
+	Progress   ProgressFunc    // called at the end of each phase, if not nil
 }
 
+// ProgressFunc is called by the provers at the end of each phase of the proof computation
+// (solving, FFTs, multi-exponentiations, …) with the name of the phase and its duration. Phases
+// may run in parallel, so it may be called concurrently.
+type ProgressFunc func(phase string, took time.Duration)
+
 // NewProverConfig returns a default ProverConfig with given prover options opts
 // applied.
 func NewProverConfig(opts ...ProverOption) (ProverConfig, error) {
-	opt := ProverConfig{}
+	opt := ProverConfig{Ctx: context.Background()}
 	for _, option := range opts {
 		if err := option(&opt); err != nil {
 			return ProverConfig{}, err
@@ -75,3 +89,50 @@ func WithSolverOptions(solverOpts ...solver.Option) ProverOption {
 		return nil
 	}
 }
+
+// WithContext specifies the context of the proof computation. The prover returns an error
+// wrapping the error of ctx if ctx is done when a phase of the computation ends.
+func WithContext(ctx context.Context) ProverOption {
+	return func(opt *ProverConfig) error {
+		if ctx == nil {
+			return errors.New("nil context")
+		}
+		opt.Ctx = ctx
+		return nil
+	}
+}
+
+// WithProgress specifies a function called at the end of each phase of the proof computation.
+func WithProgress(progress ProgressFunc) ProverOption {
+	return func(opt *ProverConfig) error {
+		opt.Progress = progress
+		return nil
+	}
+}
+
+// EndPhase reports the end of a phase of the proof computation, started at start, to the
+// progress function, and returns an error if the context of the prover is done.
+func (cfg *ProverConfig) EndPhase(phase string, start time.Time) error {
+	if cfg.Progress != nil {
+		cfg.Progress(phase, time.Since(start))
+	}
+	return cfg.checkContext(phase)
+}
+
+// Err returns an error if the context of the prover is done.
+func (cfg *ProverConfig) Err() error {
+	return cfg.checkContext("")
+}
+
+func (cfg *ProverConfig) checkContext(phase string) error {
+	if cfg.Ctx == nil {
+		return nil
+	}
+	if err := cfg.Ctx.Err(); err != nil {
+		if phase == "" {
+			return fmt.Errorf("prover aborted: %w", err)
+		}
+		return fmt.Errorf("prover aborted after phase %q: %w", phase, err)
+	}
+	return nil
+}
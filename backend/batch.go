@@ -0,0 +1,49 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/consensys/gnark/backend/witness"
+)
+
+// BatchVerificationError is returned by the batch verifiers when the batch contains invalid
+// proofs. Invalid holds the indexes of the invalid proofs in the batch, in increasing order,
+// and Errs the corresponding verification errors.
+type BatchVerificationError struct {
+	NbProofs int
+	Invalid  []int
+	Errs     []error
+}
+
+func (e *BatchVerificationError) Error() string {
+	var sbb strings.Builder
+	fmt.Fprintf(&sbb, "%d of %d proofs are invalid", len(e.Invalid), e.NbProofs)
+	for i := range e.Invalid {
+		fmt.Fprintf(&sbb, "; proof #%d: %v", e.Invalid[i], e.Errs[i])
+	}
+	return sbb.String()
+}
+
+// BatchVerify converts the proofs and the public witnesses of a batch to the types of a curve,
+// and calls its batch verifier verify. It is shared by the curve-agnostic batch verifiers of
+// the backends.
+func BatchVerify[Q, P, V any](proofs []Q, publicWitnesses []witness.Witness, verify func([]P, []V) error) error {
+	_proofs := make([]P, len(proofs))
+	for i := range proofs {
+		p, ok := any(proofs[i]).(P)
+		if !ok {
+			return fmt.Errorf("proof #%d: unexpected type %T", i, proofs[i])
+		}
+		_proofs[i] = p
+	}
+	ws := make([]V, len(publicWitnesses))
+	for i := range publicWitnesses {
+		w, ok := publicWitnesses[i].Vector().(V)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		ws[i] = w
+	}
+	return verify(_proofs, ws)
+}
@@ -0,0 +1,98 @@
+package groth16_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/internal/backend/circuits"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchVerify(t *testing.T) {
+	const nbProofs = 5
+	for _, curve := range getCurves() {
+		t.Run(curve.String(), func(t *testing.T) {
+			assert := require.New(t)
+
+			ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, &circuits.Cube{WithCommitment: true})
+			assert.NoError(err)
+			pk, vk, err := groth16.Setup(ccs)
+			assert.NoError(err)
+
+			proofs := make([]groth16.Proof, nbProofs)
+			publicWitnesses := make([]witness.Witness, nbProofs)
+			for i := range proofs {
+				x := i + 2
+				w, err := frontend.NewWitness(&circuits.Cube{X: x, Y: x * x * x}, curve.ScalarField())
+				assert.NoError(err)
+				proofs[i], err = groth16.Prove(ccs, pk, w)
+				assert.NoError(err)
+				publicWitnesses[i], err = w.Public()
+				assert.NoError(err)
+			}
+			assert.NoError(groth16.BatchVerify(proofs, vk, publicWitnesses))
+			assert.NoError(groth16.BatchVerify(proofs[:1], vk, publicWitnesses[:1]))
+
+			// the public witnesses of two proofs are swapped
+			publicWitnesses[1], publicWitnesses[3] = publicWitnesses[3], publicWitnesses[1]
+			err = groth16.BatchVerify(proofs, vk, publicWitnesses)
+			var bErr *backend.BatchVerificationError
+			assert.True(errors.As(err, &bErr), "unexpected error %v", err)
+			assert.Equal([]int{1, 3}, bErr.Invalid)
+			assert.Len(bErr.Errs, 2)
+
+			assert.Error(groth16.BatchVerify(proofs, vk, publicWitnesses[:2]))
+			publicWitnesses[1], publicWitnesses[3] = publicWitnesses[3], publicWitnesses[1]
+
+			// the proofs of knowledge of the commitments of two proofs are swapped
+			if curve == ecc.BN254 {
+				p0, p2 := proofs[0].(*groth16_bn254.Proof), proofs[2].(*groth16_bn254.Proof)
+				p0.CommitmentPok, p2.CommitmentPok = p2.CommitmentPok, p0.CommitmentPok
+				err = groth16.BatchVerify(proofs, vk, publicWitnesses)
+				assert.True(errors.As(err, &bErr), "unexpected error %v", err)
+				assert.Equal([]int{0, 2}, bErr.Invalid)
+			}
+		})
+	}
+}
+
+func BenchmarkBatchVerifier(b *testing.B) {
+	const nbProofs = 64
+	curve := ecc.BN254
+	ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, &circuits.Cube{WithCommitment: true})
+	if err != nil {
+		b.Fatal(err)
+	}
+	pk, vk, err := groth16.Setup(ccs)
+	if err != nil {
+		b.Fatal(err)
+	}
+	w, err := frontend.NewWitness(&circuits.Cube{X: 3, Y: 27}, curve.ScalarField())
+	if err != nil {
+		b.Fatal(err)
+	}
+	publicWitness, err := w.Public()
+	if err != nil {
+		b.Fatal(err)
+	}
+	proofs := make([]groth16.Proof, nbProofs)
+	publicWitnesses := make([]witness.Witness, nbProofs)
+	for i := range proofs {
+		if proofs[i], err = groth16.Prove(ccs, pk, w); err != nil {
+			b.Fatal(err)
+		}
+		publicWitnesses[i] = publicWitness
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = groth16.BatchVerify(proofs, vk, publicWitnesses)
+	}
+}
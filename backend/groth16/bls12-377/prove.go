@@ -17,6 +17,7 @@
 package groth16
 
 import (
+	"context"
 	"github.com/consensys/gnark-crypto/ecc"
 	curve "github.com/consensys/gnark-crypto/ecc/bls12-377"
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
@@ -32,6 +33,7 @@ import (
 	"github.com/consensys/gnark/logger"
 	"math/big"
 	"runtime"
+	"sync"
 	"time"
 )
 
@@ -56,11 +58,75 @@ func (proof *Proof) CurveID() ecc.ID {
 }
 
 // Prove generates the proof of knowledge of a r1cs with full witness (secret + public part).
+//
+// The context of the prover (see backend.WithContext) is checked at the end of each phase of
+// the computation: "solve", "commitments", "fft", and the multi-exponentiations "msm A",
+// "msm B1", "msm B2", "msm K" and "msm Z". The FFT and the multi-exponentiations run
+// concurrently: Prove returns as soon as the context is done or one of them fails, without
+// waiting for the ones in progress, which end in the background.
 func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (*Proof, error) {
+	return prove(r1cs, pk, pk, sampleRandom, fullWitness, opts...)
+}
+
+// g1Key identifies the slices of G1 points of a proving key in the multi-exponentiations of the
+// prover.
+type g1Key uint8
+
+const (
+	g1KeyA g1Key = iota
+	g1KeyB
+	g1KeyZ
+	g1KeyK
+)
+
+// keyMultiExp computes the multi-exponentiations of the prover with the points of a proving key,
+// which aren't necessarily in memory (see DiskProvingKey).
+type keyMultiExp interface {
+	multiExpG1(res *curve.G1Jac, key g1Key, scalars []fr.Element, config ecc.MultiExpConfig) error
+	multiExpG2B(res *curve.G2Jac, scalars []fr.Element, config ecc.MultiExpConfig) error
+}
+
+func (pk *ProvingKey) multiExpG1(res *curve.G1Jac, key g1Key, scalars []fr.Element, config ecc.MultiExpConfig) error {
+	var points []curve.G1Affine
+	switch key {
+	case g1KeyA:
+		points = pk.G1.A
+	case g1KeyB:
+		points = pk.G1.B
+	case g1KeyZ:
+		points = pk.G1.Z
+	case g1KeyK:
+		points = pk.G1.K
+	}
+	_, err := res.MultiExp(points, scalars, config)
+	return err
+}
+
+func (pk *ProvingKey) multiExpG2B(res *curve.G2Jac, scalars []fr.Element, config ecc.MultiExpConfig) error {
+	_, err := res.MultiExp(pk.G2.B, scalars, config)
+	return err
+}
+
+// sampleRandom sets r and s, which randomize the proof, to random elements.
+func sampleRandom(r, s *fr.Element) error {
+	if _, err := r.SetRandom(); err != nil {
+		return err
+	}
+	_, err := s.SetRandom()
+	return err
+}
+
+// prove computes the proof with the elements of pk, except for the multi-exponentiations with the
+// large slices of points of the key, computed by msm. The proof is randomized with the elements
+// set by sample.
+func prove(r1cs *cs.R1CS, pk *ProvingKey, msm keyMultiExp, sample func(r, s *fr.Element) error, fullWitness witness.Witness, opts ...backend.ProverOption) (*Proof, error) {
 	opt, err := backend.NewProverConfig(opts...)
 	if err != nil {
 		return nil, err
 	}
+	if err := opt.Err(); err != nil {
+		return nil, err
+	}
 
 	log := logger.Logger().With().Str("curve", r1cs.CurveID().String()).Int("nbConstraints", r1cs.GetNbConstraints()).Str("backend", "groth16").Logger()
 
@@ -101,10 +167,14 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 			solver.OverrideHint(r1cs.GkrInfo.ProveHintID, cs.GkrProveHint(r1cs.GkrInfo.HashName, &gkrData)))
 	}
 
+	startSolve := time.Now()
 	_solution, err := r1cs.Solve(fullWitness, solverOpts...)
 	if err != nil {
 		return nil, err
 	}
+	if err := opt.EndPhase("solve", startSolve); err != nil {
+		return nil, err
+	}
 
 	solution := _solution.(*cs.R1CSSolution)
 	wireValues := []fr.Element(solution.W)
@@ -119,10 +189,48 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	if proof.CommitmentPok, err = pedersen.BatchProve(pk.CommitmentKeys, privateCommittedValues, commitmentsSerialized); err != nil {
 		return nil, err
 	}
+	if err := opt.EndPhase("commitments", start); err != nil {
+		return nil, err
+	}
+
+	// the first error of the concurrent computations below, or the end of the context of the
+	// prover, cancels ctx: the multi-exponentiations which haven't started are skipped.
+	ctx, cancel := context.WithCancel(opt.Ctx)
+	defer cancel()
+	var (
+		stopOnce sync.Once
+		stopErr  error
+	)
+	fail := func(err error) error {
+		stopOnce.Do(func() {
+			stopErr = err
+			cancel()
+		})
+		return err
+	}
+	// stopped returns the error which stopped the computation of the proof.
+	stopped := func() error {
+		stopOnce.Do(func() { stopErr = opt.Err() })
+		return stopErr
+	}
+	multiExp := func(phase string, compute func() error) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		start := time.Now()
+		if err := compute(); err != nil {
+			return fail(err)
+		}
+		if err := opt.EndPhase(phase, start); err != nil {
+			return fail(err)
+		}
+		return nil
+	}
 
 	// H (witness reduction / FFT part)
 	var h []fr.Element
 	chHDone := make(chan struct{}, 1)
+	startH := time.Now()
 	go func() {
 		h = computeH(solution.A, solution.B, solution.C, &pk.Domain)
 		solution.A = nil
@@ -162,10 +270,7 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	// sample random r and s
 	var r, s big.Int
 	var _r, _s, _kr fr.Element
-	if _, err := _r.SetRandom(); err != nil {
-		return nil, err
-	}
-	if _, err := _s.SetRandom(); err != nil {
+	if err := sample(&_r, &_s); err != nil {
 		return nil, err
 	}
 	_kr.Mul(&_r, &_s).Neg(&_kr)
@@ -183,7 +288,9 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	chBs1Done := make(chan error, 1)
 	computeBS1 := func() {
 		<-chWireValuesB
-		if _, err := bs1.MultiExp(pk.G1.B, wireValuesB, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
+		if err := multiExp("msm B1", func() error {
+			return msm.multiExpG1(&bs1, g1KeyB, wireValuesB, ecc.MultiExpConfig{NbTasks: n / 2})
+		}); err != nil {
 			chBs1Done <- err
 			close(chBs1Done)
 			return
@@ -196,7 +303,9 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	chArDone := make(chan error, 1)
 	computeAR1 := func() {
 		<-chWireValuesA
-		if _, err := ar.MultiExp(pk.G1.A, wireValuesA, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
+		if err := multiExp("msm A", func() error {
+			return msm.multiExpG1(&ar, g1KeyA, wireValuesA, ecc.MultiExpConfig{NbTasks: n / 2})
+		}); err != nil {
 			chArDone <- err
 			close(chArDone)
 			return
@@ -216,8 +325,9 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 		chKrs2Done := make(chan error, 1)
 		sizeH := int(pk.Domain.Cardinality - 1) // comes from the fact the deg(H)=(n-1)+(n-1)-n=n-2
 		go func() {
-			_, err := krs2.MultiExp(pk.G1.Z, h[:sizeH], ecc.MultiExpConfig{NbTasks: n / 2})
-			chKrs2Done <- err
+			chKrs2Done <- multiExp("msm Z", func() error {
+				return msm.multiExpG1(&krs2, g1KeyZ, h[:sizeH], ecc.MultiExpConfig{NbTasks: n / 2})
+			})
 		}()
 
 		// filter the wire values if needed
@@ -226,7 +336,9 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 		toRemove = append(toRemove, commitmentInfo.CommitmentIndexes())
 		_wireValues := filterHeap(wireValues[r1cs.GetNbPublicVariables():], r1cs.GetNbPublicVariables(), internal.ConcatAll(toRemove...))
 
-		if _, err := krs.MultiExp(pk.G1.K, _wireValues, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
+		if err := multiExp("msm K", func() error {
+			return msm.multiExpG1(&krs, g1KeyK, _wireValues, ecc.MultiExpConfig{NbTasks: n / 2})
+		}); err != nil {
 			chKrsDone <- err
 			return
 		}
@@ -272,7 +384,9 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 			nbTasks *= 2
 		}
 		<-chWireValuesB
-		if _, err := Bs.MultiExp(pk.G2.B, wireValuesB, ecc.MultiExpConfig{NbTasks: nbTasks}); err != nil {
+		if err := multiExp("msm B2", func() error {
+			return msm.multiExpG2B(&Bs, wireValuesB, ecc.MultiExpConfig{NbTasks: nbTasks})
+		}); err != nil {
 			return err
 		}
 
@@ -286,19 +400,34 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	}
 
 	// wait for FFT to end, as it uses all our CPUs
-	<-chHDone
+	select {
+	case <-chHDone:
+	case <-ctx.Done():
+		return nil, stopped()
+	}
+	if err := opt.EndPhase("fft", startH); err != nil {
+		return nil, err
+	}
 
 	// schedule our proof part computations
+	chBs2Done := make(chan error, 1)
 	go computeKRS()
 	go computeAR1()
 	go computeBS1()
-	if err := computeBS2(); err != nil {
-		return nil, err
-	}
+	go func() {
+		chBs2Done <- computeBS2()
+	}()
 
-	// wait for all parts of the proof to be computed.
-	if err := <-chKrsDone; err != nil {
-		return nil, err
+	// wait for all parts of the proof to be computed, or for the first error.
+	for _, ch := range []chan error{chKrsDone, chBs2Done} {
+		select {
+		case err := <-ch:
+			if err != nil {
+				return nil, stopped()
+			}
+		case <-ctx.Done():
+			return nil, stopped()
+		}
 	}
 
 	log.Debug().Dur("took", time.Since(start)).Msg("prover done")
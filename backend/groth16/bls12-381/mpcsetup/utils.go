@@ -18,6 +18,9 @@ package mpcsetup
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
 	"math/big"
 	"math/bits"
 	"runtime"
@@ -25,6 +28,7 @@ import (
 	"github.com/consensys/gnark-crypto/ecc"
 	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/pedersen"
 	"github.com/consensys/gnark/internal/utils"
 )
 
@@ -34,13 +38,15 @@ type PublicKey struct {
 	XR  curve.G2Affine
 }
 
-func newPublicKey(x fr.Element, challenge []byte, dst byte) PublicKey {
+// newPublicKey returns the public key of the secret x. The secret s of the proof of knowledge of x
+// is drawn with sample.
+func newPublicKey(x fr.Element, challenge []byte, dst byte, sample func(*fr.Element)) PublicKey {
 	var pk PublicKey
 	_, _, g1, _ := curve.Generators()
 
 	var s fr.Element
 	var sBi big.Int
-	s.SetRandom()
+	sample(&s)
 	s.BigInt(&sBi)
 	pk.SG.ScalarMultiplication(&g1, &sBi)
 
@@ -57,6 +63,91 @@ func newPublicKey(x fr.Element, challenge []byte, dst byte) PublicKey {
 	return pk
 }
 
+// sampleRandom draws the secrets of a contribution uniformly at random.
+func sampleRandom(e *fr.Element) {
+	if _, err := e.SetRandom(); err != nil {
+		panic(err)
+	}
+}
+
+// sampleOne is used for the initial states, which hold no secret: they are deterministic, so that
+// anyone can recompute them.
+func sampleOne(e *fr.Element) {
+	e.SetOne()
+}
+
+// beaconDST is the domain separation tag of the hashes to the field of the random beacon.
+const beaconDST = "gnark-groth16-mpcsetup-beacon"
+
+var errInvalidIterations = errors.New("the number of iterations of the beacon must be positive")
+
+// newBeaconSampler returns a deterministic sampler of the secrets of a contribution: the seed of
+// the random beacon is hashed nbIterations times with SHA-256, so that computing the secrets takes
+// time, and the i-th secret drawn is the hash to the field of the result and i. It panics if
+// nbIterations < 1.
+func newBeaconSampler(seed []byte, nbIterations int) func(*fr.Element) {
+	if nbIterations < 1 {
+		panic(errInvalidIterations)
+	}
+	h := sha256.Sum256(seed)
+	for i := 1; i < nbIterations; i++ {
+		h = sha256.Sum256(h[:])
+	}
+	var msg [sha256.Size + 8]byte
+	copy(msg[:], h[:])
+	var counter uint64
+	return func(e *fr.Element) {
+		binary.BigEndian.PutUint64(msg[sha256.Size:], counter)
+		counter++
+		r, err := fr.Hash(msg[:], []byte(beaconDST), 1)
+		if err != nil {
+			panic(err)
+		}
+		e.Set(&r[0])
+	}
+}
+
+// newPedersenKeys returns the Pedersen commitment keys of the bases, given their images sigmaBases by
+// the secret σ, the base g of the verifying key and -[1/σ]g. The fields of the keys are not
+// exported, so the keys are decoded from their serialized form.
+func newPedersenKeys(bases, sigmaBases [][]curve.G1Affine, g, gRootSigmaNeg curve.G2Affine) ([]pedersen.ProvingKey, pedersen.VerifyingKey, error) {
+	var vk pedersen.VerifyingKey
+	if len(bases) != len(sigmaBases) {
+		return nil, vk, errors.New("number of commitment keys mismatch")
+	}
+
+	var buf bytes.Buffer
+	enc := curve.NewEncoder(&buf)
+	for i := range bases {
+		if len(bases[i]) != len(sigmaBases[i]) {
+			return nil, vk, errors.New("commitment key size mismatch")
+		}
+		if err := enc.Encode(bases[i]); err != nil {
+			return nil, vk, err
+		}
+		if err := enc.Encode(sigmaBases[i]); err != nil {
+			return nil, vk, err
+		}
+	}
+	if err := enc.Encode(&g); err != nil {
+		return nil, vk, err
+	}
+	if err := enc.Encode(&gRootSigmaNeg); err != nil {
+		return nil, vk, err
+	}
+
+	pk := make([]pedersen.ProvingKey, len(bases))
+	for i := range pk {
+		if _, err := pk[i].ReadFrom(&buf); err != nil {
+			return nil, vk, err
+		}
+	}
+	if _, err := vk.ReadFrom(&buf); err != nil {
+		return nil, vk, err
+	}
+	return pk, vk, nil
+}
+
 func bitReverse[T any](a []T) {
 	n := uint64(len(a))
 	nn := uint64(64 - bits.TrailingZeros64(n))
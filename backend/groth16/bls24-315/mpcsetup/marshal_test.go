@@ -34,19 +34,20 @@ func TestContributionSerialization(t *testing.T) {
 	assert := require.New(t)
 
 	// Phase 1
-	srs1 := InitPhase1(9)
-	srs1.Contribute()
-
-	assert.NoError(gnarkio.RoundTripCheck(&srs1, func() interface{} { return new(Phase1) }))
-
 	var myCircuit Circuit
 	ccs, err := frontend.Compile(curve.ID.ScalarField(), r1cs.NewBuilder, &myCircuit)
 	assert.NoError(err)
 
+	srs1 := InitPhase1(domainPower(ccs))
+	srs1.Contribute()
+
+	assert.NoError(gnarkio.RoundTripCheck(&srs1, func() interface{} { return new(Phase1) }))
+
 	r1cs := ccs.(*cs.R1CS)
 
 	// Phase 2
-	srs2, _ := InitPhase2(r1cs, &srs1)
+	srs2, _, err := InitPhase2(r1cs, &srs1)
+	assert.NoError(err)
 	srs2.Contribute()
 
 	assert.NoError(gnarkio.RoundTripCheck(&srs2, func() interface{} { return new(Phase2) }))
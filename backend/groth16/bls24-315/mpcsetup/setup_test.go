@@ -17,12 +17,17 @@
 package mpcsetup
 
 import (
+	"errors"
+	"math/bits"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
 	curve "github.com/consensys/gnark-crypto/ecc/bls24-315"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
 	cs "github.com/consensys/gnark/constraint/bls24-315"
-	"testing"
 
 	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
 	"github.com/consensys/gnark/std/hash/mimc"
@@ -38,12 +43,16 @@ func TestSetupCircuit(t *testing.T) {
 	const (
 		nContributionsPhase1 = 3
 		nContributionsPhase2 = 3
-		power                = 9
 	)
 
 	assert := require.New(t)
 
-	srs1 := InitPhase1(power)
+	// Compile the circuit
+	var myCircuit Circuit
+	ccs, err := frontend.Compile(curve.ID.ScalarField(), r1cs.NewBuilder, &myCircuit)
+	assert.NoError(err)
+
+	srs1 := InitPhase1(domainPower(ccs))
 
 	// Make and verify contributions for phase1
 	for i := 1; i < nContributionsPhase1; i++ {
@@ -55,16 +64,11 @@ func TestSetupCircuit(t *testing.T) {
 		assert.NoError(VerifyPhase1(&prev, &srs1))
 	}
 
-	// Compile the circuit
-	var myCircuit Circuit
-	ccs, err := frontend.Compile(curve.ID.ScalarField(), r1cs.NewBuilder, &myCircuit)
-	assert.NoError(err)
-
-	var evals Phase2Evaluations
 	r1cs := ccs.(*cs.R1CS)
 
 	// Prepare for phase-2
-	srs2, evals := InitPhase2(r1cs, &srs1)
+	srs2, evals, err := InitPhase2(r1cs, &srs1)
+	assert.NoError(err)
 
 	// Make and verify contributions for phase1
 	for i := 1; i < nContributionsPhase2; i++ {
@@ -77,7 +81,8 @@ func TestSetupCircuit(t *testing.T) {
 	}
 
 	// Extract the proving and verifying keys
-	pk, vk := ExtractKeys(&srs1, &srs2, &evals, ccs.GetNbConstraints())
+	pk, vk, err := ExtractKeys(&srs1, &srs2, &evals, ccs.GetNbConstraints())
+	assert.NoError(err)
 
 	// Build the witness
 	var preImage, hash fr.Element
@@ -101,6 +106,111 @@ func TestSetupCircuit(t *testing.T) {
 	assert.NoError(err)
 }
 
+func TestSetupCircuitWithCommitment(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	assert := require.New(t)
+
+	var myCircuit commitmentCircuit
+	ccs, err := frontend.Compile(curve.ID.ScalarField(), r1cs.NewBuilder, &myCircuit)
+	assert.NoError(err)
+
+	srs1 := InitPhase1(domainPower(ccs))
+	srs1.Contribute()
+
+	// phase 1 must be of the size of the domain of the circuit
+	oversized := InitPhase1(domainPower(ccs) + 1)
+	_, _, err = InitPhase2(ccs.(*cs.R1CS), &oversized)
+	assert.Error(err)
+
+	// Prepare for phase-2 and make the contributions
+	srs2, evals, err := InitPhase2(ccs.(*cs.R1CS), &srs1)
+	assert.NoError(err)
+	assert.Len(evals.G1.CKK, 1)
+	for i := 0; i < 2; i++ {
+		prev := srs2.clone()
+		srs2.Contribute()
+		assert.NoError(VerifyPhase2(&prev, &srs2))
+	}
+
+	// a contribution must update the commitment keys with its σ
+	prev := srs2.clone()
+	tampered := srs2.clone()
+	tampered.Contribute()
+	tampered.Parameters.G1.SigmaCKK[0][0] = prev.Parameters.G1.SigmaCKK[0][0]
+	tampered.Hash = tampered.hash()
+	assert.Error(VerifyPhase2(&prev, &tampered))
+
+	// malformed commitment keys are rejected
+	malformed := srs2.clone()
+	malformed.Parameters.G1.SigmaCKK[0] = malformed.Parameters.G1.SigmaCKK[0][1:]
+	_, _, err = ExtractKeys(&srs1, &malformed, &evals, ccs.GetNbConstraints())
+	assert.Error(err)
+
+	// Extract the proving and verifying keys, and prove
+	pk, vk, err := ExtractKeys(&srs1, &srs2, &evals, ccs.GetNbConstraints())
+	assert.NoError(err)
+
+	witness, err := frontend.NewWitness(&commitmentCircuit{X: 3, Y: 9}, curve.ID.ScalarField())
+	assert.NoError(err)
+	pubWitness, err := witness.Public()
+	assert.NoError(err)
+
+	proof, err := groth16.Prove(ccs, &pk, witness)
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, &vk, pubWitness))
+}
+
+func TestBeacon(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	assert := require.New(t)
+	seed := []byte("block 0x3ab6c1")
+	const nbIterations = 1 << 10
+
+	var myCircuit Circuit
+	ccs, err := frontend.Compile(curve.ID.ScalarField(), r1cs.NewBuilder, &myCircuit)
+	assert.NoError(err)
+
+	// Phase 1
+	power := domainPower(ccs)
+	srs1 := InitPhase1(power)
+	assert.Equal(srs1.Hash, InitPhase1(power).Hash, "the initial state should be deterministic")
+	srs1.Contribute()
+	prev1 := srs1.clone()
+	srs1.ContributeWithBeacon(seed, nbIterations)
+	assert.NoError(VerifyPhase1(&prev1, &srs1))
+	assert.NoError(VerifyPhase1Beacon(&prev1, &srs1, seed, nbIterations))
+
+	// the contribution is deterministic
+	again := prev1.clone()
+	again.ContributeWithBeacon(seed, nbIterations)
+	assert.Equal(srs1.Hash, again.Hash)
+
+	assert.Error(VerifyPhase1Beacon(&prev1, &srs1, []byte("block 0x3ab6c2"), nbIterations))
+	assert.Error(VerifyPhase1Beacon(&prev1, &srs1, seed, nbIterations-1))
+	random := prev1.clone()
+	random.Contribute()
+	assert.Error(VerifyPhase1Beacon(&prev1, &random, seed, nbIterations))
+	assert.Error(VerifyPhase1Beacon(&prev1, &prev1, seed, 0))
+	assert.Panics(func() { random.ContributeWithBeacon(seed, 0) })
+
+	// Phase 2
+	srs2, _, err := InitPhase2(ccs.(*cs.R1CS), &srs1)
+	assert.NoError(err)
+	srs2.Contribute()
+	prev2 := srs2.clone()
+	srs2.ContributeWithBeacon(seed, nbIterations)
+	assert.NoError(VerifyPhase2(&prev2, &srs2))
+	assert.NoError(VerifyPhase2Beacon(&prev2, &srs2, seed, nbIterations))
+	assert.Error(VerifyPhase2Beacon(&prev2, &srs2, seed, nbIterations+1))
+	assert.Error(VerifyPhase2Beacon(&prev2, &srs2, seed, -1))
+	srs2.Hash = prev2.Hash
+	assert.Error(VerifyPhase2Beacon(&prev2, &srs2, seed, nbIterations))
+}
+
 func BenchmarkPhase1(b *testing.B) {
 	const power = 14
 
@@ -122,27 +232,28 @@ func BenchmarkPhase1(b *testing.B) {
 }
 
 func BenchmarkPhase2(b *testing.B) {
-	const power = 14
-	srs1 := InitPhase1(power)
-	srs1.Contribute()
-
 	var myCircuit Circuit
 	ccs, err := frontend.Compile(curve.ID.ScalarField(), r1cs.NewBuilder, &myCircuit)
 	if err != nil {
 		b.Fatal(err)
 	}
+	srs1 := InitPhase1(domainPower(ccs))
+	srs1.Contribute()
 
 	r1cs := ccs.(*cs.R1CS)
 
 	b.Run("init", func(b *testing.B) {
 		b.ResetTimer()
 		for i := 0; i < b.N; i++ {
-			_, _ = InitPhase2(r1cs, &srs1)
+			_, _, _ = InitPhase2(r1cs, &srs1)
 		}
 	})
 
 	b.Run("contrib", func(b *testing.B) {
-		srs2, _ := InitPhase2(r1cs, &srs1)
+		srs2, _, err := InitPhase2(r1cs, &srs1)
+		if err != nil {
+			b.Fatal(err)
+		}
 		b.ResetTimer()
 		for i := 0; i < b.N; i++ {
 			srs2.Contribute()
@@ -151,6 +262,11 @@ func BenchmarkPhase2(b *testing.B) {
 
 }
 
+// domainPower returns the power of two of the domain of ccs, the size of phase 1 it needs.
+func domainPower(ccs constraint.ConstraintSystem) int {
+	return bits.TrailingZeros64(ecc.NextPowerOfTwo(uint64(ccs.GetNbConstraints())))
+}
+
 // Circuit defines a pre-image knowledge proof
 // mimc(secret preImage) = public hash
 type Circuit struct {
@@ -171,29 +287,23 @@ func (circuit *Circuit) Define(api frontend.API) error {
 	return nil
 }
 
-func (phase1 *Phase1) clone() Phase1 {
-	r := Phase1{}
-	r.Parameters.G1.Tau = append(r.Parameters.G1.Tau, phase1.Parameters.G1.Tau...)
-	r.Parameters.G1.AlphaTau = append(r.Parameters.G1.AlphaTau, phase1.Parameters.G1.AlphaTau...)
-	r.Parameters.G1.BetaTau = append(r.Parameters.G1.BetaTau, phase1.Parameters.G1.BetaTau...)
-
-	r.Parameters.G2.Tau = append(r.Parameters.G2.Tau, phase1.Parameters.G2.Tau...)
-	r.Parameters.G2.Beta = phase1.Parameters.G2.Beta
-
-	r.PublicKeys = phase1.PublicKeys
-	r.Hash = append(r.Hash, phase1.Hash...)
-
-	return r
+// commitmentCircuit commits to its variables, as lookups and range checks do
+type commitmentCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
 }
 
-func (phase2 *Phase2) clone() Phase2 {
-	r := Phase2{}
-	r.Parameters.G1.Delta = phase2.Parameters.G1.Delta
-	r.Parameters.G1.L = append(r.Parameters.G1.L, phase2.Parameters.G1.L...)
-	r.Parameters.G1.Z = append(r.Parameters.G1.Z, phase2.Parameters.G1.Z...)
-	r.Parameters.G2.Delta = phase2.Parameters.G2.Delta
-	r.PublicKey = phase2.PublicKey
-	r.Hash = append(r.Hash, phase2.Hash...)
+func (circuit *commitmentCircuit) Define(api frontend.API) error {
+	committer, ok := api.Compiler().(frontend.Committer)
+	if !ok {
+		return errors.New("compiler does not commit")
+	}
+	commitment, err := committer.Commit(circuit.X, circuit.Y)
+	if err != nil {
+		return err
+	}
+	api.AssertIsDifferent(commitment, 0)
+	api.AssertIsEqual(api.Mul(circuit.X, circuit.X), circuit.Y)
 
-	return r
+	return nil
 }
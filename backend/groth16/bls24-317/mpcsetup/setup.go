@@ -17,16 +17,23 @@
 package mpcsetup
 
 import (
+	"fmt"
+
 	curve "github.com/consensys/gnark-crypto/ecc/bls24-317"
 	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr/fft"
 	groth16 "github.com/consensys/gnark/backend/groth16/bls24-317"
 )
 
-func ExtractKeys(srs1 *Phase1, srs2 *Phase2, evals *Phase2Evaluations, nConstraints int) (pk groth16.ProvingKey, vk groth16.VerifyingKey) {
+// ExtractKeys returns the Groth16 keys of the circuit of nConstraints constraints from the results
+// of both phases and the evaluations returned by InitPhase2.
+func ExtractKeys(srs1 *Phase1, srs2 *Phase2, evals *Phase2Evaluations, nConstraints int) (pk groth16.ProvingKey, vk groth16.VerifyingKey, err error) {
 	_, _, _, g2 := curve.Generators()
 
 	// Initialize PK
 	pk.Domain = *fft.NewDomain(uint64(nConstraints))
+	if n := len(srs1.Parameters.G1.AlphaTau); uint64(n) != pk.Domain.Cardinality || len(srs2.Parameters.G1.Z) != n-1 {
+		return pk, vk, fmt.Errorf("the phases don't match the domain of size %d", pk.Domain.Cardinality)
+	}
 	pk.G1.Alpha.Set(&srs1.Parameters.G1.AlphaTau[0])
 	pk.G1.Beta.Set(&srs1.Parameters.G1.BetaTau[0])
 	pk.G1.Delta.Set(&srs2.Parameters.G1.Delta)
@@ -88,10 +95,17 @@ func ExtractKeys(srs1 *Phase1, srs2 *Phase2, evals *Phase2Evaluations, nConstrai
 	vk.G2.Gamma.Set(&g2)
 	vk.G1.K = evals.G1.VKK
 
+	// Set the Pedersen commitment keys, with [1]₂ as the base of the verifying key
+	pk.CommitmentKeys, vk.CommitmentKey, err = newPedersenKeys(evals.G1.CKK, srs2.Parameters.G1.SigmaCKK, g2, srs2.Parameters.G2.GRootSigmaNeg)
+	if err != nil {
+		return pk, vk, fmt.Errorf("commitment keys: %w", err)
+	}
+	vk.PublicAndCommitmentCommitted = evals.PublicAndCommitmentCommitted
+
 	// sets e, -[δ]2, -[γ]2
 	if err := vk.Precompute(); err != nil {
-		panic(err)
+		return pk, vk, err
 	}
 
-	return pk, vk
+	return pk, vk, nil
 }
@@ -18,6 +18,7 @@ package mpcsetup
 
 import (
 	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark/internal/utils"
 	"io"
 )
 
@@ -108,6 +109,14 @@ func (c *Phase2) writeTo(writer io.Writer) (int64, error) {
 		c.Parameters.G1.L,
 		c.Parameters.G1.Z,
 		&c.Parameters.G2.Delta,
+		&c.SigmaPublicKey.SG,
+		&c.SigmaPublicKey.SXG,
+		&c.SigmaPublicKey.XR,
+		&c.Parameters.G2.GRootSigmaNeg,
+		uint32(len(c.Parameters.G1.SigmaCKK)),
+	}
+	for i := range c.Parameters.G1.SigmaCKK {
+		toEncode = append(toEncode, c.Parameters.G1.SigmaCKK[i])
 	}
 
 	for _, v := range toEncode {
@@ -130,6 +139,10 @@ func (c *Phase2) ReadFrom(reader io.Reader) (int64, error) {
 		&c.Parameters.G1.L,
 		&c.Parameters.G1.Z,
 		&c.Parameters.G2.Delta,
+		&c.SigmaPublicKey.SG,
+		&c.SigmaPublicKey.SXG,
+		&c.SigmaPublicKey.XR,
+		&c.Parameters.G2.GRootSigmaNeg,
 	}
 
 	for _, v := range toEncode {
@@ -138,6 +151,17 @@ func (c *Phase2) ReadFrom(reader io.Reader) (int64, error) {
 		}
 	}
 
+	var nbCommitments uint32
+	if err := dec.Decode(&nbCommitments); err != nil {
+		return dec.BytesRead(), err
+	}
+	c.Parameters.G1.SigmaCKK = make([][]curve.G1Affine, nbCommitments)
+	for i := range c.Parameters.G1.SigmaCKK {
+		if err := dec.Decode(&c.Parameters.G1.SigmaCKK[i]); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
 	c.Hash = make([]byte, 32)
 	n, err := reader.Read(c.Hash)
 	return int64(n) + dec.BytesRead(), err
@@ -150,7 +174,13 @@ func (c *Phase2Evaluations) WriteTo(writer io.Writer) (int64, error) {
 	toEncode := []interface{}{
 		c.G1.A,
 		c.G1.B,
+		c.G1.VKK,
 		c.G2.B,
+		utils.IntSliceSliceToUint64SliceSlice(c.PublicAndCommitmentCommitted),
+		uint32(len(c.G1.CKK)),
+	}
+	for i := range c.G1.CKK {
+		toEncode = append(toEncode, c.G1.CKK[i])
 	}
 
 	for _, v := range toEncode {
@@ -165,10 +195,15 @@ func (c *Phase2Evaluations) WriteTo(writer io.Writer) (int64, error) {
 // ReadFrom implements io.ReaderFrom
 func (c *Phase2Evaluations) ReadFrom(reader io.Reader) (int64, error) {
 	dec := curve.NewDecoder(reader)
+	var publicAndCommitmentCommitted [][]uint64
+	var nbCommitments uint32
 	toEncode := []interface{}{
 		&c.G1.A,
 		&c.G1.B,
+		&c.G1.VKK,
 		&c.G2.B,
+		&publicAndCommitmentCommitted,
+		&nbCommitments,
 	}
 
 	for _, v := range toEncode {
@@ -176,6 +211,14 @@ func (c *Phase2Evaluations) ReadFrom(reader io.Reader) (int64, error) {
 			return dec.BytesRead(), err
 		}
 	}
+	c.PublicAndCommitmentCommitted = utils.Uint64SliceSliceToIntSliceSlice(publicAndCommitmentCommitted)
+
+	c.G1.CKK = make([][]curve.G1Affine, nbCommitments)
+	for i := range c.G1.CKK {
+		if err := dec.Decode(&c.G1.CKK[i]); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
 
 	return dec.BytesRead(), nil
 }
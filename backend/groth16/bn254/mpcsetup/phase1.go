@@ -17,6 +17,7 @@
 package mpcsetup
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"errors"
 	curve "github.com/consensys/gnark-crypto/ecc/bn254"
@@ -48,7 +49,7 @@ type Phase1 struct {
 }
 
 // InitPhase1 initialize phase 1 of the MPC. This is called once by the coordinator before
-// any randomness contribution is made (see Contribute()).
+// any randomness contribution is made (see Contribute()). The initial state is deterministic.
 func InitPhase1(power int) (phase1 Phase1) {
 	N := int(math.Pow(2, float64(power)))
 
@@ -57,9 +58,9 @@ func InitPhase1(power int) (phase1 Phase1) {
 	tau.SetOne()
 	alpha.SetOne()
 	beta.SetOne()
-	phase1.PublicKeys.Tau = newPublicKey(tau, nil, 1)
-	phase1.PublicKeys.Alpha = newPublicKey(alpha, nil, 2)
-	phase1.PublicKeys.Beta = newPublicKey(beta, nil, 3)
+	phase1.PublicKeys.Tau = newPublicKey(tau, nil, 1, sampleOne)
+	phase1.PublicKeys.Alpha = newPublicKey(alpha, nil, 2, sampleOne)
+	phase1.PublicKeys.Beta = newPublicKey(beta, nil, 3, sampleOne)
 
 	// First contribution use generators
 	_, _, g1, g2 := curve.Generators()
@@ -87,16 +88,34 @@ func InitPhase1(power int) (phase1 Phase1) {
 
 // Contribute contributes randomness to the phase1 object. This mutates phase1.
 func (phase1 *Phase1) Contribute() {
+	phase1.contribute(sampleRandom)
+}
+
+// ContributeWithBeacon makes a contribution whose secrets are derived from a random beacon: a
+// public seed nobody could predict before the previous contributions were made (e.g. a future
+// block hash), hashed nbIterations times. It is usually the last contribution of the phase, and
+// anyone can check it with VerifyPhase1Beacon. This mutates phase1. It panics if
+// nbIterations < 1.
+func (phase1 *Phase1) ContributeWithBeacon(seed []byte, nbIterations int) {
+	phase1.contribute(newBeaconSampler(seed, nbIterations))
+}
+
+// ContributionHash returns the hash of the last contribution, which the next one builds on.
+func (phase1 *Phase1) ContributionHash() []byte {
+	return phase1.Hash
+}
+
+func (phase1 *Phase1) contribute(sample func(*fr.Element)) {
 	N := len(phase1.Parameters.G2.Tau)
 
 	// Generate key pairs
 	var tau, alpha, beta fr.Element
-	tau.SetRandom()
-	alpha.SetRandom()
-	beta.SetRandom()
-	phase1.PublicKeys.Tau = newPublicKey(tau, phase1.Hash[:], 1)
-	phase1.PublicKeys.Alpha = newPublicKey(alpha, phase1.Hash[:], 2)
-	phase1.PublicKeys.Beta = newPublicKey(beta, phase1.Hash[:], 3)
+	sample(&tau)
+	sample(&alpha)
+	sample(&beta)
+	phase1.PublicKeys.Tau = newPublicKey(tau, phase1.Hash[:], 1, sample)
+	phase1.PublicKeys.Alpha = newPublicKey(alpha, phase1.Hash[:], 2, sample)
+	phase1.PublicKeys.Beta = newPublicKey(beta, phase1.Hash[:], 3, sample)
 
 	// Compute powers of τ, ατ, and βτ
 	taus := powers(tau, 2*N-1)
@@ -131,6 +150,21 @@ func VerifyPhase1(c0, c1 *Phase1, c ...*Phase1) error {
 	return nil
 }
 
+// VerifyPhase1Beacon checks that contribution is the beacon contribution of seed and nbIterations
+// on top of current (see ContributeWithBeacon). It recomputes the contribution, and doesn't
+// replace VerifyPhase1.
+func VerifyPhase1Beacon(current, contribution *Phase1, seed []byte, nbIterations int) error {
+	if nbIterations < 1 {
+		return errInvalidIterations
+	}
+	expected := current.clone()
+	expected.ContributeWithBeacon(seed, nbIterations)
+	if !bytes.Equal(expected.Hash, contribution.Hash) || !bytes.Equal(expected.Hash, contribution.hash()) {
+		return errors.New("couldn't verify the beacon contribution")
+	}
+	return nil
+}
+
 // verifyPhase1 checks that a contribution is based on a known previous Phase1 state.
 func verifyPhase1(current, contribution *Phase1) error {
 	// Compute R for τ, α, β
@@ -201,3 +235,18 @@ func (phase1 *Phase1) hash() []byte {
 	phase1.writeTo(sha)
 	return sha.Sum(nil)
 }
+
+func (phase1 *Phase1) clone() Phase1 {
+	r := Phase1{}
+	r.Parameters.G1.Tau = append(r.Parameters.G1.Tau, phase1.Parameters.G1.Tau...)
+	r.Parameters.G1.AlphaTau = append(r.Parameters.G1.AlphaTau, phase1.Parameters.G1.AlphaTau...)
+	r.Parameters.G1.BetaTau = append(r.Parameters.G1.BetaTau, phase1.Parameters.G1.BetaTau...)
+
+	r.Parameters.G2.Tau = append(r.Parameters.G2.Tau, phase1.Parameters.G2.Tau...)
+	r.Parameters.G2.Beta = phase1.Parameters.G2.Beta
+
+	r.PublicKeys = phase1.PublicKeys
+	r.Hash = append(r.Hash, phase1.Hash...)
+
+	return r
+}
@@ -17,10 +17,13 @@
 package mpcsetup
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"errors"
+	"fmt"
 	"math/big"
 
+	"github.com/consensys/gnark-crypto/ecc"
 	curve "github.com/consensys/gnark-crypto/ecc/bn254"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	"github.com/consensys/gnark/constraint"
@@ -30,31 +33,44 @@ import (
 type Phase2Evaluations struct {
 	G1 struct {
 		A, B, VKK []curve.G1Affine
+		CKK       [][]curve.G1Affine // CKK[i] are the bases of the private wires committed by the i-th commitment
 	}
 	G2 struct {
 		B []curve.G2Affine
 	}
+	PublicAndCommitmentCommitted [][]int // indexes of public/commitment committed variables
 }
 
 type Phase2 struct {
 	Parameters struct {
 		G1 struct {
-			Delta curve.G1Affine
-			L, Z  []curve.G1Affine
+			Delta    curve.G1Affine
+			L, Z     []curve.G1Affine
+			SigmaCKK [][]curve.G1Affine // σ·CKK, the commitment bases raised to the secret σ of the Pedersen keys
 		}
 		G2 struct {
-			Delta curve.G2Affine
+			Delta         curve.G2Affine
+			GRootSigmaNeg curve.G2Affine // -[1/σ]₂
 		}
 	}
-	PublicKey PublicKey
-	Hash      []byte
+	PublicKey      PublicKey
+	SigmaPublicKey PublicKey
+	Hash           []byte
 }
 
-func InitPhase2(r1cs *cs.R1CS, srs1 *Phase1) (Phase2, Phase2Evaluations) {
+// InitPhase2 initializes phase 2 of the MPC for the circuit r1cs from the result of phase 1. Like
+// InitPhase1, it is deterministic. The evaluations are needed by ExtractKeys.
+//
+// Phase 1 must be of the size of the domain of the circuit, the next power of two of its number
+// of constraints.
+func InitPhase2(r1cs *cs.R1CS, srs1 *Phase1) (Phase2, Phase2Evaluations, error) {
 	srs := srs1.Parameters
 	size := len(srs.G1.AlphaTau)
-	if size < r1cs.GetNbConstraints() {
-		panic("Number of constraints is larger than expected")
+	if domainSize := ecc.NextPowerOfTwo(uint64(r1cs.GetNbConstraints())); uint64(size) != domainSize {
+		return Phase2{}, Phase2Evaluations{}, fmt.Errorf("phase 1 has size %d, the domain of the circuit has size %d", size, domainSize)
+	}
+	if len(srs.G1.Tau) != 2*size-1 || len(srs.G1.BetaTau) != size || len(srs.G2.Tau) != size {
+		return Phase2{}, Phase2Evaluations{}, errors.New("malformed phase 1: number of powers of τ mismatch")
 	}
 
 	c2 := Phase2{}
@@ -153,43 +169,110 @@ func InitPhase2(r1cs *cs.R1CS, srs1 *Phase1) (Phase2, Phase2Evaluations) {
 	bitReverse(c2.Parameters.G1.Z)
 	c2.Parameters.G1.Z = c2.Parameters.G1.Z[:n-1]
 
-	// Evaluate L
-	nPrivate := internal + secret
-	c2.Parameters.G1.L = make([]curve.G1Affine, nPrivate)
-	evals.G1.VKK = make([]curve.G1Affine, public)
-	offset := public
+	// Evaluate L, VKK and CKK. As in groth16.Setup, a commitment is defined by a hint, so the
+	// prover considers it private, but the verifier injects its value, so it is public here. The
+	// private wires committed to go in the Pedersen commitment keys instead of L. γ is not part
+	// of the ceremony and is set to 1, so VKK and CKK aren't scaled.
+	commitmentInfo := r1cs.CommitmentInfo.(constraint.Groth16Commitments)
+	commitmentWires := commitmentInfo.CommitmentIndexes()
+	privateCommitted := commitmentInfo.GetPrivateCommitted()
+	nbPrivateCommittedWires := 0
+	for j := range privateCommitted {
+		nbPrivateCommittedWires += len(privateCommitted[j])
+	}
+
+	nbPublic := public + len(commitmentInfo)
+	nbPrivate := internal + secret - nbPrivateCommittedWires - len(commitmentInfo)
+	c2.Parameters.G1.L = make([]curve.G1Affine, 0, nbPrivate)
+	evals.G1.VKK = make([]curve.G1Affine, 0, nbPublic)
+	evals.G1.CKK = make([][]curve.G1Affine, len(commitmentInfo))
+	for j := range evals.G1.CKK {
+		evals.G1.CKK[j] = make([]curve.G1Affine, 0, len(privateCommitted[j]))
+	}
+	cI := make([]int, len(commitmentInfo)) // number of private committed wires seen so far for each commitment
+	nbCommitmentsSeen := 0
 	for i := 0; i < nWires; i++ {
 		var tmp curve.G1Affine
 		tmp.Add(&bA[i], &aB[i])
 		tmp.Add(&tmp, &C[i])
-		if i < public {
-			evals.G1.VKK[i].Set(&tmp)
-		} else {
-			c2.Parameters.G1.L[i-offset].Set(&tmp)
+
+		commitment := -1 // index of the commitment that commits to this variable as a private value
+		isCommitment := false
+		if i >= public {
+			if nbCommitmentsSeen < len(commitmentWires) && commitmentWires[nbCommitmentsSeen] == i {
+				isCommitment = true
+				nbCommitmentsSeen++
+			}
+			for j := range commitmentInfo { // does commitment j commit to i?
+				if cI[j] < len(privateCommitted[j]) && privateCommitted[j][cI[j]] == i {
+					commitment = j
+					cI[j]++
+					break // frontend guarantees that no private variable is committed to more than once
+				}
+			}
+		}
+
+		switch {
+		case i < public || isCommitment:
+			evals.G1.VKK = append(evals.G1.VKK, tmp)
+		case commitment != -1:
+			evals.G1.CKK[commitment] = append(evals.G1.CKK[commitment], tmp)
+		default:
+			c2.Parameters.G1.L = append(c2.Parameters.G1.L, tmp)
 		}
 	}
-	// Set δ public key
-	var delta fr.Element
+	evals.PublicAndCommitmentCommitted = commitmentInfo.GetPublicAndCommitmentCommitted(commitmentWires, public)
+
+	// Prepare default commitment keys: σ = 1
+	c2.Parameters.G1.SigmaCKK = make([][]curve.G1Affine, len(evals.G1.CKK))
+	for j := range evals.G1.CKK {
+		c2.Parameters.G1.SigmaCKK[j] = append([]curve.G1Affine{}, evals.G1.CKK[j]...)
+	}
+	c2.Parameters.G2.GRootSigmaNeg.Neg(&g2)
+
+	// Set δ and σ public keys
+	var delta, sigma fr.Element
 	delta.SetOne()
-	c2.PublicKey = newPublicKey(delta, nil, 1)
+	sigma.SetOne()
+	c2.PublicKey = newPublicKey(delta, nil, 1, sampleOne)
+	c2.SigmaPublicKey = newPublicKey(sigma, nil, 2, sampleOne)
 
 	// Hash initial contribution
 	c2.Hash = c2.hash()
-	return c2, evals
+	return c2, evals, nil
 }
 
+// Contribute contributes randomness to the phase2 object. This mutates c.
 func (c *Phase2) Contribute() {
+	c.contribute(sampleRandom)
+}
+
+// ContributeWithBeacon makes a contribution whose secret is derived from a random beacon: a
+// public seed nobody could predict before the previous contributions were made (e.g. a future
+// block hash), hashed nbIterations times. It is usually the last contribution of the phase, and
+// anyone can check it with VerifyPhase2Beacon. This mutates c. It panics if
+// nbIterations < 1.
+func (c *Phase2) ContributeWithBeacon(seed []byte, nbIterations int) {
+	c.contribute(newBeaconSampler(seed, nbIterations))
+}
+
+// ContributionHash returns the hash of the last contribution, which the next one builds on.
+func (c *Phase2) ContributionHash() []byte {
+	return c.Hash
+}
+
+func (c *Phase2) contribute(sample func(*fr.Element)) {
 	// Sample toxic δ
 	var delta, deltaInv fr.Element
 	var deltaBI, deltaInvBI big.Int
-	delta.SetRandom()
+	sample(&delta)
 	deltaInv.Inverse(&delta)
 
 	delta.BigInt(&deltaBI)
 	deltaInv.BigInt(&deltaInvBI)
 
 	// Set δ public key
-	c.PublicKey = newPublicKey(delta, c.Hash, 1)
+	c.PublicKey = newPublicKey(delta, c.Hash, 1, sample)
 
 	// Update δ
 	c.Parameters.G1.Delta.ScalarMultiplication(&c.Parameters.G1.Delta, &deltaBI)
@@ -205,6 +288,26 @@ func (c *Phase2) Contribute() {
 		c.Parameters.G1.L[i].ScalarMultiplication(&c.Parameters.G1.L[i], &deltaInvBI)
 	}
 
+	// Sample toxic σ of the commitment keys
+	var sigma, sigmaInv fr.Element
+	var sigmaBI, sigmaInvBI big.Int
+	sample(&sigma)
+	sigmaInv.Inverse(&sigma)
+
+	sigma.BigInt(&sigmaBI)
+	sigmaInv.BigInt(&sigmaInvBI)
+
+	// Set σ public key
+	c.SigmaPublicKey = newPublicKey(sigma, c.Hash, 2, sample)
+
+	// Update the commitment keys using σ and -[1/σ]₂ using σ⁻¹
+	for i := range c.Parameters.G1.SigmaCKK {
+		for j := range c.Parameters.G1.SigmaCKK[i] {
+			c.Parameters.G1.SigmaCKK[i][j].ScalarMultiplication(&c.Parameters.G1.SigmaCKK[i][j], &sigmaBI)
+		}
+	}
+	c.Parameters.G2.GRootSigmaNeg.ScalarMultiplication(&c.Parameters.G2.GRootSigmaNeg, &sigmaInvBI)
+
 	// 4. Hash contribution
 	c.Hash = c.hash()
 }
@@ -219,6 +322,21 @@ func VerifyPhase2(c0, c1 *Phase2, c ...*Phase2) error {
 	return nil
 }
 
+// VerifyPhase2Beacon checks that contribution is the beacon contribution of seed and nbIterations
+// on top of current (see ContributeWithBeacon). It recomputes the contribution, and doesn't
+// replace VerifyPhase2.
+func VerifyPhase2Beacon(current, contribution *Phase2, seed []byte, nbIterations int) error {
+	if nbIterations < 1 {
+		return errInvalidIterations
+	}
+	expected := current.clone()
+	expected.ContributeWithBeacon(seed, nbIterations)
+	if !bytes.Equal(expected.Hash, contribution.Hash) || !bytes.Equal(expected.Hash, contribution.hash()) {
+		return errors.New("couldn't verify the beacon contribution")
+	}
+	return nil
+}
+
 func verifyPhase2(current, contribution *Phase2) error {
 	// Compute R for δ
 	deltaR := genR(contribution.PublicKey.SG, contribution.PublicKey.SXG, current.Hash[:], 1)
@@ -246,6 +364,34 @@ func verifyPhase2(current, contribution *Phase2) error {
 		return errors.New("couldn't verify valid updates of L using δ⁻¹")
 	}
 
+	// Compute R for σ
+	sigmaR := genR(contribution.SigmaPublicKey.SG, contribution.SigmaPublicKey.SXG, current.Hash[:], 2)
+
+	// Check for knowledge of σ
+	if !sameRatio(contribution.SigmaPublicKey.SG, contribution.SigmaPublicKey.SXG, contribution.SigmaPublicKey.XR, sigmaR) {
+		return errors.New("couldn't verify knowledge of σ")
+	}
+
+	// Check for valid updates of the commitment keys using σ
+	if !sameRatio(contribution.SigmaPublicKey.SXG, contribution.SigmaPublicKey.SG, contribution.Parameters.G2.GRootSigmaNeg, current.Parameters.G2.GRootSigmaNeg) {
+		return errors.New("couldn't verify that -[1/σ]₂ is based on previous contribution")
+	}
+	if len(contribution.Parameters.G1.SigmaCKK) != len(current.Parameters.G1.SigmaCKK) {
+		return errors.New("number of commitment keys mismatch")
+	}
+	for i := range contribution.Parameters.G1.SigmaCKK {
+		if len(contribution.Parameters.G1.SigmaCKK[i]) != len(current.Parameters.G1.SigmaCKK[i]) {
+			return errors.New("commitment key size mismatch")
+		}
+		if len(contribution.Parameters.G1.SigmaCKK[i]) == 0 {
+			continue
+		}
+		S, prevS := merge(contribution.Parameters.G1.SigmaCKK[i], current.Parameters.G1.SigmaCKK[i])
+		if !sameRatio(S, prevS, contribution.Parameters.G2.GRootSigmaNeg, current.Parameters.G2.GRootSigmaNeg) {
+			return errors.New("couldn't verify valid updates of the commitment keys using σ")
+		}
+	}
+
 	// Check hash of the contribution
 	h := contribution.hash()
 	for i := 0; i < len(h); i++ {
@@ -262,3 +408,21 @@ func (c *Phase2) hash() []byte {
 	c.writeTo(sha)
 	return sha.Sum(nil)
 }
+
+func (c *Phase2) clone() Phase2 {
+	r := Phase2{}
+	r.Parameters.G1.Delta = c.Parameters.G1.Delta
+	r.Parameters.G1.L = append(r.Parameters.G1.L, c.Parameters.G1.L...)
+	r.Parameters.G1.Z = append(r.Parameters.G1.Z, c.Parameters.G1.Z...)
+	r.Parameters.G1.SigmaCKK = make([][]curve.G1Affine, len(c.Parameters.G1.SigmaCKK))
+	for i := range c.Parameters.G1.SigmaCKK {
+		r.Parameters.G1.SigmaCKK[i] = append(r.Parameters.G1.SigmaCKK[i], c.Parameters.G1.SigmaCKK[i]...)
+	}
+	r.Parameters.G2.Delta = c.Parameters.G2.Delta
+	r.Parameters.G2.GRootSigmaNeg = c.Parameters.G2.GRootSigmaNeg
+	r.PublicKey = c.PublicKey
+	r.SigmaPublicKey = c.SigmaPublicKey
+	r.Hash = append(r.Hash, c.Hash...)
+
+	return r
+}
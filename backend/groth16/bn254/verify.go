@@ -17,6 +17,7 @@
 package groth16
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"github.com/consensys/gnark-crypto/ecc"
@@ -24,9 +25,11 @@ import (
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr/pedersen"
 	"github.com/consensys/gnark-crypto/utils"
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/logger"
 	"io"
+	"math/big"
 	"text/template"
 	"time"
 )
@@ -63,25 +66,9 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector) error {
 		close(chDone)
 	}()
 
-	maxNbPublicCommitted := 0
-	for _, s := range vk.PublicAndCommitmentCommitted { // iterate over commitments
-		maxNbPublicCommitted = utils.Max(maxNbPublicCommitted, len(s))
-	}
-	commitmentsSerialized := make([]byte, len(vk.PublicAndCommitmentCommitted)*fr.Bytes)
-	commitmentPrehashSerialized := make([]byte, curve.SizeOfG1AffineUncompressed+maxNbPublicCommitted*fr.Bytes)
-	for i := range vk.PublicAndCommitmentCommitted { // solveCommitmentWire
-		copy(commitmentPrehashSerialized, proof.Commitments[i].Marshal())
-		offset := curve.SizeOfG1AffineUncompressed
-		for j := range vk.PublicAndCommitmentCommitted[i] {
-			copy(commitmentPrehashSerialized[offset:], publicWitness[vk.PublicAndCommitmentCommitted[i][j]-1].Marshal())
-			offset += fr.Bytes
-		}
-		if res, err := fr.Hash(commitmentPrehashSerialized[:offset], []byte(constraint.CommitmentDst), 1); err != nil {
-			return err
-		} else {
-			publicWitness = append(publicWitness, res[0])
-			copy(commitmentsSerialized[i*fr.Bytes:], res[0].Marshal())
-		}
+	publicWitness, commitmentsSerialized, err := solveCommitmentWires(proof, vk, publicWitness)
+	if err != nil {
+		return err
 	}
 
 	if folded, err := pedersen.FoldCommitments(proof.Commitments, commitmentsSerialized); err != nil {
@@ -125,6 +112,233 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector) error {
 	return nil
 }
 
+// solveCommitmentWires returns the public witness extended with the values of the commitment
+// wires, and the serialized values used to fold the commitments of the proof.
+func solveCommitmentWires(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector) (fr.Vector, []byte, error) {
+	maxNbPublicCommitted := 0
+	for _, s := range vk.PublicAndCommitmentCommitted { // iterate over commitments
+		maxNbPublicCommitted = utils.Max(maxNbPublicCommitted, len(s))
+	}
+	commitmentsSerialized := make([]byte, len(vk.PublicAndCommitmentCommitted)*fr.Bytes)
+	commitmentPrehashSerialized := make([]byte, curve.SizeOfG1AffineUncompressed+maxNbPublicCommitted*fr.Bytes)
+	for i := range vk.PublicAndCommitmentCommitted { // solveCommitmentWire
+		copy(commitmentPrehashSerialized, proof.Commitments[i].Marshal())
+		offset := curve.SizeOfG1AffineUncompressed
+		for j := range vk.PublicAndCommitmentCommitted[i] {
+			copy(commitmentPrehashSerialized[offset:], publicWitness[vk.PublicAndCommitmentCommitted[i][j]-1].Marshal())
+			offset += fr.Bytes
+		}
+		if res, err := fr.Hash(commitmentPrehashSerialized[:offset], []byte(constraint.CommitmentDst), 1); err != nil {
+			return nil, nil, err
+		} else {
+			publicWitness = append(publicWitness, res[0])
+			copy(commitmentsSerialized[i*fr.Bytes:], res[0].Marshal())
+		}
+	}
+	return publicWitness, commitmentsSerialized, nil
+}
+
+// BatchVerify verifies the proofs of the public witnesses publicWitnesses[i] for the same
+// VerifyingKey. The verification equations of the proofs, and the checks of their
+// commitments, are combined with random scalars in a single multi-pairing check. If it fails,
+// the proofs are verified one by one, and a *backend.BatchVerificationError reports the
+// invalid ones.
+func BatchVerify(proofs []*Proof, vk *VerifyingKey, publicWitnesses []fr.Vector) error {
+	if len(proofs) != len(publicWitnesses) {
+		return fmt.Errorf("got %d proofs but %d public witnesses", len(proofs), len(publicWitnesses))
+	}
+	if len(proofs) == 0 {
+		return nil
+	}
+	log := logger.Logger().With().Str("curve", vk.CurveID().String()).Str("backend", "groth16").Int("nbProofs", len(proofs)).Logger()
+	start := time.Now()
+
+	err := batchVerify(proofs, vk, publicWitnesses)
+	if err == nil {
+		log.Debug().Dur("took", time.Since(start)).Msg("batch verifier done")
+		return nil
+	}
+	log.Debug().Err(err).Msg("batch verification failed, verifying the proofs one by one")
+
+	bErr := &backend.BatchVerificationError{NbProofs: len(proofs)}
+	for i := range proofs {
+		if errProof := Verify(proofs[i], vk, publicWitnesses[i]); errProof != nil {
+			bErr.Invalid = append(bErr.Invalid, i)
+			bErr.Errs = append(bErr.Errs, errProof)
+		}
+	}
+	if len(bErr.Invalid) == 0 {
+		return err
+	}
+	return bErr
+}
+
+// batchVerify checks the random linear combination of the verification equations of the
+// proofs, with r₀ = 1:
+//
+//	∏ e(rᵢ⋅Arᵢ, Bsᵢ) ⋅ e(Σ rᵢ⋅Krsᵢ, -[δ]₂) ⋅ e(Σ rᵢ⋅kSumᵢ, -[γ]₂) ⋅ e(-(Σ rᵢ)⋅[α]₁, [β]₂) == 1
+//
+// With commitments, the random linear combination of the proofs of knowledge of the commitments,
+// scaled by a random s, is checked in the same multi-pairing:
+//
+//	e(s⋅Σ rᵢ⋅foldedᵢ, g) ⋅ e(s⋅Σ rᵢ⋅Pokᵢ, -[1/σ]g) == 1
+func batchVerify(proofs []*Proof, vk *VerifyingKey, publicWitnesses []fr.Vector) error {
+	n := len(proofs)
+	nbPublicVars := len(vk.G1.K) - len(vk.PublicAndCommitmentCommitted)
+
+	r := make(fr.Vector, n)
+	r[0].SetOne()
+	for i := 1; i < n; i++ {
+		if _, err := r[i].SetRandom(); err != nil {
+			return err
+		}
+	}
+	var rSum fr.Element
+	for i := range r {
+		rSum.Add(&rSum, &r[i])
+	}
+
+	p := make([]curve.G1Affine, n, n+5)
+	q := make([]curve.G2Affine, n, n+5)
+	krs := make([]curve.G1Affine, n)
+	folded := make([]curve.G1Affine, n)
+	poks := make([]curve.G1Affine, n)
+	var commitments []curve.G1Affine
+	var rCommitments fr.Vector
+
+	// combined public witness, with the commitment wires: Σ rᵢ⋅xᵢ
+	combined := make(fr.Vector, len(vk.G1.K)-1)
+	var tmp fr.Element
+	for i := range proofs {
+		proof := proofs[i]
+		if len(publicWitnesses[i]) != nbPublicVars-1 {
+			return fmt.Errorf("proof #%d: invalid witness size, got %d, expected %d (public - ONE_WIRE)", i, len(publicWitnesses[i]), nbPublicVars-1)
+		}
+		if !proof.isValid() {
+			return fmt.Errorf("proof #%d: %w", i, errCorrectSubgroupCheckFailed)
+		}
+		if len(proof.Commitments) != len(vk.PublicAndCommitmentCommitted) {
+			return fmt.Errorf("proof #%d: got %d commitments, expected %d", i, len(proof.Commitments), len(vk.PublicAndCommitmentCommitted))
+		}
+		// the combinations below don't remove the small order components of the commitments
+		if len(proof.Commitments) != 0 && !proof.CommitmentPok.IsInSubGroup() {
+			return fmt.Errorf("proof #%d: %w", i, errCorrectSubgroupCheckFailed)
+		}
+		for j := range proof.Commitments {
+			if !proof.Commitments[j].IsInSubGroup() {
+				return fmt.Errorf("proof #%d: %w", i, errCorrectSubgroupCheckFailed)
+			}
+		}
+
+		// don't extend the witness of the caller
+		publicWitness := make(fr.Vector, len(publicWitnesses[i]), len(vk.G1.K)-1)
+		copy(publicWitness, publicWitnesses[i])
+		publicWitness, commitmentsSerialized, err := solveCommitmentWires(proof, vk, publicWitness)
+		if err != nil {
+			return err
+		}
+		if len(vk.PublicAndCommitmentCommitted) != 0 {
+			if folded[i], err = pedersen.FoldCommitments(proof.Commitments, commitmentsSerialized); err != nil {
+				return err
+			}
+			poks[i] = proof.CommitmentPok
+		}
+		for j := range publicWitness {
+			tmp.Mul(&r[i], &publicWitness[j])
+			combined[j].Add(&combined[j], &tmp)
+		}
+		for j := range proof.Commitments {
+			commitments = append(commitments, proof.Commitments[j])
+			rCommitments = append(rCommitments, r[i])
+		}
+
+		var bi big.Int
+		p[i].ScalarMultiplication(&proof.Ar, r[i].BigInt(&bi))
+		q[i] = proof.Bs
+		krs[i] = proof.Krs
+	}
+
+	// the proofs of knowledge of the commitments
+	if len(vk.PublicAndCommitmentCommitted) != 0 {
+		var s fr.Element
+		if _, err := s.SetRandom(); err != nil {
+			return err
+		}
+		sr := make(fr.Vector, n)
+		for i := range sr {
+			sr[i].Mul(&s, &r[i])
+		}
+		var foldedSum, pokSum curve.G1Affine
+		if _, err := foldedSum.MultiExp(folded, sr, ecc.MultiExpConfig{}); err != nil {
+			return err
+		}
+		if _, err := pokSum.MultiExp(poks, sr, ecc.MultiExpConfig{}); err != nil {
+			return err
+		}
+		g, gRootSigmaNeg, err := commitmentKeyG2(&vk.CommitmentKey)
+		if err != nil {
+			return err
+		}
+		p = append(p, foldedSum, pokSum)
+		q = append(q, g, gRootSigmaNeg)
+	}
+
+	// Σ rᵢ⋅Krsᵢ
+	var krsSum curve.G1Affine
+	if _, err := krsSum.MultiExp(krs, r, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+
+	// Σ rᵢ⋅kSumᵢ = (Σ rᵢ)⋅[K₀]₁ + Σⱼ (Σ rᵢ⋅xᵢⱼ)⋅[Kⱼ]₁ + Σ rᵢ⋅Commitmentsᵢ
+	var kSum, jac curve.G1Jac
+	if _, err := kSum.MultiExp(vk.G1.K[1:], combined, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	var bi big.Int
+	jac.FromAffine(&vk.G1.K[0])
+	jac.ScalarMultiplication(&jac, rSum.BigInt(&bi))
+	kSum.AddAssign(&jac)
+	if len(commitments) != 0 {
+		if _, err := jac.MultiExp(commitments, rCommitments, ecc.MultiExpConfig{}); err != nil {
+			return err
+		}
+		kSum.AddAssign(&jac)
+	}
+	var kSumAff, alpha curve.G1Affine
+	kSumAff.FromJacobian(&kSum)
+
+	// -(Σ rᵢ)⋅[α]₁
+	rSum.Neg(&rSum)
+	alpha.ScalarMultiplication(&vk.G1.Alpha, rSum.BigInt(&bi))
+
+	p = append(p, krsSum, kSumAff, alpha)
+	q = append(q, vk.G2.deltaNeg, vk.G2.gammaNeg, vk.G2.Beta)
+	ok, err := curve.PairingCheck(p, q)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errPairingCheckFailed
+	}
+	return nil
+}
+
+// commitmentKeyG2 returns g and -[1/σ]g, the points of the verifying key of the Pedersen
+// commitments. The fields of the key are not exported, so they are decoded from its serialized
+// form.
+func commitmentKeyG2(vk *pedersen.VerifyingKey) (g, gRootSigmaNeg curve.G2Affine, err error) {
+	var buf bytes.Buffer
+	if _, err = vk.WriteTo(&buf); err != nil {
+		return
+	}
+	dec := curve.NewDecoder(&buf)
+	if err = dec.Decode(&g); err != nil {
+		return
+	}
+	err = dec.Decode(&gRootSigmaNeg)
+	return
+}
+
 // ExportSolidity writes a solidity Verifier contract on provided writer.
 // This is an experimental feature and gnark solidity generator as not been thoroughly tested.
 //
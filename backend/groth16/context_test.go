@@ -0,0 +1,68 @@
+package groth16_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/internal/backend/circuits"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProveContext(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &circuits.Cube{WithCommitment: true})
+	assert.NoError(err)
+	pk, _, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&circuits.Cube{X: 3, Y: 27}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// all the phases are reported
+	var lock sync.Mutex
+	var phases []string
+	progress := func(phase string, _ time.Duration) {
+		lock.Lock()
+		defer lock.Unlock()
+		phases = append(phases, phase)
+	}
+	_, err = groth16.Prove(ccs, pk, w, backend.WithContext(context.Background()), backend.WithProgress(progress))
+	assert.NoError(err)
+	assert.ElementsMatch([]string{"solve", "commitments", "fft", "msm A", "msm B1", "msm B2", "msm K", "msm Z"}, phases)
+
+	// canceled before proving
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = groth16.Prove(ccs, pk, w, backend.WithContext(ctx))
+	assert.ErrorIs(err, context.Canceled)
+
+	// canceled after solving
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	phases = nil
+	_, err = groth16.Prove(ccs, pk, w, backend.WithContext(ctx), backend.WithProgress(func(phase string, took time.Duration) {
+		progress(phase, took)
+		if phase == "solve" {
+			cancel()
+		}
+	}))
+	assert.ErrorIs(err, context.Canceled)
+	assert.Equal([]string{"solve"}, phases)
+
+	// canceled during the multi-exponentiations, which run concurrently
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	_, err = groth16.Prove(ccs, pk, w, backend.WithContext(ctx), backend.WithProgress(func(phase string, _ time.Duration) {
+		if phase == "msm A" {
+			cancel()
+		}
+	}))
+	assert.ErrorIs(err, context.Canceled)
+}
@@ -0,0 +1,59 @@
+package groth16_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/internal/backend/circuits"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProveWithDiskKey(t *testing.T) {
+	for _, curve := range getCurves() {
+		t.Run(curve.String(), func(t *testing.T) {
+			assert := require.New(t)
+
+			ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, &circuits.Cube{WithCommitment: true})
+			assert.NoError(err)
+			pk, vk, err := groth16.Setup(ccs)
+			assert.NoError(err)
+
+			f, err := os.Create(filepath.Join(t.TempDir(), "pk"))
+			assert.NoError(err)
+			defer f.Close()
+			_, err = pk.WriteRawTo(f)
+			assert.NoError(err)
+
+			dpk, err := groth16.NewDiskProvingKey(curve, f, 2)
+			assert.NoError(err)
+			assert.Equal(curve, dpk.CurveID())
+
+			w, err := frontend.NewWitness(&circuits.Cube{X: 3, Y: 27}, curve.ScalarField())
+			assert.NoError(err)
+			proof, err := groth16.ProveWithDiskKey(ccs, dpk, w)
+			assert.NoError(err)
+			pw, err := w.Public()
+			assert.NoError(err)
+			assert.NoError(groth16.Verify(proof, vk, pw))
+
+			// on error, the results are nil interfaces, not typed nil pointers
+			w, err = frontend.NewWitness(&circuits.Cube{X: 3, Y: 28}, curve.ScalarField())
+			assert.NoError(err)
+			proof, err = groth16.ProveWithDiskKey(ccs, dpk, w)
+			assert.Error(err)
+			assert.True(proof == nil)
+			dpk, err = groth16.NewDiskProvingKey(curve, bytes.NewReader(nil), 2)
+			assert.Error(err)
+			assert.True(dpk == nil)
+		})
+	}
+
+	_, err := groth16.NewDiskProvingKey(ecc.SECP256K1, nil, 2)
+	require.Error(t, err)
+}
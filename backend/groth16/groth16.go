@@ -20,6 +20,7 @@
 package groth16
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -159,6 +160,45 @@ func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness) error {
 	}
 }
 
+// BatchVerify verifies the proofs of the public witnesses for the same VerifyingKey with a
+// single multi-pairing check, which is much faster than verifying them one by one. If the
+// batch contains invalid proofs, the returned error is a *backend.BatchVerificationError
+// reporting them.
+func BatchVerify(proofs []Proof, vk VerifyingKey, publicWitnesses []witness.Witness) error {
+	switch _vk := vk.(type) {
+	case *groth16_bls12377.VerifyingKey:
+		return backend.BatchVerify(proofs, publicWitnesses, func(proofs []*groth16_bls12377.Proof, publicWitnesses []fr_bls12377.Vector) error {
+			return groth16_bls12377.BatchVerify(proofs, _vk, publicWitnesses)
+		})
+	case *groth16_bls12381.VerifyingKey:
+		return backend.BatchVerify(proofs, publicWitnesses, func(proofs []*groth16_bls12381.Proof, publicWitnesses []fr_bls12381.Vector) error {
+			return groth16_bls12381.BatchVerify(proofs, _vk, publicWitnesses)
+		})
+	case *groth16_bn254.VerifyingKey:
+		return backend.BatchVerify(proofs, publicWitnesses, func(proofs []*groth16_bn254.Proof, publicWitnesses []fr_bn254.Vector) error {
+			return groth16_bn254.BatchVerify(proofs, _vk, publicWitnesses)
+		})
+	case *groth16_bw6761.VerifyingKey:
+		return backend.BatchVerify(proofs, publicWitnesses, func(proofs []*groth16_bw6761.Proof, publicWitnesses []fr_bw6761.Vector) error {
+			return groth16_bw6761.BatchVerify(proofs, _vk, publicWitnesses)
+		})
+	case *groth16_bls24317.VerifyingKey:
+		return backend.BatchVerify(proofs, publicWitnesses, func(proofs []*groth16_bls24317.Proof, publicWitnesses []fr_bls24317.Vector) error {
+			return groth16_bls24317.BatchVerify(proofs, _vk, publicWitnesses)
+		})
+	case *groth16_bls24315.VerifyingKey:
+		return backend.BatchVerify(proofs, publicWitnesses, func(proofs []*groth16_bls24315.Proof, publicWitnesses []fr_bls24315.Vector) error {
+			return groth16_bls24315.BatchVerify(proofs, _vk, publicWitnesses)
+		})
+	case *groth16_bw6633.VerifyingKey:
+		return backend.BatchVerify(proofs, publicWitnesses, func(proofs []*groth16_bw6633.Proof, publicWitnesses []fr_bw6633.Vector) error {
+			return groth16_bw6633.BatchVerify(proofs, _vk, publicWitnesses)
+		})
+	default:
+		panic("unrecognized R1CS curve type")
+	}
+}
+
 // Prove runs the groth16.Prove algorithm.
 //
 // if the force flag is set:
@@ -195,6 +235,121 @@ func Prove(r1cs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.
 	}
 }
 
+// DiskProvingKey represents a Groth16 ProvingKey whose large slices of points stay on disk, and
+// are read by chunks during the proof computations (see NewDiskProvingKey).
+//
+// it's underlying implementation is strongly typed with the curve (see gnark/internal/backend)
+type DiskProvingKey interface {
+	CurveID() ecc.ID
+}
+
+// NewDiskProvingKey reads the elements of a ProvingKey encoded with WriteRawTo in r, except for
+// its large slices of points, which are read by chunks of at most chunkSize points during each
+// proof computation with ProveWithDiskKey, bounding the memory used by the key.
+//
+// r must stay readable while the key is used, and can be read concurrently. As with
+// UnsafeReadFrom, the points aren't checked to be on the curve or in the correct subgroup.
+func NewDiskProvingKey(curveID ecc.ID, r io.ReaderAt, chunkSize int) (DiskProvingKey, error) {
+	switch curveID {
+	case ecc.BLS12_377:
+		pk, err := groth16_bls12377.NewDiskProvingKey(r, chunkSize)
+		if err != nil {
+			return nil, err
+		}
+		return pk, nil
+	case ecc.BLS12_381:
+		pk, err := groth16_bls12381.NewDiskProvingKey(r, chunkSize)
+		if err != nil {
+			return nil, err
+		}
+		return pk, nil
+	case ecc.BN254:
+		pk, err := groth16_bn254.NewDiskProvingKey(r, chunkSize)
+		if err != nil {
+			return nil, err
+		}
+		return pk, nil
+	case ecc.BW6_761:
+		pk, err := groth16_bw6761.NewDiskProvingKey(r, chunkSize)
+		if err != nil {
+			return nil, err
+		}
+		return pk, nil
+	case ecc.BLS24_317:
+		pk, err := groth16_bls24317.NewDiskProvingKey(r, chunkSize)
+		if err != nil {
+			return nil, err
+		}
+		return pk, nil
+	case ecc.BLS24_315:
+		pk, err := groth16_bls24315.NewDiskProvingKey(r, chunkSize)
+		if err != nil {
+			return nil, err
+		}
+		return pk, nil
+	case ecc.BW6_633:
+		pk, err := groth16_bw6633.NewDiskProvingKey(r, chunkSize)
+		if err != nil {
+			return nil, err
+		}
+		return pk, nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %s", curveID)
+	}
+}
+
+// ProveWithDiskKey runs the groth16.Prove algorithm with a proving key read from disk. The proof
+// is equivalent to one computed by Prove with the key in memory: it is randomized in the same way,
+// and verifies with the same VerifyingKey.
+func ProveWithDiskKey(r1cs constraint.ConstraintSystem, pk DiskProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (Proof, error) {
+	switch _r1cs := r1cs.(type) {
+	case *cs_bls12377.R1CS:
+		proof, err := groth16_bls12377.ProveWithDiskKey(_r1cs, pk.(*groth16_bls12377.DiskProvingKey), fullWitness, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return proof, nil
+	case *cs_bls12381.R1CS:
+		proof, err := groth16_bls12381.ProveWithDiskKey(_r1cs, pk.(*groth16_bls12381.DiskProvingKey), fullWitness, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return proof, nil
+	case *cs_bn254.R1CS:
+		proof, err := groth16_bn254.ProveWithDiskKey(_r1cs, pk.(*groth16_bn254.DiskProvingKey), fullWitness, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return proof, nil
+	case *cs_bw6761.R1CS:
+		proof, err := groth16_bw6761.ProveWithDiskKey(_r1cs, pk.(*groth16_bw6761.DiskProvingKey), fullWitness, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return proof, nil
+	case *cs_bls24317.R1CS:
+		proof, err := groth16_bls24317.ProveWithDiskKey(_r1cs, pk.(*groth16_bls24317.DiskProvingKey), fullWitness, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return proof, nil
+	case *cs_bls24315.R1CS:
+		proof, err := groth16_bls24315.ProveWithDiskKey(_r1cs, pk.(*groth16_bls24315.DiskProvingKey), fullWitness, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return proof, nil
+	case *cs_bw6633.R1CS:
+		proof, err := groth16_bw6633.ProveWithDiskKey(_r1cs, pk.(*groth16_bw6633.DiskProvingKey), fullWitness, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return proof, nil
+	default:
+		panic("unrecognized R1CS curve type")
+	}
+}
+
 // Setup runs groth16.Setup with provided R1CS and outputs a key pair associated with the circuit.
 //
 // Note that careful consideration must be given to this step in production environment.
@@ -0,0 +1,374 @@
+// Package mpcsetup runs the multi-party computation ceremony producing the Groth16 keys of a
+// circuit, on any supported curve. It dispatches to the curve-specific packages
+// backend/groth16/<curve>/mpcsetup, and records the ceremony in a Transcript.
+//
+// # See also
+//
+// https://eprint.iacr.org/2017/1050.pdf
+package mpcsetup
+
+import (
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/constraint"
+	cs_bls12377 "github.com/consensys/gnark/constraint/bls12-377"
+	cs_bls12381 "github.com/consensys/gnark/constraint/bls12-381"
+	cs_bls24315 "github.com/consensys/gnark/constraint/bls24-315"
+	cs_bls24317 "github.com/consensys/gnark/constraint/bls24-317"
+	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
+	cs_bw6633 "github.com/consensys/gnark/constraint/bw6-633"
+	cs_bw6761 "github.com/consensys/gnark/constraint/bw6-761"
+
+	mpcsetup_bls12377 "github.com/consensys/gnark/backend/groth16/bls12-377/mpcsetup"
+	mpcsetup_bls12381 "github.com/consensys/gnark/backend/groth16/bls12-381/mpcsetup"
+	mpcsetup_bls24315 "github.com/consensys/gnark/backend/groth16/bls24-315/mpcsetup"
+	mpcsetup_bls24317 "github.com/consensys/gnark/backend/groth16/bls24-317/mpcsetup"
+	mpcsetup_bn254 "github.com/consensys/gnark/backend/groth16/bn254/mpcsetup"
+	mpcsetup_bw6633 "github.com/consensys/gnark/backend/groth16/bw6-633/mpcsetup"
+	mpcsetup_bw6761 "github.com/consensys/gnark/backend/groth16/bw6-761/mpcsetup"
+)
+
+// Phase1 is the state of phase 1 of the ceremony, the "powers of τ", which doesn't depend on the
+// circuit.
+//
+// it's underlying implementation is curve specific (see backend/groth16/<curve>/mpcsetup)
+type Phase1 interface {
+	io.WriterTo
+	io.ReaderFrom
+
+	// Contribute contributes randomness to the state.
+	Contribute()
+
+	// ContributeWithBeacon makes a deterministic contribution from a random beacon.
+	ContributeWithBeacon(seed []byte, nbIterations int)
+
+	// ContributionHash returns the hash of the last contribution.
+	ContributionHash() []byte
+}
+
+// Phase2 is the state of phase 2 of the ceremony, which is specific to a circuit.
+//
+// it's underlying implementation is curve specific (see backend/groth16/<curve>/mpcsetup)
+type Phase2 interface {
+	io.WriterTo
+	io.ReaderFrom
+
+	// Contribute contributes randomness to the state.
+	Contribute()
+
+	// ContributeWithBeacon makes a deterministic contribution from a random beacon.
+	ContributeWithBeacon(seed []byte, nbIterations int)
+
+	// ContributionHash returns the hash of the last contribution.
+	ContributionHash() []byte
+}
+
+// Phase2Evaluations holds the evaluations of the circuit computed by InitPhase2, needed by
+// ExtractKeys.
+//
+// it's underlying implementation is curve specific (see backend/groth16/<curve>/mpcsetup)
+type Phase2Evaluations interface {
+	io.WriterTo
+	io.ReaderFrom
+}
+
+// InitPhase1 returns the initial state of phase 1, for circuits of up to 2ᵖᵒʷᵉʳ constraints.
+func InitPhase1(curveID ecc.ID, power int) Phase1 {
+	switch curveID {
+	case ecc.BN254:
+		srs1 := mpcsetup_bn254.InitPhase1(power)
+		return &srs1
+	case ecc.BLS12_377:
+		srs1 := mpcsetup_bls12377.InitPhase1(power)
+		return &srs1
+	case ecc.BLS12_381:
+		srs1 := mpcsetup_bls12381.InitPhase1(power)
+		return &srs1
+	case ecc.BW6_761:
+		srs1 := mpcsetup_bw6761.InitPhase1(power)
+		return &srs1
+	case ecc.BLS24_317:
+		srs1 := mpcsetup_bls24317.InitPhase1(power)
+		return &srs1
+	case ecc.BLS24_315:
+		srs1 := mpcsetup_bls24315.InitPhase1(power)
+		return &srs1
+	case ecc.BW6_633:
+		srs1 := mpcsetup_bw6633.InitPhase1(power)
+		return &srs1
+	default:
+		panic("not implemented")
+	}
+}
+
+// VerifyPhase1 checks that each contribution of phase 1 is based on the previous one.
+func VerifyPhase1(c0, c1 Phase1, c ...Phase1) error {
+	contribs := append([]Phase1{c0, c1}, c...)
+	switch c0.(type) {
+	case *mpcsetup_bn254.Phase1:
+		return verifyAll(mpcsetup_bn254.VerifyPhase1, contribs)
+	case *mpcsetup_bls12377.Phase1:
+		return verifyAll(mpcsetup_bls12377.VerifyPhase1, contribs)
+	case *mpcsetup_bls12381.Phase1:
+		return verifyAll(mpcsetup_bls12381.VerifyPhase1, contribs)
+	case *mpcsetup_bw6761.Phase1:
+		return verifyAll(mpcsetup_bw6761.VerifyPhase1, contribs)
+	case *mpcsetup_bls24317.Phase1:
+		return verifyAll(mpcsetup_bls24317.VerifyPhase1, contribs)
+	case *mpcsetup_bls24315.Phase1:
+		return verifyAll(mpcsetup_bls24315.VerifyPhase1, contribs)
+	case *mpcsetup_bw6633.Phase1:
+		return verifyAll(mpcsetup_bw6633.VerifyPhase1, contribs)
+	default:
+		panic("not implemented")
+	}
+}
+
+// VerifyPhase1Beacon checks that contribution is the beacon contribution of seed and nbIterations
+// on top of current.
+func VerifyPhase1Beacon(current, contribution Phase1, seed []byte, nbIterations int) error {
+	switch _current := current.(type) {
+	case *mpcsetup_bn254.Phase1:
+		return mpcsetup_bn254.VerifyPhase1Beacon(_current, contribution.(*mpcsetup_bn254.Phase1), seed, nbIterations)
+	case *mpcsetup_bls12377.Phase1:
+		return mpcsetup_bls12377.VerifyPhase1Beacon(_current, contribution.(*mpcsetup_bls12377.Phase1), seed, nbIterations)
+	case *mpcsetup_bls12381.Phase1:
+		return mpcsetup_bls12381.VerifyPhase1Beacon(_current, contribution.(*mpcsetup_bls12381.Phase1), seed, nbIterations)
+	case *mpcsetup_bw6761.Phase1:
+		return mpcsetup_bw6761.VerifyPhase1Beacon(_current, contribution.(*mpcsetup_bw6761.Phase1), seed, nbIterations)
+	case *mpcsetup_bls24317.Phase1:
+		return mpcsetup_bls24317.VerifyPhase1Beacon(_current, contribution.(*mpcsetup_bls24317.Phase1), seed, nbIterations)
+	case *mpcsetup_bls24315.Phase1:
+		return mpcsetup_bls24315.VerifyPhase1Beacon(_current, contribution.(*mpcsetup_bls24315.Phase1), seed, nbIterations)
+	case *mpcsetup_bw6633.Phase1:
+		return mpcsetup_bw6633.VerifyPhase1Beacon(_current, contribution.(*mpcsetup_bw6633.Phase1), seed, nbIterations)
+	default:
+		panic("not implemented")
+	}
+}
+
+// InitPhase2 returns the initial state of phase 2 for the circuit r1cs, from the final state of
+// phase 1, and the evaluations of the circuit. Phase 1 must be of the size of the domain of the
+// circuit.
+func InitPhase2(r1cs constraint.ConstraintSystem, srs1 Phase1) (Phase2, Phase2Evaluations, error) {
+	switch _r1cs := r1cs.(type) {
+	case *cs_bn254.R1CS:
+		srs2, evals, err := mpcsetup_bn254.InitPhase2(_r1cs, srs1.(*mpcsetup_bn254.Phase1))
+		if err != nil {
+			return nil, nil, err
+		}
+		return &srs2, &evals, nil
+	case *cs_bls12377.R1CS:
+		srs2, evals, err := mpcsetup_bls12377.InitPhase2(_r1cs, srs1.(*mpcsetup_bls12377.Phase1))
+		if err != nil {
+			return nil, nil, err
+		}
+		return &srs2, &evals, nil
+	case *cs_bls12381.R1CS:
+		srs2, evals, err := mpcsetup_bls12381.InitPhase2(_r1cs, srs1.(*mpcsetup_bls12381.Phase1))
+		if err != nil {
+			return nil, nil, err
+		}
+		return &srs2, &evals, nil
+	case *cs_bw6761.R1CS:
+		srs2, evals, err := mpcsetup_bw6761.InitPhase2(_r1cs, srs1.(*mpcsetup_bw6761.Phase1))
+		if err != nil {
+			return nil, nil, err
+		}
+		return &srs2, &evals, nil
+	case *cs_bls24317.R1CS:
+		srs2, evals, err := mpcsetup_bls24317.InitPhase2(_r1cs, srs1.(*mpcsetup_bls24317.Phase1))
+		if err != nil {
+			return nil, nil, err
+		}
+		return &srs2, &evals, nil
+	case *cs_bls24315.R1CS:
+		srs2, evals, err := mpcsetup_bls24315.InitPhase2(_r1cs, srs1.(*mpcsetup_bls24315.Phase1))
+		if err != nil {
+			return nil, nil, err
+		}
+		return &srs2, &evals, nil
+	case *cs_bw6633.R1CS:
+		srs2, evals, err := mpcsetup_bw6633.InitPhase2(_r1cs, srs1.(*mpcsetup_bw6633.Phase1))
+		if err != nil {
+			return nil, nil, err
+		}
+		return &srs2, &evals, nil
+	default:
+		panic("not implemented")
+	}
+}
+
+// VerifyPhase2 checks that each contribution of phase 2 is based on the previous one.
+func VerifyPhase2(c0, c1 Phase2, c ...Phase2) error {
+	contribs := append([]Phase2{c0, c1}, c...)
+	switch c0.(type) {
+	case *mpcsetup_bn254.Phase2:
+		return verifyAll(mpcsetup_bn254.VerifyPhase2, contribs)
+	case *mpcsetup_bls12377.Phase2:
+		return verifyAll(mpcsetup_bls12377.VerifyPhase2, contribs)
+	case *mpcsetup_bls12381.Phase2:
+		return verifyAll(mpcsetup_bls12381.VerifyPhase2, contribs)
+	case *mpcsetup_bw6761.Phase2:
+		return verifyAll(mpcsetup_bw6761.VerifyPhase2, contribs)
+	case *mpcsetup_bls24317.Phase2:
+		return verifyAll(mpcsetup_bls24317.VerifyPhase2, contribs)
+	case *mpcsetup_bls24315.Phase2:
+		return verifyAll(mpcsetup_bls24315.VerifyPhase2, contribs)
+	case *mpcsetup_bw6633.Phase2:
+		return verifyAll(mpcsetup_bw6633.VerifyPhase2, contribs)
+	default:
+		panic("not implemented")
+	}
+}
+
+// VerifyPhase2Beacon checks that contribution is the beacon contribution of seed and nbIterations
+// on top of current.
+func VerifyPhase2Beacon(current, contribution Phase2, seed []byte, nbIterations int) error {
+	switch _current := current.(type) {
+	case *mpcsetup_bn254.Phase2:
+		return mpcsetup_bn254.VerifyPhase2Beacon(_current, contribution.(*mpcsetup_bn254.Phase2), seed, nbIterations)
+	case *mpcsetup_bls12377.Phase2:
+		return mpcsetup_bls12377.VerifyPhase2Beacon(_current, contribution.(*mpcsetup_bls12377.Phase2), seed, nbIterations)
+	case *mpcsetup_bls12381.Phase2:
+		return mpcsetup_bls12381.VerifyPhase2Beacon(_current, contribution.(*mpcsetup_bls12381.Phase2), seed, nbIterations)
+	case *mpcsetup_bw6761.Phase2:
+		return mpcsetup_bw6761.VerifyPhase2Beacon(_current, contribution.(*mpcsetup_bw6761.Phase2), seed, nbIterations)
+	case *mpcsetup_bls24317.Phase2:
+		return mpcsetup_bls24317.VerifyPhase2Beacon(_current, contribution.(*mpcsetup_bls24317.Phase2), seed, nbIterations)
+	case *mpcsetup_bls24315.Phase2:
+		return mpcsetup_bls24315.VerifyPhase2Beacon(_current, contribution.(*mpcsetup_bls24315.Phase2), seed, nbIterations)
+	case *mpcsetup_bw6633.Phase2:
+		return mpcsetup_bw6633.VerifyPhase2Beacon(_current, contribution.(*mpcsetup_bw6633.Phase2), seed, nbIterations)
+	default:
+		panic("not implemented")
+	}
+}
+
+// ExtractKeys returns the Groth16 keys of a circuit of nConstraints constraints from the final
+// states of both phases.
+func ExtractKeys(srs1 Phase1, srs2 Phase2, evals Phase2Evaluations, nConstraints int) (groth16.ProvingKey, groth16.VerifyingKey, error) {
+	switch _srs1 := srs1.(type) {
+	case *mpcsetup_bn254.Phase1:
+		pk, vk, err := mpcsetup_bn254.ExtractKeys(_srs1, srs2.(*mpcsetup_bn254.Phase2), evals.(*mpcsetup_bn254.Phase2Evaluations), nConstraints)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &pk, &vk, nil
+	case *mpcsetup_bls12377.Phase1:
+		pk, vk, err := mpcsetup_bls12377.ExtractKeys(_srs1, srs2.(*mpcsetup_bls12377.Phase2), evals.(*mpcsetup_bls12377.Phase2Evaluations), nConstraints)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &pk, &vk, nil
+	case *mpcsetup_bls12381.Phase1:
+		pk, vk, err := mpcsetup_bls12381.ExtractKeys(_srs1, srs2.(*mpcsetup_bls12381.Phase2), evals.(*mpcsetup_bls12381.Phase2Evaluations), nConstraints)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &pk, &vk, nil
+	case *mpcsetup_bw6761.Phase1:
+		pk, vk, err := mpcsetup_bw6761.ExtractKeys(_srs1, srs2.(*mpcsetup_bw6761.Phase2), evals.(*mpcsetup_bw6761.Phase2Evaluations), nConstraints)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &pk, &vk, nil
+	case *mpcsetup_bls24317.Phase1:
+		pk, vk, err := mpcsetup_bls24317.ExtractKeys(_srs1, srs2.(*mpcsetup_bls24317.Phase2), evals.(*mpcsetup_bls24317.Phase2Evaluations), nConstraints)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &pk, &vk, nil
+	case *mpcsetup_bls24315.Phase1:
+		pk, vk, err := mpcsetup_bls24315.ExtractKeys(_srs1, srs2.(*mpcsetup_bls24315.Phase2), evals.(*mpcsetup_bls24315.Phase2Evaluations), nConstraints)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &pk, &vk, nil
+	case *mpcsetup_bw6633.Phase1:
+		pk, vk, err := mpcsetup_bw6633.ExtractKeys(_srs1, srs2.(*mpcsetup_bw6633.Phase2), evals.(*mpcsetup_bw6633.Phase2Evaluations), nConstraints)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &pk, &vk, nil
+	default:
+		panic("not implemented")
+	}
+}
+
+// NewPhase1 instantiates a curve-typed Phase1, to be read with ReadFrom.
+func NewPhase1(curveID ecc.ID) Phase1 {
+	switch curveID {
+	case ecc.BN254:
+		return &mpcsetup_bn254.Phase1{}
+	case ecc.BLS12_377:
+		return &mpcsetup_bls12377.Phase1{}
+	case ecc.BLS12_381:
+		return &mpcsetup_bls12381.Phase1{}
+	case ecc.BW6_761:
+		return &mpcsetup_bw6761.Phase1{}
+	case ecc.BLS24_317:
+		return &mpcsetup_bls24317.Phase1{}
+	case ecc.BLS24_315:
+		return &mpcsetup_bls24315.Phase1{}
+	case ecc.BW6_633:
+		return &mpcsetup_bw6633.Phase1{}
+	default:
+		panic("not implemented")
+	}
+}
+
+// NewPhase2 instantiates a curve-typed Phase2, to be read with ReadFrom.
+func NewPhase2(curveID ecc.ID) Phase2 {
+	switch curveID {
+	case ecc.BN254:
+		return &mpcsetup_bn254.Phase2{}
+	case ecc.BLS12_377:
+		return &mpcsetup_bls12377.Phase2{}
+	case ecc.BLS12_381:
+		return &mpcsetup_bls12381.Phase2{}
+	case ecc.BW6_761:
+		return &mpcsetup_bw6761.Phase2{}
+	case ecc.BLS24_317:
+		return &mpcsetup_bls24317.Phase2{}
+	case ecc.BLS24_315:
+		return &mpcsetup_bls24315.Phase2{}
+	case ecc.BW6_633:
+		return &mpcsetup_bw6633.Phase2{}
+	default:
+		panic("not implemented")
+	}
+}
+
+// NewPhase2Evaluations instantiates a curve-typed Phase2Evaluations, to be read with ReadFrom.
+func NewPhase2Evaluations(curveID ecc.ID) Phase2Evaluations {
+	switch curveID {
+	case ecc.BN254:
+		return &mpcsetup_bn254.Phase2Evaluations{}
+	case ecc.BLS12_377:
+		return &mpcsetup_bls12377.Phase2Evaluations{}
+	case ecc.BLS12_381:
+		return &mpcsetup_bls12381.Phase2Evaluations{}
+	case ecc.BW6_761:
+		return &mpcsetup_bw6761.Phase2Evaluations{}
+	case ecc.BLS24_317:
+		return &mpcsetup_bls24317.Phase2Evaluations{}
+	case ecc.BLS24_315:
+		return &mpcsetup_bls24315.Phase2Evaluations{}
+	case ecc.BW6_633:
+		return &mpcsetup_bw6633.Phase2Evaluations{}
+	default:
+		panic("not implemented")
+	}
+}
+
+// verifyAll calls the curve-typed verify on the contributions.
+func verifyAll[T, I any](verify func(c0, c1 *T, c ...*T) error, contribs []I) error {
+	typed := make([]*T, len(contribs))
+	for i := range contribs {
+		typed[i] = any(contribs[i]).(*T)
+	}
+	return verify(typed[0], typed[1], typed[2:]...)
+}
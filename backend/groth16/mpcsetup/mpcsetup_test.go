@@ -0,0 +1,180 @@
+package mpcsetup_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	mpcsetup_bn254 "github.com/consensys/gnark/backend/groth16/bn254/mpcsetup"
+	"github.com/consensys/gnark/backend/groth16/mpcsetup"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/stretchr/testify/require"
+)
+
+type cubeCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *cubeCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(c.X, c.X, c.X)
+	api.AssertIsEqual(c.Y, api.Add(x3, c.X, 5))
+	return nil
+}
+
+func TestCeremony(t *testing.T) {
+	assert := require.New(t)
+	const curve = ecc.BN254
+	seed := []byte("block 0x3ab6c1")
+
+	transcript := mpcsetup.NewTranscript(curve)
+	record := func(phase int, participant string, beacon *mpcsetup.Beacon, hash []byte) {
+		assert.NoError(transcript.Append(mpcsetup.Contribution{
+			Phase:       phase,
+			Participant: participant,
+			Time:        time.Now(),
+			Beacon:      beacon,
+			Hash:        hash,
+		}))
+	}
+
+	// phase 1: the participants receive the serialized state of the previous one. The domain of
+	// the circuit, of 3 constraints, has 2² elements.
+	srs1 := []mpcsetup.Phase1{mpcsetup.InitPhase1(curve, 2)}
+	record(1, "coordinator", nil, srs1[0].ContributionHash())
+	for i, participant := range []string{"alice", "bob", "beacon"} {
+		next := roundTrip1(t, curve, srs1[i])
+		var beacon *mpcsetup.Beacon
+		if participant == "beacon" {
+			beacon = &mpcsetup.Beacon{Seed: seed, NbIterations: 16}
+			next.ContributeWithBeacon(beacon.Seed, beacon.NbIterations)
+		} else {
+			next.Contribute()
+		}
+		srs1 = append(srs1, next)
+		record(1, participant, beacon, next.ContributionHash())
+	}
+
+	// phase 2
+	ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, &cubeCircuit{})
+	assert.NoError(err)
+	init, evals, err := mpcsetup.InitPhase2(ccs, srs1[len(srs1)-1])
+	assert.NoError(err)
+	srs2 := []mpcsetup.Phase2{init}
+	record(2, "coordinator", nil, init.ContributionHash())
+	next := roundTrip2(t, curve, init)
+	next.Contribute()
+	srs2 = append(srs2, next)
+	record(2, "alice", nil, next.ContributionHash())
+
+	// the transcript survives its encoding
+	b, err := json.Marshal(transcript)
+	assert.NoError(err)
+	var decoded mpcsetup.Transcript
+	assert.NoError(json.Unmarshal(b, &decoded))
+	assert.NoError(decoded.VerifyPhase1(srs1...))
+	assert.NoError(decoded.VerifyPhase2(srs2...))
+	assert.Len(decoded.Phase(1), 4)
+
+	// tampered transcripts
+	tampered := decoded
+	tampered.Contributions = append([]mpcsetup.Contribution{}, decoded.Contributions...)
+	tampered.Contributions[1].Participant = "mallory"
+	assert.Error(tampered.Verify())
+
+	tampered.Contributions = append([]mpcsetup.Contribution{}, decoded.Contributions...)
+	tampered.Contributions[3].Beacon = &mpcsetup.Beacon{Seed: seed, NbIterations: 17}
+	assert.Error(tampered.Verify())
+
+	// contributions missing or out of order
+	assert.Error(decoded.VerifyPhase1(srs1[:3]...))
+	assert.Error(decoded.VerifyPhase1(srs1[0], srs1[2], srs1[1], srs1[3]))
+	assert.Error(decoded.Append(mpcsetup.Contribution{Phase: 1}))
+
+	// a beacon contribution must be reproducible from the recorded parameters, even in a transcript
+	// with valid digests
+	forged := mpcsetup.NewTranscript(curve)
+	for _, c := range decoded.Phase(1) {
+		if c.Beacon != nil {
+			c.Beacon = &mpcsetup.Beacon{Seed: seed, NbIterations: 17}
+		}
+		assert.NoError(forged.Append(c))
+	}
+	assert.Error(forged.VerifyPhase1(srs1...))
+
+	// the keys prove
+	pk, vk, err := mpcsetup.ExtractKeys(srs1[len(srs1)-1], srs2[len(srs2)-1], evals, ccs.GetNbConstraints())
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&cubeCircuit{X: 3, Y: 35}, curve.ScalarField())
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, w)
+	assert.NoError(err)
+	pw, err := w.Public()
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, vk, pw))
+}
+
+// The initial state is recorded with its hash, that the next contribution is chained to. A
+// coordinator who knows a contribution made to the initial state could publish it with the hash
+// of the initial state: the chain of contributions would still be valid.
+func TestTamperedInitialState(t *testing.T) {
+	assert := require.New(t)
+	const curve = ecc.BN254
+
+	record := func(transcript *mpcsetup.Transcript, phase int, hash []byte) {
+		assert.NoError(transcript.Append(mpcsetup.Contribution{Phase: phase, Time: time.Now(), Hash: hash}))
+	}
+
+	init1 := mpcsetup.InitPhase1(curve, 2)
+	tampered1 := roundTrip1(t, curve, init1)
+	tampered1.Contribute()
+	tampered1.(*mpcsetup_bn254.Phase1).Hash = init1.ContributionHash()
+	next1 := roundTrip1(t, curve, tampered1)
+	next1.Contribute()
+
+	transcript := mpcsetup.NewTranscript(curve)
+	record(transcript, 1, init1.ContributionHash())
+	record(transcript, 1, next1.ContributionHash())
+	assert.NoError(mpcsetup.VerifyPhase1(tampered1, next1), "the chain alone doesn't detect it")
+	assert.Error(transcript.VerifyPhase1(tampered1, next1))
+	assert.Error(transcript.VerifyPhase1(roundTrip1(t, curve, tampered1), next1), "the hash is read from the file")
+
+	ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, &cubeCircuit{})
+	assert.NoError(err)
+	init2, _, err := mpcsetup.InitPhase2(ccs, init1)
+	assert.NoError(err)
+	tampered2 := roundTrip2(t, curve, init2)
+	tampered2.Contribute()
+	tampered2.(*mpcsetup_bn254.Phase2).Hash = init2.ContributionHash()
+	next2 := roundTrip2(t, curve, tampered2)
+	next2.Contribute()
+
+	record(transcript, 2, init2.ContributionHash())
+	record(transcript, 2, next2.ContributionHash())
+	assert.NoError(mpcsetup.VerifyPhase2(tampered2, next2), "the chain alone doesn't detect it")
+	assert.Error(transcript.VerifyPhase2(roundTrip2(t, curve, tampered2), next2))
+}
+
+func roundTrip1(t *testing.T, curve ecc.ID, srs mpcsetup.Phase1) mpcsetup.Phase1 {
+	var buf bytes.Buffer
+	_, err := srs.WriteTo(&buf)
+	require.NoError(t, err)
+	res := mpcsetup.NewPhase1(curve)
+	_, err = res.ReadFrom(&buf)
+	require.NoError(t, err)
+	return res
+}
+
+func roundTrip2(t *testing.T, curve ecc.ID, srs mpcsetup.Phase2) mpcsetup.Phase2 {
+	var buf bytes.Buffer
+	_, err := srs.WriteTo(&buf)
+	require.NoError(t, err)
+	res := mpcsetup.NewPhase2(curve)
+	_, err = res.ReadFrom(&buf)
+	require.NoError(t, err)
+	return res
+}
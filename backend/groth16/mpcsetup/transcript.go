@@ -0,0 +1,227 @@
+package mpcsetup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// Transcript is the public record of a ceremony, encoded in JSON: the list of the states of both
+// phases, from their initialization to their last contribution. Each entry is chained to the
+// previous ones by its Digest. The digests are unkeyed hashes: anyone who alters an entry can
+// recompute the following ones, so the chain only shows tampering when checked against a last
+// Digest (or contribution hashes) published independently, e.g. by the participants.
+type Transcript struct {
+	Curve         string         `json:"curve"`
+	Contributions []Contribution `json:"contributions"`
+}
+
+// Contribution is an entry of a Transcript. The initial state of a phase is recorded as a
+// contribution of the coordinator.
+type Contribution struct {
+	// Phase is 1 or 2.
+	Phase int `json:"phase"`
+
+	// Participant, Comment and Time are informative metadata.
+	Participant string    `json:"participant"`
+	Comment     string    `json:"comment,omitempty"`
+	Time        time.Time `json:"time"`
+
+	// Beacon is set if the contribution was made with ContributeWithBeacon.
+	Beacon *Beacon `json:"beacon,omitempty"`
+
+	// Hash is the ContributionHash of the state after the contribution.
+	Hash []byte `json:"hash"`
+
+	// Digest is the hash of the curve, of the fields above and of the previous Digest. It is set by
+	// Append.
+	Digest []byte `json:"digest"`
+}
+
+// Beacon holds the public parameters of a random beacon contribution.
+type Beacon struct {
+	Seed         []byte `json:"seed"`
+	NbIterations int    `json:"nbIterations"`
+}
+
+// NewTranscript returns an empty transcript for a ceremony on the curve curveID.
+func NewTranscript(curveID ecc.ID) *Transcript {
+	return &Transcript{Curve: curveID.String()}
+}
+
+// Append sets the digest of c, chaining it to the last contribution, and records it.
+func (t *Transcript) Append(c Contribution) error {
+	if c.Phase != 1 && c.Phase != 2 {
+		return fmt.Errorf("invalid phase %d", c.Phase)
+	}
+	var prev []byte
+	if n := len(t.Contributions); n != 0 {
+		if t.Contributions[n-1].Phase > c.Phase {
+			return errors.New("phase 1 contributions can't follow phase 2 ones")
+		}
+		prev = t.Contributions[n-1].Digest
+	}
+	c.Digest = t.digest(prev, &c)
+	t.Contributions = append(t.Contributions, c)
+	return nil
+}
+
+// Verify checks the chain of digests of the transcript. It only checks that the transcript is
+// consistent; that it is the one of the ceremony is checked by comparing its last Digest with a
+// published one.
+func (t *Transcript) Verify() error {
+	var prev []byte
+	for i := range t.Contributions {
+		c := &t.Contributions[i]
+		if c.Phase != 1 && c.Phase != 2 || i != 0 && t.Contributions[i-1].Phase > c.Phase {
+			return fmt.Errorf("contribution %d: invalid phase %d", i, c.Phase)
+		}
+		if !bytes.Equal(c.Digest, t.digest(prev, c)) {
+			return fmt.Errorf("contribution %d: digest mismatch", i)
+		}
+		prev = c.Digest
+	}
+	return nil
+}
+
+// Phase returns the contributions of the phase.
+func (t *Transcript) Phase(phase int) []Contribution {
+	var res []Contribution
+	for _, c := range t.Contributions {
+		if c.Phase == phase {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// VerifyPhase1 checks the transcript, and that contribs are the states of phase 1 it records, each
+// based on the previous one. contribs[0] is the initial state: its hash is checked against its
+// content, but whether it is a valid initial state is not: compare it with InitPhase1.
+func (t *Transcript) VerifyPhase1(contribs ...Phase1) error {
+	entries, err := match(t, 1, contribs)
+	if err != nil {
+		return err
+	}
+	if len(contribs) == 0 {
+		return nil
+	}
+	if err := checkHash(contribs[0]); err != nil {
+		return fmt.Errorf("phase 1 contribution 0: %w", err)
+	}
+	if len(contribs) > 1 {
+		if err := VerifyPhase1(contribs[0], contribs[1], contribs[2:]...); err != nil {
+			return err
+		}
+	}
+	for i := 1; i < len(entries); i++ {
+		if b := entries[i].Beacon; b != nil {
+			if err := VerifyPhase1Beacon(contribs[i-1], contribs[i], b.Seed, b.NbIterations); err != nil {
+				return fmt.Errorf("phase 1 contribution %d: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// VerifyPhase2 checks the transcript, and that contribs are the states of phase 2 it records, each
+// based on the previous one. contribs[0] is the initial state: its hash is checked against its
+// content, but whether it is a valid initial state is not: compare it with InitPhase2.
+func (t *Transcript) VerifyPhase2(contribs ...Phase2) error {
+	entries, err := match(t, 2, contribs)
+	if err != nil {
+		return err
+	}
+	if len(contribs) == 0 {
+		return nil
+	}
+	if err := checkHash(contribs[0]); err != nil {
+		return fmt.Errorf("phase 2 contribution 0: %w", err)
+	}
+	if len(contribs) > 1 {
+		if err := VerifyPhase2(contribs[0], contribs[1], contribs[2:]...); err != nil {
+			return err
+		}
+	}
+	for i := 1; i < len(entries); i++ {
+		if b := entries[i].Beacon; b != nil {
+			if err := VerifyPhase2Beacon(contribs[i-1], contribs[i], b.Seed, b.NbIterations); err != nil {
+				return fmt.Errorf("phase 2 contribution %d: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// match verifies the transcript and checks that it records the hashes of contribs for the phase.
+func match[S interface{ ContributionHash() []byte }](t *Transcript, phase int, contribs []S) ([]Contribution, error) {
+	if err := t.Verify(); err != nil {
+		return nil, err
+	}
+	entries := t.Phase(phase)
+	if len(entries) != len(contribs) {
+		return nil, fmt.Errorf("the transcript records %d states of phase %d, got %d", len(entries), phase, len(contribs))
+	}
+	for i := range entries {
+		if !bytes.Equal(entries[i].Hash, contribs[i].ContributionHash()) {
+			return nil, fmt.Errorf("phase %d contribution %d: hash mismatch with the transcript", phase, i)
+		}
+	}
+	return entries, nil
+}
+
+// checkHash checks that the hash recorded in a state is the hash of its content. States are
+// decoded with the hash they record, which the verification of a contribution recomputes, but
+// nothing does for the initial state. States are written with their hash at the end, after the
+// content it is the SHA-256 of.
+func checkHash(s interface {
+	io.WriterTo
+	ContributionHash() []byte
+}) error {
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		return err
+	}
+	hash := s.ContributionHash()
+	content := buf.Bytes()[:buf.Len()-len(hash)]
+	if h := sha256.Sum256(content); !bytes.Equal(h[:], hash) {
+		return errors.New("the recorded hash isn't the hash of the state")
+	}
+	return nil
+}
+
+func (t *Transcript) digest(prev []byte, c *Contribution) []byte {
+	h := sha256.New()
+	writeBytes := func(b []byte) {
+		var l [8]byte
+		binary.BigEndian.PutUint64(l[:], uint64(len(b)))
+		h.Write(l[:])
+		h.Write(b)
+	}
+	writeInt := func(i int) {
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], uint64(i))
+		h.Write(b[:])
+	}
+	writeBytes(prev)
+	writeBytes([]byte(t.Curve))
+	writeInt(c.Phase)
+	writeBytes([]byte(c.Participant))
+	writeBytes([]byte(c.Comment))
+	writeBytes([]byte(c.Time.UTC().Format(time.RFC3339Nano)))
+	if c.Beacon != nil {
+		h.Write([]byte{1})
+		writeBytes(c.Beacon.Seed)
+		writeInt(c.Beacon.NbIterations)
+	} else {
+		h.Write([]byte{0})
+	}
+	writeBytes(c.Hash)
+	return h.Sum(nil)
+}
@@ -0,0 +1,107 @@
+package plonk_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/plonk"
+	plonk_bn254 "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/internal/backend/circuits"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+// batchProofs returns nbProofs proofs of circuits.Cube and their public witnesses.
+func batchProofs(assert *require.Assertions, curve ecc.ID, nbProofs int) (constraint.ConstraintSystem, plonk.VerifyingKey, []plonk.Proof, []witness.Witness) {
+	ccs, err := frontend.Compile(curve.ScalarField(), scs.NewBuilder, &circuits.Cube{WithCommitment: true})
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+
+	proofs := make([]plonk.Proof, nbProofs)
+	publicWitnesses := make([]witness.Witness, nbProofs)
+	for i := range proofs {
+		x := i + 2
+		w, err := frontend.NewWitness(&circuits.Cube{X: x, Y: x * x * x}, curve.ScalarField())
+		assert.NoError(err)
+		proofs[i], err = plonk.Prove(ccs, pk, w)
+		assert.NoError(err)
+		publicWitnesses[i], err = w.Public()
+		assert.NoError(err)
+	}
+	return ccs, vk, proofs, publicWitnesses
+}
+
+func TestBatchVerifyRandomCombination(t *testing.T) {
+	assert := require.New(t)
+
+	_, vk, proofs, publicWitnesses := batchProofs(assert, ecc.BN254, 1)
+	assert.NoError(plonk.BatchVerify(proofs, vk, publicWitnesses))
+
+	// two copies of a proof, whose quotients of the opening at ζ are shifted by D and -D: the
+	// shifts cancel out in the sum of the claims, but not in a random linear combination.
+	proof := proofs[0].(*plonk_bn254.Proof)
+	d := vk.(*plonk_bn254.VerifyingKey).Kzg.G1
+	plus, minus := *proof, *proof
+	plus.BatchedProof.H.Add(&plus.BatchedProof.H, &d)
+	minus.BatchedProof.H.Sub(&minus.BatchedProof.H, &d)
+	assert.Error(plonk.Verify(&plus, vk, publicWitnesses[0]))
+	assert.Error(plonk.Verify(&minus, vk, publicWitnesses[0]))
+
+	err := plonk.BatchVerify([]plonk.Proof{&plus, &minus}, vk, []witness.Witness{publicWitnesses[0], publicWitnesses[0]})
+	var bErr *backend.BatchVerificationError
+	assert.True(errors.As(err, &bErr), "unexpected error %v", err)
+	assert.Equal([]int{0, 1}, bErr.Invalid)
+}
+
+func TestAccumulator(t *testing.T) {
+	assert := require.New(t)
+
+	_, _vk, _proofs, _publicWitnesses := batchProofs(assert, ecc.BN254, 4)
+	vk := _vk.(*plonk_bn254.VerifyingKey)
+	proofs := make([]*plonk_bn254.Proof, len(_proofs))
+	publicWitnesses := make([]fr_bn254.Vector, len(_proofs))
+	for i := range proofs {
+		proofs[i] = _proofs[i].(*plonk_bn254.Proof)
+		publicWitnesses[i] = _publicWitnesses[i].Vector().(fr_bn254.Vector)
+	}
+
+	// two verifications accumulated separately, then merged
+	acc1, acc2 := plonk_bn254.NewAccumulator(vk.Kzg), plonk_bn254.NewAccumulator(vk.Kzg)
+	for i := range proofs {
+		acc := acc1
+		if i%2 == 1 {
+			acc = acc2
+		}
+		assert.NoError(acc.Add(proofs[i], vk, publicWitnesses[i]))
+	}
+	assert.NoError(acc1.Check())
+	assert.NoError(acc2.Check())
+	assert.NoError(acc1.Merge(acc2))
+	assert.Equal(2*len(proofs), acc1.NbClaims())
+	assert.NoError(acc1.Check())
+
+	// an invalid opening proof is only detected by the final check
+	invalid := *proofs[0]
+	invalid.ZShiftedOpening.H.Double(&invalid.ZShiftedOpening.H)
+	acc3 := plonk_bn254.NewAccumulator(vk.Kzg)
+	assert.NoError(acc3.Add(&invalid, vk, publicWitnesses[0]))
+	assert.NoError(acc1.Merge(acc3))
+	assert.Error(acc1.Check())
+
+	// another KZG SRS
+	other := plonk_bn254.NewAccumulator(vk.Kzg)
+	otherVk := *vk
+	otherVk.Kzg.G1.Double(&otherVk.Kzg.G1)
+	assert.Error(other.Add(proofs[0], &otherVk, publicWitnesses[0]))
+	assert.Error(acc1.Merge(plonk_bn254.NewAccumulator(otherVk.Kzg)))
+}
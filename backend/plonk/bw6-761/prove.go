@@ -100,6 +100,11 @@ func bsb22ComputeCommitmentHint(spr *cs.SparseR1CS, pk *ProvingKey, proof *Proof
 	}
 }
 
+// Prove generates the proof of knowledge of a sparse r1cs with full witness (secret + public part).
+//
+// The context of the prover (see backend.WithContext) is checked at the end of each phase of
+// the computation: "solve", "commit lro", "copy constraint ratio", "commit z", "quotient",
+// "commit quotient", "open z", "commit linearized polynomial" and "batch opening".
 func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (*Proof, error) {
 	log := logger.Logger().With().
 		Str("curve", spr.CurveID().String()).
@@ -111,6 +116,9 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	if err != nil {
 		return nil, err
 	}
+	if err := opt.Err(); err != nil {
+		return nil, err
+	}
 
 	start := time.Now()
 
@@ -148,6 +156,9 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	if err != nil {
 		return nil, err
 	}
+	if err := opt.EndPhase("solve", start); err != nil {
+		return nil, err
+	}
 	solution := _solution.(*cs.SparseR1CSSolution)
 	evaluationLDomainSmall := []fr.Element(solution.L)
 	evaluationRDomainSmall := []fr.Element(solution.R)
@@ -213,9 +224,13 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 
 	// wait for polys to be blinded
 	wgLRO.Wait()
+	startPhase := time.Now()
 	if err := commitToLRO(bwliop.Coefficients(), bwriop.Coefficients(), bwoiop.Coefficients(), proof, pk.Kzg); err != nil {
 		return nil, err
 	}
+	if err := opt.EndPhase("commit lro", startPhase); err != nil {
+		return nil, err
+	}
 
 	gamma, err := deriveRandomness(&fs, "gamma", &proof.LRO[0], &proof.LRO[1], &proof.LRO[2]) // TODO @Tabaie @ThomasPiellard add BSB commitment here?
 	if err != nil {
@@ -255,6 +270,7 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 
 	// compute the copy constraint's ratio
 	// note that wliop, wriop and woiop are fft'ed (mutated) in the process.
+	startPhase = time.Now()
 	bwziop, err := iop.BuildRatioCopyConstraint(
 		[]*iop.Polynomial{
 			wliop,
@@ -270,6 +286,9 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	if err != nil {
 		return proof, err
 	}
+	if err := opt.EndPhase("copy constraint ratio", startPhase); err != nil {
+		return nil, err
+	}
 	// unused.
 	wliop = nil
 	wriop = nil
@@ -283,9 +302,15 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 		// blind Z
 		// TODO @gbotrel memory wise we should allocate a bigger result for BuildRatioCopyConstraint
 		bwziop.Blind(2)
+		startCommit := time.Now()
 		proof.Z, err = kzg.Commit(bwziop.Coefficients(), pk.Kzg, runtime.NumCPU()*2)
 		if err != nil {
 			chZ <- err
+			return
+		}
+		if err := opt.EndPhase("commit z", startCommit); err != nil {
+			chZ <- err
+			return
 		}
 
 		// derive alpha from the Comm(l), Comm(r), Comm(o), Com(Z)
@@ -297,6 +322,7 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 		alpha, err = deriveRandomness(&fs, "alpha", alphaDeps...)
 		if err != nil {
 			chZ <- err
+			return
 		}
 
 		// Store z(g*x)
@@ -400,6 +426,7 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	copy(toEval[idx_Bsb22Commitments+len(lcCommitments):], pk.lcQcp)
 
 	// systemEvaluation reuses lcqk for memory.
+	startPhase = time.Now()
 	systemEvaluation, err := evaluate(lcqk, pk, fm, toEval...)
 	if err != nil {
 		return nil, err
@@ -420,8 +447,12 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	if err != nil {
 		return nil, err
 	}
+	if err := opt.EndPhase("quotient", startPhase); err != nil {
+		return nil, err
+	}
 
 	// compute kzg commitments of h1, h2 and h3
+	startPhase = time.Now()
 	if err := commitToQuotient(
 		h.Coefficients()[:pk.Domain[0].Cardinality+2],
 		h.Coefficients()[pk.Domain[0].Cardinality+2:2*(pk.Domain[0].Cardinality+2)],
@@ -429,6 +460,9 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 		proof, pk.Kzg); err != nil {
 		return nil, err
 	}
+	if err := opt.EndPhase("commit quotient", startPhase); err != nil {
+		return nil, err
+	}
 
 	// derive zeta
 	zeta, err := deriveRandomness(&fs, "zeta", &proof.H[0], &proof.H[1], &proof.H[2])
@@ -458,6 +492,7 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 
 	var zetaShifted fr.Element
 	zetaShifted.Mul(&zeta, &pk.Vk.Generator)
+	startPhase = time.Now()
 	proof.ZShiftedOpening, err = kzg.Open(
 		bwziop.Coefficients()[:bwziop.BlindedSize()],
 		zetaShifted,
@@ -466,6 +501,9 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	if err != nil {
 		return nil, err
 	}
+	if err := opt.EndPhase("open z", startPhase); err != nil {
+		return nil, err
+	}
 
 	// start to compute foldedH and foldedHDigest while computeLinearizedPolynomial runs.
 	computeFoldedH := make(chan struct{}, 1)
@@ -531,10 +569,14 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 
 	// TODO this commitment is only necessary to derive the challenge, we should
 	// be able to avoid doing it and get the challenge in another way
+	startPhase = time.Now()
 	linearizedPolynomialDigest, errLPoly = kzg.Commit(linearizedPolynomialCanonical, pk.Kzg, runtime.NumCPU()*2)
 	if errLPoly != nil {
 		return nil, errLPoly
 	}
+	if err := opt.EndPhase("commit linearized polynomial", startPhase); err != nil {
+		return nil, err
+	}
 
 	// wait for foldedH and foldedHDigest
 	<-computeFoldedH
@@ -563,6 +605,7 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	digestsToOpen[5] = pk.Vk.S[0]
 	digestsToOpen[6] = pk.Vk.S[1]
 
+	startPhase = time.Now()
 	proof.BatchedProof, err = kzg.BatchOpenSinglePoint(
 		polysToOpen,
 		digestsToOpen,
@@ -571,11 +614,14 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 		pk.Kzg,
 	)
 
-	log.Debug().Dur("took", time.Since(start)).Msg("prover done")
-
 	if err != nil {
 		return nil, err
 	}
+	if err := opt.EndPhase("batch opening", startPhase); err != nil {
+		return nil, err
+	}
+
+	log.Debug().Dur("took", time.Since(start)).Msg("prover done")
 
 	return proof, nil
 
@@ -0,0 +1,61 @@
+package plonk_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/internal/backend/circuits"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProveContext(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &circuits.Cube{WithCommitment: true})
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	pk, _, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&circuits.Cube{X: 3, Y: 27}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// all the phases are reported
+	var lock sync.Mutex
+	var phases []string
+	progress := func(phase string, _ time.Duration) {
+		lock.Lock()
+		defer lock.Unlock()
+		phases = append(phases, phase)
+	}
+	_, err = plonk.Prove(ccs, pk, w, backend.WithContext(context.Background()), backend.WithProgress(progress))
+	assert.NoError(err)
+	assert.ElementsMatch([]string{"solve", "commit lro", "copy constraint ratio", "commit z", "quotient", "commit quotient", "open z", "commit linearized polynomial", "batch opening"}, phases)
+
+	// canceled before proving
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = plonk.Prove(ccs, pk, w, backend.WithContext(ctx))
+	assert.ErrorIs(err, context.Canceled)
+
+	// canceled after solving
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	phases = nil
+	_, err = plonk.Prove(ccs, pk, w, backend.WithContext(ctx), backend.WithProgress(func(phase string, took time.Duration) {
+		progress(phase, took)
+		if phase == "solve" {
+			cancel()
+		}
+	}))
+	assert.ErrorIs(err, context.Canceled)
+	assert.Equal([]string{"solve"}, phases)
+}
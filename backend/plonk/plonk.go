@@ -213,6 +213,44 @@ func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness) error {
 	}
 }
 
+// BatchVerify verifies the proofs of the public witnesses for the same VerifyingKey, with a
+// single pairing check for all their KZG opening claims. If the batch contains invalid proofs,
+// the returned error is a *backend.BatchVerificationError reporting them.
+func BatchVerify(proofs []Proof, vk VerifyingKey, publicWitnesses []witness.Witness) error {
+	switch _vk := vk.(type) {
+	case *plonk_bn254.VerifyingKey:
+		return backend.BatchVerify(proofs, publicWitnesses, func(proofs []*plonk_bn254.Proof, publicWitnesses []fr_bn254.Vector) error {
+			return plonk_bn254.BatchVerify(proofs, _vk, publicWitnesses)
+		})
+	case *plonk_bls12381.VerifyingKey:
+		return backend.BatchVerify(proofs, publicWitnesses, func(proofs []*plonk_bls12381.Proof, publicWitnesses []fr_bls12381.Vector) error {
+			return plonk_bls12381.BatchVerify(proofs, _vk, publicWitnesses)
+		})
+	case *plonk_bls12377.VerifyingKey:
+		return backend.BatchVerify(proofs, publicWitnesses, func(proofs []*plonk_bls12377.Proof, publicWitnesses []fr_bls12377.Vector) error {
+			return plonk_bls12377.BatchVerify(proofs, _vk, publicWitnesses)
+		})
+	case *plonk_bw6761.VerifyingKey:
+		return backend.BatchVerify(proofs, publicWitnesses, func(proofs []*plonk_bw6761.Proof, publicWitnesses []fr_bw6761.Vector) error {
+			return plonk_bw6761.BatchVerify(proofs, _vk, publicWitnesses)
+		})
+	case *plonk_bw6633.VerifyingKey:
+		return backend.BatchVerify(proofs, publicWitnesses, func(proofs []*plonk_bw6633.Proof, publicWitnesses []fr_bw6633.Vector) error {
+			return plonk_bw6633.BatchVerify(proofs, _vk, publicWitnesses)
+		})
+	case *plonk_bls24317.VerifyingKey:
+		return backend.BatchVerify(proofs, publicWitnesses, func(proofs []*plonk_bls24317.Proof, publicWitnesses []fr_bls24317.Vector) error {
+			return plonk_bls24317.BatchVerify(proofs, _vk, publicWitnesses)
+		})
+	case *plonk_bls24315.VerifyingKey:
+		return backend.BatchVerify(proofs, publicWitnesses, func(proofs []*plonk_bls24315.Proof, publicWitnesses []fr_bls24315.Vector) error {
+			return plonk_bls24315.BatchVerify(proofs, _vk, publicWitnesses)
+		})
+	default:
+		panic("unrecognized verifying key type")
+	}
+}
+
 // NewCS instantiate a concrete curved-typed SparseR1CS and return a ConstraintSystem interface
 // This method exists for (de)serialization purposes
 func NewCS(curveID ecc.ID) constraint.ConstraintSystem {
@@ -17,10 +17,9 @@
 package plonkfri
 
 import (
-	"crypto/sha256"
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
-	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fri"
+	"github.com/consensys/gnark/backend/plonkfri/fri"
 	cs "github.com/consensys/gnark/constraint/bls12-377"
 )
 
@@ -78,27 +77,27 @@ type VerifyingKey struct {
 
 	// S commitments to S1, S2, S3
 	SCanonical [3][]fr.Element
-	Spp        [3]fri.ProofOfProximity
+	Spp        [3]ProofOfProximity
 
 	// Id commitments to Id1, Id2, Id3
 	// Id   [3]Commitment
 	IdCanonical [3][]fr.Element
-	Idpp        [3]fri.ProofOfProximity
+	Idpp        [3]ProofOfProximity
 
 	// Commitments to ql, qr, qm, qo prepended with as many zeroes (ones for l) as there are public inputs.
 	// In particular Qk is not complete.
-	Qpp [5]fri.ProofOfProximity // Ql, Qr, Qm, Qo, Qk
-
-	// Iopp scheme (currently one for each size of polynomial)
-	Iopp fri.Iopp
+	Qpp [5]ProofOfProximity // Ql, Qr, Qm, Qo, Qk
 
 	// generator of the group on which the Iopp works. If i is the opening position,
 	// the polynomials will be opened at genOpening^{i}.
 	GenOpening fr.Element
+
+	// FriConfig holds the parameters of the IOP of proximity
+	FriConfig fri.Config
 }
 
-// Setup sets proving and verifying keys
-func Setup(spr *cs.SparseR1CS) (*ProvingKey, *VerifyingKey, error) {
+// Setup sets proving and verifying keys, with the FRI parameters set by opts (see fri.NewConfig)
+func Setup(spr *cs.SparseR1CS, opts ...fri.Option) (*ProvingKey, *VerifyingKey, error) {
 
 	var pk ProvingKey
 	var vk VerifyingKey
@@ -126,13 +125,15 @@ func Setup(spr *cs.SparseR1CS) (*ProvingKey, *VerifyingKey, error) {
 	vk.SizeInv.SetUint64(vk.Size).Inverse(&vk.SizeInv)
 	vk.Generator.Set(&pk.Domain[0].Generator)
 	vk.NbPublicVariables = uint64(len(spr.Public))
+	var err error
+	if vk.FriConfig, err = fri.NewConfig(opts...); err != nil {
+		return nil, nil, err
+	}
 
 	// IOP schemess
-	// The +2 is to handle the blinding.
-	sizeIopp := pk.Domain[0].Cardinality + 2
-	vk.Iopp = fri.RADIX_2_FRI.New(sizeIopp, sha256.New())
+	iopp := vk.iopp()
 	// only there to access the group used in FRI...
-	rho := uint64(fri.GetRho())
+	rho := vk.FriConfig.BlowUpFactor
 	// we multiply by 2 because the IOP is created with size pk.Domain[0].Cardinality + 2 (because
 	// of the blinding), so the domain will be rho*size_domain where size_domain is the next power
 	// of 2 after pk.Domain[0].Cardinality + 2, which is 2*rho*pk.Domain[0].Cardinality
@@ -190,24 +191,23 @@ func Setup(spr *cs.SparseR1CS) (*ProvingKey, *VerifyingKey, error) {
 	copy(pk.CQr, pk.EvaluationQrDomainBigBitReversed)
 	copy(pk.CQm, pk.EvaluationQmDomainBigBitReversed)
 	copy(pk.CQo, pk.EvaluationQoDomainBigBitReversed)
-	var err error
-	vk.Qpp[0], err = vk.Iopp.BuildProofOfProximity(pk.CQl)
+	vk.Qpp[0], err = iopp.BuildProofOfProximity(pk.CQl)
 	if err != nil {
 		return &pk, &vk, err
 	}
-	vk.Qpp[1], err = vk.Iopp.BuildProofOfProximity(pk.CQr)
+	vk.Qpp[1], err = iopp.BuildProofOfProximity(pk.CQr)
 	if err != nil {
 		return &pk, &vk, err
 	}
-	vk.Qpp[2], err = vk.Iopp.BuildProofOfProximity(pk.CQm)
+	vk.Qpp[2], err = iopp.BuildProofOfProximity(pk.CQm)
 	if err != nil {
 		return &pk, &vk, err
 	}
-	vk.Qpp[3], err = vk.Iopp.BuildProofOfProximity(pk.CQo)
+	vk.Qpp[3], err = iopp.BuildProofOfProximity(pk.CQo)
 	if err != nil {
 		return &pk, &vk, err
 	}
-	vk.Qpp[4], err = vk.Iopp.BuildProofOfProximity(pk.CQkIncomplete)
+	vk.Qpp[4], err = iopp.BuildProofOfProximity(pk.CQkIncomplete)
 	if err != nil {
 		return &pk, &vk, err
 	}
@@ -309,6 +309,7 @@ func buildPermutation(spr *cs.SparseR1CS, pk *ProvingKey) {
 func computePermutationPolynomials(pk *ProvingKey, vk *VerifyingKey) error {
 
 	nbElmt := int(pk.Domain[0].Cardinality)
+	iopp := vk.iopp()
 
 	// sID = [1,..,g^{n-1},s,..,s*g^{n-1},s^2,..,s^2*g^{n-1}]
 	pk.LId = getIDSmallDomain(&pk.Domain[0])
@@ -344,15 +345,15 @@ func computePermutationPolynomials(pk *ProvingKey, vk *VerifyingKey) error {
 	copy(vk.IdCanonical[2], pk.EvaluationId3BigDomain)
 
 	var err error
-	vk.Idpp[0], err = vk.Iopp.BuildProofOfProximity(pk.EvaluationId1BigDomain)
+	vk.Idpp[0], err = iopp.BuildProofOfProximity(pk.EvaluationId1BigDomain)
 	if err != nil {
 		return err
 	}
-	vk.Idpp[1], err = vk.Iopp.BuildProofOfProximity(pk.EvaluationId2BigDomain)
+	vk.Idpp[1], err = iopp.BuildProofOfProximity(pk.EvaluationId2BigDomain)
 	if err != nil {
 		return err
 	}
-	vk.Idpp[2], err = vk.Iopp.BuildProofOfProximity(pk.EvaluationId3BigDomain)
+	vk.Idpp[2], err = iopp.BuildProofOfProximity(pk.EvaluationId3BigDomain)
 	if err != nil {
 		return err
 	}
@@ -374,15 +375,15 @@ func computePermutationPolynomials(pk *ProvingKey, vk *VerifyingKey) error {
 	copy(vk.SCanonical[0], pk.EvaluationS1BigDomain[:pk.Domain[0].Cardinality])
 	copy(vk.SCanonical[1], pk.EvaluationS2BigDomain[:pk.Domain[0].Cardinality])
 	copy(vk.SCanonical[2], pk.EvaluationS3BigDomain[:pk.Domain[0].Cardinality])
-	vk.Spp[0], err = vk.Iopp.BuildProofOfProximity(vk.SCanonical[0])
+	vk.Spp[0], err = iopp.BuildProofOfProximity(vk.SCanonical[0])
 	if err != nil {
 		return err
 	}
-	vk.Spp[1], err = vk.Iopp.BuildProofOfProximity(vk.SCanonical[1])
+	vk.Spp[1], err = iopp.BuildProofOfProximity(vk.SCanonical[1])
 	if err != nil {
 		return err
 	}
-	vk.Spp[2], err = vk.Iopp.BuildProofOfProximity(vk.SCanonical[2])
+	vk.Spp[2], err = iopp.BuildProofOfProximity(vk.SCanonical[2])
 	if err != nil {
 		return err
 	}
@@ -412,6 +413,12 @@ func getIDSmallDomain(domain *fft.Domain) []fr.Element {
 	return res
 }
 
+// iopp returns the IOP of proximity of the scheme, for polynomials of size Size+2 (the +2 is to
+// handle the blinding). It's built from the key, and doesn't need to be serialized with it.
+func (vk *VerifyingKey) iopp() *iopp {
+	return newIopp(vk.Size+2, vk.FriConfig)
+}
+
 // NbPublicWitness returns the expected public witness size (number of field elements)
 func (vk *VerifyingKey) NbPublicWitness() int {
 	return int(vk.NbPublicVariables)
@@ -30,8 +30,6 @@ import (
 
 	cs "github.com/consensys/gnark/constraint/bls12-381"
 
-	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fri"
-
 	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/internal/utils"
@@ -39,33 +37,33 @@ import (
 
 type Proof struct {
 	// commitments to the solution vectors
-	LROpp [3]fri.ProofOfProximity
+	LROpp [3]ProofOfProximity
 
 	// commitment to Z (permutation polynomial)
 	// Z   Commitment
-	Zpp fri.ProofOfProximity
+	Zpp ProofOfProximity
 
 	// commitment to h1,h2,h3 such that h = h1 + X**n*h2 + X**2nh3 the quotient polynomial
-	Hpp [3]fri.ProofOfProximity
+	Hpp [3]ProofOfProximity
 
 	// opening proofs for L, R, O
-	OpeningsLROmp [3]fri.OpeningProof
+	OpeningsLROmp [3]OpeningProof
 
 	// opening proofs for Z, Zu
-	OpeningsZmp [2]fri.OpeningProof
+	OpeningsZmp [2]OpeningProof
 
 	// opening proof for H
-	OpeningsHmp [3]fri.OpeningProof
+	OpeningsHmp [3]OpeningProof
 
 	// opening proofs for ql, qr, qm, qo, qk
-	OpeningsQlQrQmQoQkincompletemp [5]fri.OpeningProof
+	OpeningsQlQrQmQoQkincompletemp [5]OpeningProof
 
 	// openings of S1, S2, S3
 	// OpeningsS1S2S3   [3]OpeningProof
-	OpeningsS1S2S3mp [3]fri.OpeningProof
+	OpeningsS1S2S3mp [3]OpeningProof
 
 	// openings of Id1, Id2, Id3
-	OpeningsId1Id2Id3mp [3]fri.OpeningProof
+	OpeningsId1Id2Id3mp [3]OpeningProof
 }
 
 func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (*Proof, error) {
@@ -73,6 +71,7 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	if err != nil {
 		return nil, err
 	}
+	iopp := pk.Vk.iopp()
 
 	var proof Proof
 
@@ -102,15 +101,15 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	if err != nil {
 		return nil, err
 	}
-	proof.LROpp[0], err = pk.Vk.Iopp.BuildProofOfProximity(blindedLCanonical)
+	proof.LROpp[0], err = iopp.BuildProofOfProximity(blindedLCanonical)
 	if err != nil {
 		return nil, err
 	}
-	proof.LROpp[1], err = pk.Vk.Iopp.BuildProofOfProximity(blindedRCanonical)
+	proof.LROpp[1], err = iopp.BuildProofOfProximity(blindedRCanonical)
 	if err != nil {
 		return nil, err
 	}
-	proof.LROpp[2], err = pk.Vk.Iopp.BuildProofOfProximity(blindedOCanonical)
+	proof.LROpp[2], err = iopp.BuildProofOfProximity(blindedOCanonical)
 	if err != nil {
 		return nil, err
 	}
@@ -120,15 +119,14 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	if !ok {
 		return nil, witness.ErrInvalidWitness
 	}
-	dataFiatShamir := make([][fr.Bytes]byte, len(spr.Public)+3)
+	dataFiatShamir := make([][]byte, 0, len(spr.Public)+3)
 	for i := 0; i < len(spr.Public); i++ {
-		copy(dataFiatShamir[i][:], fw[i].Marshal())
+		dataFiatShamir = append(dataFiatShamir, fw[i].Marshal())
 	}
-	copy(dataFiatShamir[len(spr.Public)][:], proof.LROpp[0].ID)
-	copy(dataFiatShamir[len(spr.Public)+1][:], proof.LROpp[1].ID)
-	copy(dataFiatShamir[len(spr.Public)+2][:], proof.LROpp[2].ID)
+	// the Merkle roots are bound entirely, they are longer than fr.Bytes with 64 bytes hashes
+	dataFiatShamir = append(dataFiatShamir, proof.LROpp[0].Commitment(), proof.LROpp[1].Commitment(), proof.LROpp[2].Commitment())
 
-	beta, err := deriveRandomnessFixedSize(&fs, "gamma", dataFiatShamir...)
+	beta, err := deriveRandomness(&fs, "gamma", dataFiatShamir...)
 	if err != nil {
 		return nil, err
 	}
@@ -151,14 +149,14 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	}
 
 	// 4 - commit Z
-	proof.Zpp, err = pk.Vk.Iopp.BuildProofOfProximity(blindedZCanonical)
+	proof.Zpp, err = iopp.BuildProofOfProximity(blindedZCanonical)
 	if err != nil {
 		return nil, err
 	}
 
 	// 5 - compute H
 	// var alpha fr.Element
-	alpha, err := deriveRandomness(&fs, "alpha", proof.Zpp.ID)
+	alpha, err := deriveRandomness(&fs, "alpha", proof.Zpp.Commitment())
 	if err != nil {
 		return nil, err
 	}
@@ -201,15 +199,15 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 		alpha)
 
 	// 6 - commit to H
-	proof.Hpp[0], err = pk.Vk.Iopp.BuildProofOfProximity(h1Canonical)
+	proof.Hpp[0], err = iopp.BuildProofOfProximity(h1Canonical)
 	if err != nil {
 		return nil, err
 	}
-	proof.Hpp[1], err = pk.Vk.Iopp.BuildProofOfProximity(h2Canonical)
+	proof.Hpp[1], err = iopp.BuildProofOfProximity(h2Canonical)
 	if err != nil {
 		return nil, err
 	}
-	proof.Hpp[2], err = pk.Vk.Iopp.BuildProofOfProximity(h3Canonical)
+	proof.Hpp[2], err = iopp.BuildProofOfProximity(h3Canonical)
 	if err != nil {
 		return nil, err
 	}
@@ -218,11 +216,11 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	// compute the size of the domain of evaluation of the committed polynomial,
 	// the opening position. The challenge zeta will be g^{i} where i is the opening
 	// position, and g is the generator of the fri domain.
-	rho := uint64(fri.GetRho())
+	rho := pk.Vk.FriConfig.BlowUpFactor
 	friSize := 2 * rho * pk.Vk.Size
 	var bFriSize big.Int
 	bFriSize.SetInt64(int64(friSize))
-	frOpeningPosition, err := deriveRandomness(&fs, "zeta", proof.Hpp[0].ID, proof.Hpp[1].ID, proof.Hpp[2].ID)
+	frOpeningPosition, err := deriveRandomness(&fs, "zeta", proof.Hpp[0].Commitment(), proof.Hpp[1].Commitment(), proof.Hpp[2].Commitment())
 	if err != nil {
 		return nil, err
 	}
@@ -231,79 +229,79 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	openingPosition := bOpeningPosition.Uint64()
 
 	// ql, qr, qm, qo, qkIncomplete
-	proof.OpeningsQlQrQmQoQkincompletemp[0], err = pk.Vk.Iopp.Open(pk.CQl, openingPosition)
+	proof.OpeningsQlQrQmQoQkincompletemp[0], err = iopp.Open(pk.CQl, openingPosition)
 	if err != nil {
 		return &proof, err
 	}
-	proof.OpeningsQlQrQmQoQkincompletemp[1], err = pk.Vk.Iopp.Open(pk.CQr, openingPosition)
+	proof.OpeningsQlQrQmQoQkincompletemp[1], err = iopp.Open(pk.CQr, openingPosition)
 	if err != nil {
 		return &proof, err
 	}
-	proof.OpeningsQlQrQmQoQkincompletemp[2], err = pk.Vk.Iopp.Open(pk.CQm, openingPosition)
+	proof.OpeningsQlQrQmQoQkincompletemp[2], err = iopp.Open(pk.CQm, openingPosition)
 	if err != nil {
 		return &proof, err
 	}
-	proof.OpeningsQlQrQmQoQkincompletemp[3], err = pk.Vk.Iopp.Open(pk.CQo, openingPosition)
+	proof.OpeningsQlQrQmQoQkincompletemp[3], err = iopp.Open(pk.CQo, openingPosition)
 	if err != nil {
 		return &proof, err
 	}
-	proof.OpeningsQlQrQmQoQkincompletemp[4], err = pk.Vk.Iopp.Open(pk.CQkIncomplete, openingPosition)
+	proof.OpeningsQlQrQmQoQkincompletemp[4], err = iopp.Open(pk.CQkIncomplete, openingPosition)
 	if err != nil {
 		return &proof, err
 	}
 
 	// l, r, o
-	proof.OpeningsLROmp[0], err = pk.Vk.Iopp.Open(blindedLCanonical, openingPosition)
+	proof.OpeningsLROmp[0], err = iopp.Open(blindedLCanonical, openingPosition)
 	if err != nil {
 		return &proof, err
 	}
-	proof.OpeningsLROmp[1], err = pk.Vk.Iopp.Open(blindedRCanonical, openingPosition)
+	proof.OpeningsLROmp[1], err = iopp.Open(blindedRCanonical, openingPosition)
 	if err != nil {
 		return &proof, err
 	}
-	proof.OpeningsLROmp[2], err = pk.Vk.Iopp.Open(blindedOCanonical, openingPosition)
+	proof.OpeningsLROmp[2], err = iopp.Open(blindedOCanonical, openingPosition)
 	if err != nil {
 		return &proof, err
 	}
 
 	// h0, h1, h2
-	proof.OpeningsHmp[0], err = pk.Vk.Iopp.Open(h1Canonical, openingPosition)
+	proof.OpeningsHmp[0], err = iopp.Open(h1Canonical, openingPosition)
 	if err != nil {
 		return &proof, err
 	}
-	proof.OpeningsHmp[1], err = pk.Vk.Iopp.Open(h2Canonical, openingPosition)
+	proof.OpeningsHmp[1], err = iopp.Open(h2Canonical, openingPosition)
 	if err != nil {
 		return &proof, err
 	}
-	proof.OpeningsHmp[2], err = pk.Vk.Iopp.Open(h3Canonical, openingPosition)
+	proof.OpeningsHmp[2], err = iopp.Open(h3Canonical, openingPosition)
 	if err != nil {
 		return &proof, err
 	}
 
 	// s0, s1, s2
-	proof.OpeningsS1S2S3mp[0], err = pk.Vk.Iopp.Open(pk.Vk.SCanonical[0], openingPosition)
+	proof.OpeningsS1S2S3mp[0], err = iopp.Open(pk.Vk.SCanonical[0], openingPosition)
 	if err != nil {
 		return &proof, err
 	}
-	proof.OpeningsS1S2S3mp[1], err = pk.Vk.Iopp.Open(pk.Vk.SCanonical[1], openingPosition)
+	proof.OpeningsS1S2S3mp[1], err = iopp.Open(pk.Vk.SCanonical[1], openingPosition)
 	if err != nil {
 		return &proof, err
 	}
-	proof.OpeningsS1S2S3mp[2], err = pk.Vk.Iopp.Open(pk.Vk.SCanonical[2], openingPosition)
+	proof.OpeningsS1S2S3mp[2], err = iopp.Open(pk.Vk.SCanonical[2], openingPosition)
 	if err != nil {
 		return &proof, err
 	}
 
 	// id0, id1, id2
-	proof.OpeningsId1Id2Id3mp[0], err = pk.Vk.Iopp.Open(pk.Vk.IdCanonical[0], openingPosition)
+	proof.OpeningsId1Id2Id3mp[0], err = iopp.Open(pk.Vk.IdCanonical[0], openingPosition)
 	if err != nil {
 		return &proof, err
 	}
-	proof.OpeningsId1Id2Id3mp[1], err = pk.Vk.Iopp.Open(pk.Vk.IdCanonical[1], openingPosition)
+	proof.OpeningsId1Id2Id3mp[1], err = iopp.Open(pk.Vk.IdCanonical[1], openingPosition)
 	if err != nil {
 		return &proof, err
 	}
-	proof.OpeningsId1Id2Id3mp[2], err = pk.Vk.Iopp.Open(pk.Vk.IdCanonical[2], openingPosition)
+	proof.OpeningsId1Id2Id3mp[2], err = iopp.Open(pk.Vk.IdCanonical[2], openingPosition)
 	if err != nil {
 		return &proof, err
 	}
@@ -312,12 +310,12 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	// to query the "rho" factor from FRI to know by what should be shifted the opening position.
 	// We multiply by 2 because FRI is instantiated with pk.Domain[0].Cardinality+2, which makes
 	// the iop's domain of size rho*(2*pk.Domain[0].Cardinality).
-	shiftedOpeningPosition := (openingPosition + uint64(2*rho)) % friSize
-	proof.OpeningsZmp[0], err = pk.Vk.Iopp.Open(blindedZCanonical, openingPosition)
+	shiftedOpeningPosition := (openingPosition + 2*rho) % friSize
+	proof.OpeningsZmp[0], err = iopp.Open(blindedZCanonical, openingPosition)
 	if err != nil {
 		return &proof, err
 	}
-	proof.OpeningsZmp[1], err = pk.Vk.Iopp.Open(blindedZCanonical, shiftedOpeningPosition)
+	proof.OpeningsZmp[1], err = iopp.Open(blindedZCanonical, shiftedOpeningPosition)
 	if err != nil {
 		return &proof, err
 	}
@@ -664,24 +662,6 @@ func blindPoly(cp []fr.Element, rou, bo uint64) ([]fr.Element, error) {
 	return res, nil
 }
 
-func deriveRandomnessFixedSize(fs *fiatshamir.Transcript, challenge string, data ...[fr.Bytes]byte) (fr.Element, error) {
-
-	var r fr.Element
-	for _, d := range data {
-		if err := fs.Bind(challenge, d[:]); err != nil {
-			return r, err
-		}
-	}
-
-	b, err := fs.ComputeChallenge(challenge)
-	if err != nil {
-		return r, err
-	}
-	r.SetBytes(b)
-	return r, nil
-
-}
-
 func deriveRandomness(fs *fiatshamir.Transcript, challenge string, data ...[]byte) (fr.Element, error) {
 
 	var r fr.Element
@@ -20,7 +20,6 @@ import (
 	"crypto/sha256"
 	"errors"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
-	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr/fri"
 	"math/big"
 
 	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
@@ -29,20 +28,20 @@ import (
 var ErrInvalidAlgebraicRelation = errors.New("algebraic relation does not hold")
 
 func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector) error {
+	iopp := vk.iopp()
 
 	// 0 - derive the challenges with Fiat Shamir
 	hFunc := sha256.New()
 	fs := fiatshamir.NewTranscript(hFunc, "gamma", "beta", "alpha", "zeta")
 
-	dataFiatShamir := make([][fr.Bytes]byte, len(publicWitness)+3)
+	dataFiatShamir := make([][]byte, 0, len(publicWitness)+3)
 	for i := 0; i < len(publicWitness); i++ {
-		copy(dataFiatShamir[i][:], publicWitness[i].Marshal())
+		dataFiatShamir = append(dataFiatShamir, publicWitness[i].Marshal())
 	}
-	copy(dataFiatShamir[len(publicWitness)][:], proof.LROpp[0].ID)
-	copy(dataFiatShamir[len(publicWitness)+1][:], proof.LROpp[1].ID)
-	copy(dataFiatShamir[len(publicWitness)+2][:], proof.LROpp[2].ID)
+	// the Merkle roots are bound entirely, they are longer than fr.Bytes with 64 bytes hashes
+	dataFiatShamir = append(dataFiatShamir, proof.LROpp[0].Commitment(), proof.LROpp[1].Commitment(), proof.LROpp[2].Commitment())
 
-	beta, err := deriveRandomnessFixedSize(&fs, "gamma", dataFiatShamir...)
+	beta, err := deriveRandomness(&fs, "gamma", dataFiatShamir...)
 	if err != nil {
 		return err
 	}
@@ -52,7 +51,7 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector) error {
 		return err
 	}
 
-	alpha, err := deriveRandomness(&fs, "alpha", proof.Zpp.ID)
+	alpha, err := deriveRandomness(&fs, "alpha", proof.Zpp.Commitment())
 	if err != nil {
 		return err
 	}
@@ -60,11 +59,11 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector) error {
 	// compute the size of the domain of evaluation of the committed polynomial,
 	// the opening position. The challenge zeta will be g^{i} where i is the opening
 	// position, and g is the generator of the fri domain.
-	rho := uint64(fri.GetRho())
+	rho := vk.FriConfig.BlowUpFactor
 	friSize := 2 * rho * vk.Size
 	var bFriSize big.Int
 	bFriSize.SetInt64(int64(friSize))
-	frOpeningPosition, err := deriveRandomness(&fs, "zeta", proof.Hpp[0].ID, proof.Hpp[1].ID, proof.Hpp[2].ID)
+	frOpeningPosition, err := deriveRandomness(&fs, "zeta", proof.Hpp[0].Commitment(), proof.Hpp[1].Commitment(), proof.Hpp[2].Commitment())
 	if err != nil {
 		return err
 	}
@@ -72,8 +71,8 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector) error {
 	bOpeningPosition.SetBytes(frOpeningPosition.Marshal()).Mod(&bOpeningPosition, &bFriSize)
 	openingPosition := bOpeningPosition.Uint64()
 
-	shiftedOpeningPosition := (openingPosition + uint64(2*rho)) % friSize
-	err = vk.Iopp.VerifyOpening(shiftedOpeningPosition, proof.OpeningsZmp[1], proof.Zpp)
+	shiftedOpeningPosition := (openingPosition + 2*rho) % friSize
+	err = iopp.VerifyOpening(shiftedOpeningPosition, proof.OpeningsZmp[1], proof.Zpp)
 	if err != nil {
 		return err
 	}
@@ -81,89 +80,89 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector) error {
 	// 1 - verify that the commitments are low degree polynomials
 
 	// ql, qr, qm, qo, qkIncomplete
-	err = vk.Iopp.VerifyProofOfProximity(vk.Qpp[0])
+	err = iopp.VerifyProofOfProximity(vk.Qpp[0])
 	if err != nil {
 		return err
 	}
-	err = vk.Iopp.VerifyProofOfProximity(vk.Qpp[1])
+	err = iopp.VerifyProofOfProximity(vk.Qpp[1])
 	if err != nil {
 		return err
 	}
-	err = vk.Iopp.VerifyProofOfProximity(vk.Qpp[2])
+	err = iopp.VerifyProofOfProximity(vk.Qpp[2])
 	if err != nil {
 		return err
 	}
-	err = vk.Iopp.VerifyProofOfProximity(vk.Qpp[3])
+	err = iopp.VerifyProofOfProximity(vk.Qpp[3])
 	if err != nil {
 		return err
 	}
-	err = vk.Iopp.VerifyProofOfProximity(vk.Qpp[4])
+	err = iopp.VerifyProofOfProximity(vk.Qpp[4])
 	if err != nil {
 		return err
 	}
 
 	// l, r, o
-	err = vk.Iopp.VerifyProofOfProximity(proof.LROpp[0])
+	err = iopp.VerifyProofOfProximity(proof.LROpp[0])
 	if err != nil {
 		return err
 	}
-	err = vk.Iopp.VerifyProofOfProximity(proof.LROpp[1])
+	err = iopp.VerifyProofOfProximity(proof.LROpp[1])
 	if err != nil {
 		return err
 	}
-	err = vk.Iopp.VerifyProofOfProximity(proof.LROpp[2])
+	err = iopp.VerifyProofOfProximity(proof.LROpp[2])
 	if err != nil {
 		return err
 	}
-	err = vk.Iopp.VerifyProofOfProximity(proof.Zpp)
+	err = iopp.VerifyProofOfProximity(proof.Zpp)
 	if err != nil {
 		return err
 	}
 
 	// h0, h1, h2
-	err = vk.Iopp.VerifyProofOfProximity(proof.Hpp[0])
+	err = iopp.VerifyProofOfProximity(proof.Hpp[0])
 	if err != nil {
 		return err
 	}
-	err = vk.Iopp.VerifyProofOfProximity(proof.Hpp[1])
+	err = iopp.VerifyProofOfProximity(proof.Hpp[1])
 	if err != nil {
 		return err
 	}
-	err = vk.Iopp.VerifyProofOfProximity(proof.Hpp[2])
+	err = iopp.VerifyProofOfProximity(proof.Hpp[2])
 	if err != nil {
 		return err
 	}
 
 	// s1, s2, s3
-	err = vk.Iopp.VerifyProofOfProximity(vk.Spp[0])
+	err = iopp.VerifyProofOfProximity(vk.Spp[0])
 	if err != nil {
 		return err
 	}
-	err = vk.Iopp.VerifyProofOfProximity(vk.Spp[1])
+	err = iopp.VerifyProofOfProximity(vk.Spp[1])
 	if err != nil {
 		return err
 	}
-	err = vk.Iopp.VerifyProofOfProximity(vk.Spp[2])
+	err = iopp.VerifyProofOfProximity(vk.Spp[2])
 	if err != nil {
 		return err
 	}
 
 	// id1, id2, id3
-	err = vk.Iopp.VerifyProofOfProximity(vk.Idpp[0])
+	err = iopp.VerifyProofOfProximity(vk.Idpp[0])
 	if err != nil {
 		return err
 	}
-	err = vk.Iopp.VerifyProofOfProximity(vk.Idpp[1])
+	err = iopp.VerifyProofOfProximity(vk.Idpp[1])
 	if err != nil {
 		return err
 	}
-	err = vk.Iopp.VerifyProofOfProximity(vk.Idpp[2])
+	err = iopp.VerifyProofOfProximity(vk.Idpp[2])
 	if err != nil {
 		return err
 	}
 
 	// Z
-	err = vk.Iopp.VerifyProofOfProximity(proof.Zpp)
+	err = iopp.VerifyProofOfProximity(proof.Zpp)
 	if err != nil {
 		return err
 	}
@@ -172,85 +171,85 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector) error {
 
 	// ql, qr, qm, qo, qkIncomplete
 	// openingPosition := uint64(2)
-	err = vk.Iopp.VerifyOpening(openingPosition, proof.OpeningsQlQrQmQoQkincompletemp[0], vk.Qpp[0])
+	err = iopp.VerifyOpening(openingPosition, proof.OpeningsQlQrQmQoQkincompletemp[0], vk.Qpp[0])
 	if err != nil {
 		return err
 	}
-	err = vk.Iopp.VerifyOpening(openingPosition, proof.OpeningsQlQrQmQoQkincompletemp[1], vk.Qpp[1])
+	err = iopp.VerifyOpening(openingPosition, proof.OpeningsQlQrQmQoQkincompletemp[1], vk.Qpp[1])
 	if err != nil {
 		return err
 	}
-	err = vk.Iopp.VerifyOpening(openingPosition, proof.OpeningsQlQrQmQoQkincompletemp[2], vk.Qpp[2])
+	err = iopp.VerifyOpening(openingPosition, proof.OpeningsQlQrQmQoQkincompletemp[2], vk.Qpp[2])
 	if err != nil {
 		return err
 	}
-	err = vk.Iopp.VerifyOpening(openingPosition, proof.OpeningsQlQrQmQoQkincompletemp[3], vk.Qpp[3])
+	err = iopp.VerifyOpening(openingPosition, proof.OpeningsQlQrQmQoQkincompletemp[3], vk.Qpp[3])
 	if err != nil {
 		return err
 	}
-	err = vk.Iopp.VerifyOpening(openingPosition, proof.OpeningsQlQrQmQoQkincompletemp[4], vk.Qpp[4])
+	err = iopp.VerifyOpening(openingPosition, proof.OpeningsQlQrQmQoQkincompletemp[4], vk.Qpp[4])
 	if err != nil {
 		return err
 	}
 
 	// l, r, o
-	err = vk.Iopp.VerifyOpening(openingPosition, proof.OpeningsLROmp[0], proof.LROpp[0])
+	err = iopp.VerifyOpening(openingPosition, proof.OpeningsLROmp[0], proof.LROpp[0])
 	if err != nil {
 		return err
 	}
-	err = vk.Iopp.VerifyOpening(openingPosition, proof.OpeningsLROmp[1], proof.LROpp[1])
+	err = iopp.VerifyOpening(openingPosition, proof.OpeningsLROmp[1], proof.LROpp[1])
 	if err != nil {
 		return err
 	}
-	err = vk.Iopp.VerifyOpening(openingPosition, proof.OpeningsLROmp[2], proof.LROpp[2])
+	err = iopp.VerifyOpening(openingPosition, proof.OpeningsLROmp[2], proof.LROpp[2])
 	if err != nil {
 		return err
 	}
 
 	// h0, h1, h2
-	err = vk.Iopp.VerifyOpening(openingPosition, proof.OpeningsHmp[0], proof.Hpp[0])
+	err = iopp.VerifyOpening(openingPosition, proof.OpeningsHmp[0], proof.Hpp[0])
 	if err != nil {
 		return err
 	}
-	err = vk.Iopp.VerifyOpening(openingPosition, proof.OpeningsHmp[1], proof.Hpp[1])
+	err = iopp.VerifyOpening(openingPosition, proof.OpeningsHmp[1], proof.Hpp[1])
 	if err != nil {
 		return err
 	}
-	err = vk.Iopp.VerifyOpening(openingPosition, proof.OpeningsHmp[2], proof.Hpp[2])
+	err = iopp.VerifyOpening(openingPosition, proof.OpeningsHmp[2], proof.Hpp[2])
 	if err != nil {
 		return err
 	}
 
 	// s0, s1, s2
-	err = vk.Iopp.VerifyOpening(openingPosition, proof.OpeningsS1S2S3mp[0], vk.Spp[0])
+	err = iopp.VerifyOpening(openingPosition, proof.OpeningsS1S2S3mp[0], vk.Spp[0])
 	if err != nil {
 		return err
 	}
-	err = vk.Iopp.VerifyOpening(openingPosition, proof.OpeningsS1S2S3mp[1], vk.Spp[1])
+	err = iopp.VerifyOpening(openingPosition, proof.OpeningsS1S2S3mp[1], vk.Spp[1])
 	if err != nil {
 		return err
 	}
-	err = vk.Iopp.VerifyOpening(openingPosition, proof.OpeningsS1S2S3mp[2], vk.Spp[2])
+	err = iopp.VerifyOpening(openingPosition, proof.OpeningsS1S2S3mp[2], vk.Spp[2])
 	if err != nil {
 		return err
 	}
 
 	// id0, id1, id2
-	err = vk.Iopp.VerifyOpening(openingPosition, proof.OpeningsId1Id2Id3mp[0], vk.Idpp[0])
+	err = iopp.VerifyOpening(openingPosition, proof.OpeningsId1Id2Id3mp[0], vk.Idpp[0])
 	if err != nil {
 		return err
 	}
-	err = vk.Iopp.VerifyOpening(openingPosition, proof.OpeningsId1Id2Id3mp[1], vk.Idpp[1])
+	err = iopp.VerifyOpening(openingPosition, proof.OpeningsId1Id2Id3mp[1], vk.Idpp[1])
 	if err != nil {
 		return err
 	}
-	err = vk.Iopp.VerifyOpening(openingPosition, proof.OpeningsId1Id2Id3mp[2], vk.Idpp[2])
+	err = iopp.VerifyOpening(openingPosition, proof.OpeningsId1Id2Id3mp[2], vk.Idpp[2])
 	if err != nil {
 		return err
 	}
 
 	// Z, Zshift
-	err = vk.Iopp.VerifyOpening(openingPosition, proof.OpeningsZmp[0], proof.Zpp)
+	err = iopp.VerifyOpening(openingPosition, proof.OpeningsZmp[0], proof.Zpp)
 	if err != nil {
 		return err
 	}
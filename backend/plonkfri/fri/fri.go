@@ -0,0 +1,154 @@
+// Package fri holds the parameters of the FRI IOP of proximity used by the PLONK-FRI backend to
+// commit to polynomials, and estimates the soundness of the proofs they provide.
+//
+// The parameters are chosen at setup, with the options of this package, and recorded in the
+// verifying key:
+//
+//	pk, vk, err := plonkfri.Setup(ccs, fri.WithNbQueries(60), fri.WithGrindingBits(16))
+package fri
+
+import (
+	"crypto"
+	_ "crypto/sha256" // registers the hash functions accepted by WithHash
+	_ "crypto/sha512"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	_ "golang.org/x/crypto/blake2b"
+	_ "golang.org/x/crypto/sha3"
+)
+
+// maxGrindingBits bounds the proof of work of the prover, which computes 2^GrindingBits hashes on
+// average for each proof of proximity.
+const maxGrindingBits = 32
+
+// Option defines option for altering the FRI parameters of the PLONK-FRI setup. See the
+// descriptions of functions returning instances of this type for implemented options.
+type Option func(*Config) error
+
+// Config holds the parameters of FRI with the options applied.
+type Config struct {
+	// BlowUpFactor is the ratio of the size of the evaluation domain of the committed
+	// polynomials to their degree bound: the inverse of the rate of the Reed-Solomon code.
+	BlowUpFactor uint64
+
+	// NbQueries is the number of positions at which the verifier checks the foldings.
+	NbQueries uint64
+
+	// GrindingBits is the number of leading zero bits of the proof of work the prover computes
+	// before the queries are derived.
+	GrindingBits uint64
+
+	// Hash is used for the Merkle trees and the Fiat-Shamir transcript of FRI.
+	Hash crypto.Hash
+}
+
+// NewConfig returns the default Config with the options opts applied: a blow-up factor of 8, 40
+// queries, 8 bits of grinding and SHA-256. See ProximitySecurityBits for what they bound.
+func NewConfig(opts ...Option) (Config, error) {
+	cfg := Config{
+		BlowUpFactor: 8,
+		NbQueries:    40,
+		GrindingBits: 8,
+		Hash:         crypto.SHA256,
+	}
+	for _, option := range opts {
+		if err := option(&cfg); err != nil {
+			return Config{}, err
+		}
+	}
+	return cfg, nil
+}
+
+// WithBlowUpFactor sets the blow-up factor, a power of 2 greater than 1. Larger factors make
+// each query more significant, at the cost of a prover working on larger domains.
+func WithBlowUpFactor(blowUpFactor uint64) Option {
+	return func(cfg *Config) error {
+		cfg.BlowUpFactor = blowUpFactor
+		return cfg.Check()
+	}
+}
+
+// WithNbQueries sets the number of queries, which the size of the proofs is proportional to.
+func WithNbQueries(nbQueries uint64) Option {
+	return func(cfg *Config) error {
+		cfg.NbQueries = nbQueries
+		return cfg.Check()
+	}
+}
+
+// WithGrindingBits sets the number of bits of the proof of work of the prover, at most 32.
+func WithGrindingBits(grindingBits uint64) Option {
+	return func(cfg *Config) error {
+		cfg.GrindingBits = grindingBits
+		return cfg.Check()
+	}
+}
+
+// WithHash sets the hash function of the Merkle trees and of the Fiat-Shamir transcript. It must
+// be linked in the binary; SHA-2, SHA-3 and BLAKE2b are.
+func WithHash(h crypto.Hash) Option {
+	return func(cfg *Config) error {
+		cfg.Hash = h
+		return cfg.Check()
+	}
+}
+
+// Check returns an error if the parameters are invalid. They're checked by the options, and
+// when a verifying key is read.
+func (cfg *Config) Check() error {
+	if cfg.BlowUpFactor < 2 || bits.OnesCount64(cfg.BlowUpFactor) != 1 {
+		return fmt.Errorf("the blow-up factor must be a power of 2 greater than 1, got %d", cfg.BlowUpFactor)
+	}
+	if cfg.NbQueries == 0 {
+		return errors.New("the number of queries must be positive")
+	}
+	if cfg.GrindingBits > maxGrindingBits {
+		return fmt.Errorf("the number of grinding bits must be at most %d, got %d", maxGrindingBits, cfg.GrindingBits)
+	}
+	if !cfg.Hash.Available() {
+		return fmt.Errorf("hash function %d is not available", cfg.Hash)
+	}
+	return nil
+}
+
+// ProximitySecurityBits returns the conjectured soundness of the FRI IOP of proximity alone with
+// these parameters, in bits.
+//
+// Under the usual conjecture on the soundness of FRI (see the ethSTARK documentation), each
+// query with a blow-up factor ρ divides the probability of accepting a false proof by ρ, and the
+// grinding by 2^GrindingBits, which gives NbQueries·log₂(ρ) + GrindingBits bits. The result is
+// capped by the collision resistance of the hash function. The scalar fields of the supported
+// curves are large enough (more than 250 bits) not to limit it. cfg must be valid.
+//
+// It isn't the security of the PLONK-FRI proofs, see SecurityBits.
+func (cfg *Config) ProximitySecurityBits() int {
+	security := int(cfg.NbQueries)*bits.TrailingZeros64(cfg.BlowUpFactor) + int(cfg.GrindingBits)
+	if collision := cfg.Hash.Size() * 4; collision < security {
+		return collision
+	}
+	return security
+}
+
+// SecurityBits returns the conjectured soundness of the PLONK-FRI proofs with these parameters,
+// in bits, for circuits of size size (the Size of the verifying key, a power of 2): the minimum
+// of ProximitySecurityBits and of the soundness of the check of the PLONK identity.
+//
+// The verifier checks the PLONK identity, of degree less than deg = 4(size+2), at a single
+// position of the domain of size 2ρ·size on which the polynomials are committed to, so a false
+// proof passes that check with probability about deg/(2ρ·size), whatever the number of queries.
+// It is the bound of the scheme for all valid parameters, and it's only a few bits. cfg must be
+// valid.
+func (cfg *Config) SecurityBits(size uint64) int {
+	security := cfg.ProximitySecurityBits()
+	// ⌊log₂(2ρ·size/deg)⌋, or 0 if the identity may hold on the whole domain
+	identity := bits.Len64(2*cfg.BlowUpFactor*size/(4*(size+2))) - 1
+	if identity < 0 {
+		identity = 0
+	}
+	if identity < security {
+		return identity
+	}
+	return security
+}
@@ -0,0 +1,51 @@
+package fri_test
+
+import (
+	"crypto"
+	"testing"
+
+	"github.com/consensys/gnark/backend/plonkfri/fri"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig(t *testing.T) {
+	assert := require.New(t)
+
+	cfg, err := fri.NewConfig()
+	assert.NoError(err)
+	assert.Equal(128, cfg.ProximitySecurityBits())
+
+	cfg, err = fri.NewConfig(fri.WithBlowUpFactor(4), fri.WithNbQueries(30), fri.WithGrindingBits(20), fri.WithHash(crypto.SHA3_512))
+	assert.NoError(err)
+	assert.Equal(fri.Config{BlowUpFactor: 4, NbQueries: 30, GrindingBits: 20, Hash: crypto.SHA3_512}, cfg)
+	assert.Equal(80, cfg.ProximitySecurityBits())
+
+	// capped by the collision resistance of the hash function
+	cfg, err = fri.NewConfig(fri.WithNbQueries(100), fri.WithHash(crypto.SHA512_224))
+	assert.NoError(err)
+	assert.Equal(112, cfg.ProximitySecurityBits())
+
+	// the check of the PLONK identity bounds the soundness of the proofs
+	cfg, err = fri.NewConfig()
+	assert.NoError(err)
+	assert.Equal(1, cfg.SecurityBits(1<<10))
+	assert.Equal(0, cfg.SecurityBits(1))
+	cfg, err = fri.NewConfig(fri.WithBlowUpFactor(1 << 10))
+	assert.NoError(err)
+	assert.Equal(8, cfg.SecurityBits(1<<10))
+	cfg, err = fri.NewConfig(fri.WithBlowUpFactor(1<<20), fri.WithNbQueries(1), fri.WithGrindingBits(0), fri.WithHash(crypto.SHA512_224))
+	assert.NoError(err)
+	assert.Equal(20, cfg.ProximitySecurityBits())
+	assert.Equal(18, cfg.SecurityBits(1<<20))
+
+	for _, opt := range []fri.Option{
+		fri.WithBlowUpFactor(1),
+		fri.WithBlowUpFactor(6),
+		fri.WithNbQueries(0),
+		fri.WithGrindingBits(33),
+		fri.WithHash(crypto.MD4), // not linked
+	} {
+		_, err = fri.NewConfig(opt)
+		assert.Error(err)
+	}
+}
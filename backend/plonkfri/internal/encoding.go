@@ -0,0 +1,216 @@
+package internal
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// EncodingVersion is the version of the encoding of the PLONK-FRI keys and proofs, written in
+// their header. It changes with the encoding, and the readers reject the other versions.
+const EncodingVersion = 1
+
+// headerMagic starts the encoding of the PLONK-FRI keys and proofs
+const headerMagic = "gnark plonkfri"
+
+// ErrEncodingVersion is returned when reading keys or proofs written with another version of the
+// encoding.
+var ErrEncodingVersion = errors.New("unsupported version of the PLONK-FRI encoding")
+
+// maxLength bounds the lengths read by a Reader.
+const maxLength = 1 << 28
+
+// maxInitialCap bounds the capacity allocated for a slice before its elements are read, so that an
+// invalid length doesn't trigger a huge allocation: the slice grows as its elements are read.
+const maxInitialCap = 1 << 10
+
+// InitialCap returns the capacity to allocate for a slice of n elements before reading them.
+func InitialCap(n int) int {
+	if n > maxInitialCap {
+		return maxInitialCap
+	}
+	return n
+}
+
+// Writer writes the elements of PLONK-FRI keys and proofs to an io.Writer. It records the number
+// of bytes written and the first error, after which the writes are no-ops.
+type Writer struct {
+	w   io.Writer
+	N   int64
+	Err error
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Header writes the header of a key or a proof: a magic string and the EncodingVersion.
+func (w *Writer) Header() {
+	w.Raw([]byte(headerMagic))
+	w.Uint64(EncodingVersion)
+}
+
+// Raw writes b as is.
+func (w *Writer) Raw(b []byte) {
+	if w.Err != nil {
+		return
+	}
+	n, err := w.w.Write(b)
+	w.N += int64(n)
+	w.Err = err
+}
+
+func (w *Writer) Uint64(v uint64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	w.Raw(b[:])
+}
+
+// Bytes writes b prefixed by its length.
+func (w *Writer) Bytes(b []byte) {
+	w.Uint64(uint64(len(b)))
+	w.Raw(b)
+}
+
+// BytesSlice writes b prefixed by its length.
+func (w *Writer) BytesSlice(b [][]byte) {
+	w.Uint64(uint64(len(b)))
+	for i := range b {
+		w.Bytes(b[i])
+	}
+}
+
+// Int64Slice writes s prefixed by its length.
+func (w *Writer) Int64Slice(s []int64) {
+	w.Uint64(uint64(len(s)))
+	for _, v := range s {
+		w.Uint64(uint64(v))
+	}
+}
+
+// WriterTo writes v with its WriteTo method.
+func (w *Writer) WriterTo(v io.WriterTo) {
+	if w.Err != nil {
+		return
+	}
+	n, err := v.WriteTo(w.w)
+	w.N += n
+	w.Err = err
+}
+
+// Reader reads the elements written by a Writer from an io.Reader. It records the number of bytes
+// read and the first error, after which the reads return zero values.
+type Reader struct {
+	r   io.Reader
+	N   int64
+	Err error
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: r}
+}
+
+// Header reads a header written by Writer.Header, and checks its version.
+func (r *Reader) Header() {
+	magic := make([]byte, len(headerMagic))
+	r.Raw(magic)
+	if r.Err == nil && string(magic) != headerMagic {
+		r.Err = errors.New("not a PLONK-FRI key or proof: invalid header")
+	}
+	if version := r.Uint64(); r.Err == nil && version != EncodingVersion {
+		r.Err = fmt.Errorf("%w: got version %d, expected %d", ErrEncodingVersion, version, EncodingVersion)
+	}
+}
+
+// Raw reads len(b) bytes in b.
+func (r *Reader) Raw(b []byte) {
+	if r.Err != nil {
+		return
+	}
+	n, err := io.ReadFull(r.r, b)
+	r.N += int64(n)
+	r.Err = err
+}
+
+func (r *Reader) Uint64() uint64 {
+	var b [8]byte
+	r.Raw(b[:])
+	if r.Err != nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(b[:])
+}
+
+// Length reads a length written by Writer.Bytes, Writer.BytesSlice or Writer.Int64Slice.
+func (r *Reader) Length() int {
+	n := r.Uint64()
+	if r.Err == nil && n > maxLength {
+		r.Err = fmt.Errorf("length %d too large", n)
+	}
+	if r.Err != nil {
+		return 0
+	}
+	return int(n)
+}
+
+// Bytes reads a slice written by Writer.Bytes. Empty slices are read as nil.
+func (r *Reader) Bytes() []byte {
+	n := r.Length()
+	if n == 0 {
+		return nil
+	}
+	b := make([]byte, 0, InitialCap(n))
+	for len(b) < n && r.Err == nil {
+		chunk := n - len(b)
+		if chunk > maxInitialCap {
+			chunk = maxInitialCap
+		}
+		b = append(b, make([]byte, chunk)...)
+		r.Raw(b[len(b)-chunk:])
+	}
+	return b
+}
+
+// BytesSlice reads a slice written by Writer.BytesSlice. Empty slices are read as nil.
+func (r *Reader) BytesSlice() [][]byte {
+	n := r.Length()
+	if n == 0 {
+		return nil
+	}
+	b := make([][]byte, 0, InitialCap(n))
+	for i := 0; i < n && r.Err == nil; i++ {
+		b = append(b, r.Bytes())
+	}
+	return b
+}
+
+// Int64Slice reads a slice written by Writer.Int64Slice. Empty slices are read as nil.
+func (r *Reader) Int64Slice() []int64 {
+	n := r.Length()
+	if n == 0 {
+		return nil
+	}
+	s := make([]int64, 0, InitialCap(n))
+	for i := 0; i < n && r.Err == nil; i++ {
+		s = append(s, int64(r.Uint64()))
+	}
+	return s
+}
+
+// ReaderFrom reads v with its ReadFrom method.
+func (r *Reader) ReaderFrom(v io.ReaderFrom) {
+	if r.Err != nil {
+		return
+	}
+	n, err := v.ReadFrom(r.r)
+	r.N += n
+	r.Err = err
+}
+
+// Check records err if no error occurred before.
+func (r *Reader) Check(err error) {
+	if r.Err == nil {
+		r.Err = err
+	}
+}
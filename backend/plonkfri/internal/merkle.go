@@ -0,0 +1,118 @@
+package internal
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/accumulator/merkletree"
+)
+
+// MerkleTree is a binary Merkle tree whose number of leaves is a power of 2. It hashes the leaves
+// and the nodes as gnark-crypto's merkletree does, which verifies its paths, but it keeps all the
+// nodes so that the paths of many leaves are computed without rebuilding the tree.
+type MerkleTree struct {
+	// nodes[1] is the root, the children of nodes[i] are nodes[2i] and nodes[2i+1], and the
+	// hashes of the leaves are at the end
+	nodes [][]byte
+}
+
+// NewMerkleTree builds the Merkle tree of leaves, whose length must be a power of 2.
+func NewMerkleTree(h hash.Hash, leaves [][]byte) *MerkleTree {
+	n := len(leaves)
+	if n == 0 || n&(n-1) != 0 {
+		panic("the number of leaves must be a power of 2")
+	}
+	t := &MerkleTree{nodes: make([][]byte, 2*n)}
+	for i := range leaves {
+		t.nodes[n+i] = sum(h, leaves[i])
+	}
+	for i := n - 1; i > 0; i-- {
+		t.nodes[i] = sum(h, t.nodes[2*i], t.nodes[2*i+1])
+	}
+	return t
+}
+
+// Root returns the root of the tree.
+func (t *MerkleTree) Root() []byte {
+	return t.nodes[1]
+}
+
+// Path returns the Merkle path of the i-th leaf: the siblings of the nodes from the leaf to the
+// root.
+func (t *MerkleTree) Path(i int) [][]byte {
+	var path [][]byte
+	for j := len(t.nodes)/2 + i; j > 1; j /= 2 {
+		path = append(path, t.nodes[j^1])
+	}
+	return path
+}
+
+// VerifyMerklePath returns true if path is the Merkle path of leaf at index i in a tree of
+// nbLeaves leaves with the given root. nbLeaves must be a power of 2.
+func VerifyMerklePath(h hash.Hash, root, leaf []byte, i, nbLeaves int, path [][]byte) bool {
+	if i < 0 || nbLeaves <= 0 || nbLeaves&(nbLeaves-1) != 0 || len(path) != bits.TrailingZeros(uint(nbLeaves)) {
+		return false
+	}
+	proofSet := make([][]byte, 0, len(path)+1)
+	proofSet = append(proofSet, leaf)
+	proofSet = append(proofSet, path...)
+	return merkletree.VerifyProof(h, root, proofSet, uint64(i), uint64(nbLeaves))
+}
+
+func sum(h hash.Hash, data ...[]byte) []byte {
+	h.Reset()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+// Grind returns the smallest nonce such that ProofOfWork(h, seed, nonce) starts with nbBits zero
+// bits.
+func Grind(h hash.Hash, seed []byte, nbBits uint64) uint64 {
+	for nonce := uint64(0); ; nonce++ {
+		if LeadingZeros(ProofOfWork(h, seed, nonce)) >= nbBits {
+			return nonce
+		}
+	}
+}
+
+// ProofOfWork returns the hash of seed and nonce.
+func ProofOfWork(h hash.Hash, seed []byte, nonce uint64) []byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], nonce)
+	h.Reset()
+	h.Write(seed)
+	h.Write(b[:])
+	return h.Sum(nil)
+}
+
+// LeadingZeros returns the number of leading zero bits of b.
+func LeadingZeros(b []byte) uint64 {
+	for i := range b {
+		if b[i] != 0 {
+			return uint64(8*i + bits.LeadingZeros8(b[i]))
+		}
+	}
+	return uint64(8 * len(b))
+}
+
+// QueryPositions derives nbQueries positions in [0, bound) from seed. bound must be a power of 2.
+func QueryPositions(h hash.Hash, seed []byte, nbQueries, bound uint64) []uint64 {
+	var b [8]byte
+	positions := make([]uint64, nbQueries)
+	for i := range positions {
+		binary.BigEndian.PutUint64(b[:], uint64(i))
+		h.Reset()
+		h.Write(seed)
+		h.Write(b[:])
+		d := h.Sum(nil)
+		var v uint64
+		for j := 0; j < 8 && j < len(d); j++ {
+			v = v<<8 | uint64(d[j])
+		}
+		positions[i] = v & (bound - 1)
+	}
+	return positions
+}
@@ -0,0 +1,39 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/accumulator/merkletree"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerkleTree(t *testing.T) {
+	assert := require.New(t)
+	h := sha256.New()
+
+	leaves := make([][]byte, 16)
+	for i := range leaves {
+		leaves[i] = []byte(fmt.Sprintf("leaf %d", i))
+	}
+	tree := NewMerkleTree(h, leaves)
+
+	for i := range leaves {
+		// the tree is the one of gnark-crypto's merkletree
+		ref := merkletree.New(sha256.New())
+		assert.NoError(ref.SetIndex(uint64(i)))
+		for _, leaf := range leaves {
+			ref.Push(leaf)
+		}
+		root, proofSet, _, _ := ref.Prove()
+		assert.Equal(root, tree.Root())
+		assert.Equal(proofSet[1:], tree.Path(i))
+
+		path := tree.Path(i)
+		assert.True(VerifyMerklePath(h, tree.Root(), leaves[i], i, len(leaves), path))
+		assert.False(VerifyMerklePath(h, tree.Root(), leaves[(i+1)%len(leaves)], i, len(leaves), path))
+		assert.False(VerifyMerklePath(h, tree.Root(), leaves[i], i, len(leaves), path[1:]))
+		assert.False(VerifyMerklePath(h, tree.Root(), leaves[i], i, 2*len(leaves), path))
+	}
+}
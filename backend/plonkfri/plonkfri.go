@@ -17,7 +17,11 @@
 package plonkfri
 
 import (
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/plonkfri/fri"
 	"github.com/consensys/gnark/constraint"
 
 	"github.com/consensys/gnark/backend/witness"
@@ -44,22 +48,28 @@ import (
 	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	fr_bw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr"
 	fr_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+
+	gnarkio "github.com/consensys/gnark/io"
 )
 
 // Proof represents a Plonk proof generated by plonk.Prove
 //
 // it's underlying implementation is curve specific (see gnark/internal/backend)
 type Proof interface {
-	// io.WriterTo
-	// io.ReaderFrom
+	io.WriterTo
+	io.ReaderFrom
+	gnarkio.WriterRawTo
+	gnarkio.UnsafeReaderFrom
 }
 
 // ProvingKey represents a plonk ProvingKey
 //
 // it's underlying implementation is strongly typed with the curve (see gnark/internal/backend)
 type ProvingKey interface {
-	// io.WriterTo
-	// io.ReaderFrom
+	io.WriterTo
+	io.ReaderFrom
+	gnarkio.WriterRawTo
+	gnarkio.UnsafeReaderFrom
 	VerifyingKey() interface{}
 }
 
@@ -67,30 +77,35 @@ type ProvingKey interface {
 //
 // it's underlying implementation is strongly typed with the curve (see gnark/internal/backend)
 type VerifyingKey interface {
-	// io.WriterTo
-	// io.ReaderFrom
-	// InitKZG(srs kzg.SRS) error
+	io.WriterTo
+	io.ReaderFrom
+	gnarkio.WriterRawTo
+	gnarkio.UnsafeReaderFrom
 	NbPublicWitness() int // number of elements expected in the public witness
 }
 
 // Setup prepares the public data associated to a circuit + public inputs.
-func Setup(ccs constraint.ConstraintSystem) (ProvingKey, VerifyingKey, error) {
+//
+// The parameters of FRI (blow-up factor, number of queries, grinding, hash function) are set by
+// opts, and recorded in the verifying key. fri.Config.SecurityBits estimates the soundness of the
+// proofs they give.
+func Setup(ccs constraint.ConstraintSystem, opts ...fri.Option) (ProvingKey, VerifyingKey, error) {
 
 	switch tccs := ccs.(type) {
 	case *cs_bn254.SparseR1CS:
-		return plonk_bn254.Setup(tccs)
+		return plonk_bn254.Setup(tccs, opts...)
 	case *cs_bls12381.SparseR1CS:
-		return plonk_bls12381.Setup(tccs)
+		return plonk_bls12381.Setup(tccs, opts...)
 	case *cs_bls12377.SparseR1CS:
-		return plonk_bls12377.Setup(tccs)
+		return plonk_bls12377.Setup(tccs, opts...)
 	case *cs_bw6761.SparseR1CS:
-		return plonk_bw6761.Setup(tccs)
+		return plonk_bw6761.Setup(tccs, opts...)
 	case *cs_bls24315.SparseR1CS:
-		return plonk_bls24315.Setup(tccs)
+		return plonk_bls24315.Setup(tccs, opts...)
 	case *cs_bw6633.SparseR1CS:
-		return plonk_bw6633.Setup(tccs)
+		return plonk_bw6633.Setup(tccs, opts...)
 	case *cs_bls24317.SparseR1CS:
-		return plonk_bls24317.Setup(tccs)
+		return plonk_bls24317.Setup(tccs, opts...)
 	default:
 		panic("unrecognized SparseR1CS curve type")
 	}
@@ -189,3 +204,81 @@ func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness) error {
 		panic("unrecognized proof type")
 	}
 }
+
+// NewProvingKey instantiates a curve-typed ProvingKey and returns an interface
+// This function exists for serialization purposes
+func NewProvingKey(curveID ecc.ID) ProvingKey {
+	var pk ProvingKey
+	switch curveID {
+	case ecc.BN254:
+		pk = &plonk_bn254.ProvingKey{}
+	case ecc.BLS12_377:
+		pk = &plonk_bls12377.ProvingKey{}
+	case ecc.BLS12_381:
+		pk = &plonk_bls12381.ProvingKey{}
+	case ecc.BW6_761:
+		pk = &plonk_bw6761.ProvingKey{}
+	case ecc.BLS24_317:
+		pk = &plonk_bls24317.ProvingKey{}
+	case ecc.BLS24_315:
+		pk = &plonk_bls24315.ProvingKey{}
+	case ecc.BW6_633:
+		pk = &plonk_bw6633.ProvingKey{}
+	default:
+		panic("not implemented")
+	}
+
+	return pk
+}
+
+// NewProof instantiates a curve-typed Proof and returns an interface
+// This function exists for serialization purposes
+func NewProof(curveID ecc.ID) Proof {
+	var proof Proof
+	switch curveID {
+	case ecc.BN254:
+		proof = &plonk_bn254.Proof{}
+	case ecc.BLS12_377:
+		proof = &plonk_bls12377.Proof{}
+	case ecc.BLS12_381:
+		proof = &plonk_bls12381.Proof{}
+	case ecc.BW6_761:
+		proof = &plonk_bw6761.Proof{}
+	case ecc.BLS24_317:
+		proof = &plonk_bls24317.Proof{}
+	case ecc.BLS24_315:
+		proof = &plonk_bls24315.Proof{}
+	case ecc.BW6_633:
+		proof = &plonk_bw6633.Proof{}
+	default:
+		panic("not implemented")
+	}
+
+	return proof
+}
+
+// NewVerifyingKey instantiates a curve-typed VerifyingKey and returns an interface
+// This function exists for serialization purposes
+func NewVerifyingKey(curveID ecc.ID) VerifyingKey {
+	var vk VerifyingKey
+	switch curveID {
+	case ecc.BN254:
+		vk = &plonk_bn254.VerifyingKey{}
+	case ecc.BLS12_377:
+		vk = &plonk_bls12377.VerifyingKey{}
+	case ecc.BLS12_381:
+		vk = &plonk_bls12381.VerifyingKey{}
+	case ecc.BW6_761:
+		vk = &plonk_bw6761.VerifyingKey{}
+	case ecc.BLS24_317:
+		vk = &plonk_bls24317.VerifyingKey{}
+	case ecc.BLS24_315:
+		vk = &plonk_bls24315.VerifyingKey{}
+	case ecc.BW6_633:
+		vk = &plonk_bw6633.VerifyingKey{}
+	default:
+		panic("not implemented")
+	}
+
+	return vk
+}
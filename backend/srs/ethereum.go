@@ -0,0 +1,106 @@
+package srs
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	kzg_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/kzg"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// ethereumTranscript is the part of the transcript.json of the Ethereum KZG ceremony read by
+// ReadEthereumKZG. The transcript holds several sub-ceremonies of different sizes; the points are
+// hex encoded in the compressed format of ZCash, which is the one of gnark-crypto.
+type ethereumTranscript struct {
+	Transcripts []ethereumSubTranscript `json:"transcripts"`
+}
+
+type ethereumSubTranscript struct {
+	NumG1Powers int `json:"numG1Powers"`
+	NumG2Powers int `json:"numG2Powers"`
+	PowersOfTau struct {
+		G1Powers []string `json:"G1Powers"`
+		G2Powers []string `json:"G2Powers"`
+	} `json:"powersOfTau"`
+}
+
+// ReadEthereumKZG reads the transcript.json of the Ethereum KZG ceremony and returns a BLS12-381
+// KZG SRS with the first size powers of τ in G₁, for plonk.Setup. The powers are taken from the
+// smallest sub-ceremony holding at least size powers.
+func ReadEthereumKZG(r io.Reader, size uint64) (kzg.SRS, error) {
+	var transcript ethereumTranscript
+	if err := json.NewDecoder(r).Decode(&transcript); err != nil {
+		return nil, fmt.Errorf("decoding transcript: %w", err)
+	}
+
+	// select the smallest sub-ceremony large enough
+	selected := -1
+	for i, t := range transcript.Transcripts {
+		if uint64(t.NumG1Powers) >= size && (selected == -1 || t.NumG1Powers < transcript.Transcripts[selected].NumG1Powers) {
+			selected = i
+		}
+	}
+	if size < 2 || selected == -1 {
+		return nil, fmt.Errorf("invalid size %d: no sub-ceremony holds enough powers of τ", size)
+	}
+	powers := transcript.Transcripts[selected].PowersOfTau
+	if len(powers.G1Powers) != transcript.Transcripts[selected].NumG1Powers || len(powers.G2Powers) != transcript.Transcripts[selected].NumG2Powers {
+		return nil, errors.New("number of powers of τ doesn't match the transcript")
+	}
+	if len(powers.G2Powers) < 2 {
+		return nil, errors.New("missing powers of τ in G₂")
+	}
+
+	tauG1 := make([]curve.G1Affine, size)
+	tauG2 := make([]curve.G2Affine, len(powers.G2Powers))
+	if err := decodeHexPoints(tauG1, powers.G1Powers); err != nil {
+		return nil, fmt.Errorf("[τⁱ]₁: %w", err)
+	}
+	if err := decodeHexPoints(tauG2, powers.G2Powers); err != nil {
+		return nil, fmt.Errorf("[τⁱ]₂: %w", err)
+	}
+
+	if err := checkPowersBLS12381(tauG1, tauG2); err != nil {
+		return nil, err
+	}
+
+	var srs kzg_bls12381.SRS
+	srs.Pk.G1 = tauG1
+	srs.Vk.G1 = tauG1[0]
+	srs.Vk.G2 = [2]curve.G2Affine{tauG2[0], tauG2[1]}
+	return &srs, nil
+}
+
+// decodeHexPoints decodes the first len(points) "0x" prefixed hex encoded points. SetBytes checks
+// that the points are in the subgroup.
+func decodeHexPoints[T any, PT interface {
+	*T
+	SetBytes([]byte) (int, error)
+}](points []T, encoded []string) error {
+	errs := make([]error, len(points))
+	utils.Parallelize(len(points), func(start, end int) {
+		for i := start; i < end; i++ {
+			b, err := hex.DecodeString(strings.TrimPrefix(encoded[i], "0x"))
+			if err != nil {
+				errs[i] = fmt.Errorf("point %d: %w", i, err)
+				return
+			}
+			if _, err := PT(&points[i]).SetBytes(b); err != nil {
+				errs[i] = fmt.Errorf("point %d: %w", i, err)
+				return
+			}
+		}
+	})
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package srs
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	kzg_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/kzg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEthereumKZG(t *testing.T) {
+	assert := require.New(t)
+
+	tau := big.NewInt(0xc0ffee)
+	expected, err := kzg_bls12381.NewSRS(16, tau)
+	assert.NoError(err)
+	_, _, _, g2 := curve.Generators()
+	tauG2 := make([]curve.G2Affine, 4)
+	tauG2[0] = g2
+	for i := 1; i < len(tauG2); i++ {
+		tauG2[i].ScalarMultiplication(&tauG2[i-1], tau)
+	}
+
+	// sub-ceremonies of 8 and 16 powers
+	var transcript ethereumTranscript
+	transcript.Transcripts = make([]ethereumSubTranscript, 2)
+	for i, size := range []int{8, 16} {
+		transcript.Transcripts[i].NumG1Powers = size
+		transcript.Transcripts[i].NumG2Powers = len(tauG2)
+		for j := 0; j < size; j++ {
+			b := expected.Pk.G1[j].Bytes()
+			transcript.Transcripts[i].PowersOfTau.G1Powers = append(transcript.Transcripts[i].PowersOfTau.G1Powers, "0x"+hex.EncodeToString(b[:]))
+		}
+		for j := range tauG2 {
+			b := tauG2[j].Bytes()
+			transcript.Transcripts[i].PowersOfTau.G2Powers = append(transcript.Transcripts[i].PowersOfTau.G2Powers, "0x"+hex.EncodeToString(b[:]))
+		}
+	}
+	encode := func() []byte {
+		b, err := json.Marshal(&transcript)
+		assert.NoError(err)
+		return b
+	}
+
+	srs, err := ReadEthereumKZG(bytes.NewReader(encode()), 10)
+	assert.NoError(err)
+	kzgSrs := srs.(*kzg_bls12381.SRS)
+	assert.Equal(expected.Pk.G1[:10], kzgSrs.Pk.G1)
+	assert.Equal(expected.Vk, kzgSrs.Vk)
+
+	_, err = ReadEthereumKZG(bytes.NewReader(encode()), 17)
+	assert.Error(err, "no sub-ceremony is large enough")
+
+	// powers not in order
+	powers := transcript.Transcripts[1].PowersOfTau.G1Powers
+	powers[2], powers[3] = powers[3], powers[2]
+	_, err = ReadEthereumKZG(bytes.NewReader(encode()), 10)
+	assert.Error(err)
+	_, err = ReadEthereumKZG(bytes.NewReader(encode()), 8)
+	assert.NoError(err, "the powers of the smaller sub-ceremony are valid")
+	powers[2], powers[3] = powers[3], powers[2]
+
+	// powers in G₂ not in order
+	powers = transcript.Transcripts[1].PowersOfTau.G2Powers
+	powers[2], powers[3] = powers[3], powers[2]
+	_, err = ReadEthereumKZG(bytes.NewReader(encode()), 10)
+	assert.Error(err)
+}
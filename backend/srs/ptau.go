@@ -0,0 +1,240 @@
+package srs
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend/groth16/mpcsetup"
+
+	fp_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	fp_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fp"
+)
+
+// sections of a .ptau file
+const (
+	ptauHeader     = 1
+	ptauTauG1      = 2
+	ptauTauG2      = 3
+	ptauAlphaTauG1 = 4
+	ptauBetaTauG1  = 5
+	ptauBetaG2     = 6
+)
+
+const (
+	ptauMaxVersion  = 1
+	ptauMaxSections = 1024
+)
+
+// ReadPtauKZG reads a snarkjs .ptau file and returns a KZG SRS with the first size powers of τ in
+// G₁, for plonk.Setup.
+func ReadPtauKZG(r io.Reader, size uint64) (kzg.SRS, error) {
+	p, err := newPtauReader(r)
+	if err != nil {
+		return nil, err
+	}
+	if size < 2 || size > p.nbTauG1() {
+		return nil, fmt.Errorf("invalid size %d: the file holds %d powers of τ", size, p.nbTauG1())
+	}
+	switch p.curve {
+	case ecc.BN254:
+		return readPtauKZGBN254(p, int(size))
+	case ecc.BLS12_381:
+		return readPtauKZGBLS12381(p, int(size))
+	default:
+		return nil, ErrUnsupportedCurve
+	}
+}
+
+// ReadPtauPhase1 reads a snarkjs .ptau file and returns the phase 1 of the Groth16 MPC for
+// circuits of up to 2ᵖᵒʷᵉʳ constraints, for mpcsetup.InitPhase2. As with mpcsetup.InitPhase1,
+// power must be the exact size of the circuit, which can be smaller than the power of the file.
+func ReadPtauPhase1(r io.Reader, power int) (mpcsetup.Phase1, error) {
+	p, err := newPtauReader(r)
+	if err != nil {
+		return nil, err
+	}
+	if power < 1 || power > p.power {
+		return nil, fmt.Errorf("invalid power %d: the file has power %d", power, p.power)
+	}
+	switch p.curve {
+	case ecc.BN254:
+		return readPtauPhase1BN254(p, 1<<power)
+	case ecc.BLS12_381:
+		return readPtauPhase1BLS12381(p, 1<<power)
+	default:
+		return nil, ErrUnsupportedCurve
+	}
+}
+
+// ptauReader reads a .ptau file section by section, without loading the powers it doesn't need.
+//
+// The file starts with the magic "ptau", the version and the number of sections, all little
+// endian. Each section starts with its type (uint32) and its size (uint64). The header section
+// holds the size in bytes n8 of the base field elements, the base field modulus, the power and the
+// power of the ceremony; the points sections hold, for 2ᵖᵒʷᵉʳ = N, [τⁱ]₁ for i < 2N-1, [τⁱ]₂,
+// [ατⁱ]₁ and [βτⁱ]₁ for i < N, and [β]₂. Points are stored as affine coordinates, little endian
+// and in Montgomery form.
+type ptauReader struct {
+	r          io.Reader
+	section    *io.LimitedReader // unread part of the current section
+	nbSections uint32            // number of sections left
+	n8         int
+	curve      ecc.ID
+	power      int
+}
+
+func newPtauReader(r io.Reader) (*ptauReader, error) {
+	var header [12]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	if string(header[:4]) != "ptau" {
+		return nil, fmt.Errorf("invalid file type %q, expected \"ptau\"", header[:4])
+	}
+	if version := binary.LittleEndian.Uint32(header[4:8]); version == 0 || version > ptauMaxVersion {
+		return nil, fmt.Errorf("unsupported ptau version %d", version)
+	}
+	p := &ptauReader{
+		r:          r,
+		nbSections: binary.LittleEndian.Uint32(header[8:12]),
+	}
+	if p.nbSections > ptauMaxSections {
+		return nil, fmt.Errorf("too many sections: %d", p.nbSections)
+	}
+
+	// snarkjs writes the header section first
+	sType, s, err := p.next()
+	if err != nil {
+		return nil, fmt.Errorf("reading header section: %w", err)
+	}
+	if sType != ptauHeader {
+		return nil, fmt.Errorf("expected header section, got section %d", sType)
+	}
+	var buf [4]byte
+	if _, err := io.ReadFull(s, buf[:]); err != nil {
+		return nil, fmt.Errorf("reading header section: %w", err)
+	}
+	p.n8 = int(binary.LittleEndian.Uint32(buf[:]))
+	if p.n8 != fp_bn254.Bytes && p.n8 != fp_bls12381.Bytes {
+		return nil, fmt.Errorf("%w: field elements of %d bytes", ErrUnsupportedCurve, p.n8)
+	}
+	q := make([]byte, p.n8)
+	if _, err := io.ReadFull(s, q); err != nil {
+		return nil, fmt.Errorf("reading header section: %w", err)
+	}
+	switch modulus := fromLittleEndian(q); {
+	case modulus.Cmp(fp_bn254.Modulus()) == 0:
+		p.curve = ecc.BN254
+	case modulus.Cmp(fp_bls12381.Modulus()) == 0:
+		p.curve = ecc.BLS12_381
+	default:
+		return nil, fmt.Errorf("%w: base field modulus %s", ErrUnsupportedCurve, modulus)
+	}
+	if _, err := io.ReadFull(s, buf[:]); err != nil {
+		return nil, fmt.Errorf("reading header section: %w", err)
+	}
+	p.power = int(binary.LittleEndian.Uint32(buf[:]))
+	if p.power < 1 || p.power > 30 {
+		return nil, fmt.Errorf("invalid power %d", p.power)
+	}
+
+	return p, nil
+}
+
+// nbTauG1 returns the number of powers of τ in G₁ held by the file.
+func (p *ptauReader) nbTauG1() uint64 {
+	return (2 << p.power) - 1
+}
+
+// next returns the type and the content of the next section, skipping what is left of the
+// current one. It returns io.EOF after the last section.
+func (p *ptauReader) next() (uint32, io.Reader, error) {
+	if p.section != nil && p.section.N > 0 {
+		if seeker, ok := p.r.(io.Seeker); ok {
+			if _, err := seeker.Seek(p.section.N, io.SeekCurrent); err != nil {
+				return 0, nil, err
+			}
+		} else if _, err := io.Copy(io.Discard, p.section); err != nil {
+			return 0, nil, err
+		} else if p.section.N > 0 {
+			return 0, nil, io.ErrUnexpectedEOF
+		}
+	}
+	if p.nbSections == 0 {
+		return 0, nil, io.EOF
+	}
+	p.nbSections--
+
+	var header [12]byte
+	if _, err := io.ReadFull(p.r, header[:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, nil, err
+	}
+	sType := binary.LittleEndian.Uint32(header[:4])
+	size := binary.LittleEndian.Uint64(header[4:])
+	if size > 1<<62 {
+		return 0, nil, fmt.Errorf("invalid size of section %d", sType)
+	}
+	p.section = &io.LimitedReader{R: p.r, N: int64(size)}
+	return sType, p.section, nil
+}
+
+// readSections reads the first nbPoints[t] points of each section of type t with read. The
+// sections not in nbPoints are skipped, and all the sections in nbPoints must be found.
+func (p *ptauReader) readSections(nbPoints map[uint32]int, read func(sType uint32, r io.Reader, n int) error) error {
+	found := make(map[uint32]bool, len(nbPoints))
+	for {
+		sType, s, err := p.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		n, ok := nbPoints[sType]
+		if !ok {
+			continue
+		}
+		if found[sType] {
+			return fmt.Errorf("duplicate section %d", sType)
+		}
+		if err := read(sType, s, n); err != nil {
+			return fmt.Errorf("reading section %d: %w", sType, err)
+		}
+		found[sType] = true
+	}
+	for sType := range nbPoints {
+		if !found[sType] {
+			return fmt.Errorf("missing section %d", sType)
+		}
+	}
+	return nil
+}
+
+// fromLittleEndian returns the integer of the little endian bytes b.
+func fromLittleEndian(b []byte) *big.Int {
+	be := make([]byte, len(b))
+	for i := range b {
+		be[len(b)-1-i] = b[i]
+	}
+	return new(big.Int).SetBytes(be)
+}
+
+// readFull reads n elements of size bytes.
+func readFull(r io.Reader, n, size int) ([]byte, error) {
+	buf := make([]byte, n*size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return buf, nil
+}
@@ -0,0 +1,26 @@
+package srs
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/stretchr/testify/require"
+)
+
+// cubicCircuit has 3 constraints: the phase 1 of circuits of up to 2² constraints is enough.
+type cubicCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (circuit *cubicCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(circuit.X, circuit.X, circuit.X)
+	api.AssertIsEqual(circuit.Y, api.Add(x3, circuit.X, 5))
+	return nil
+}
+
+func TestPtauInvalid(t *testing.T) {
+	_, err := ReadPtauKZG(bytes.NewReader([]byte("zkey")), 4)
+	require.Error(t, err)
+}
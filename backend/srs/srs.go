@@ -0,0 +1,25 @@
+// Package srs reads the structured reference strings produced by public trusted setup
+// ceremonies, so that production keys don't rely on an SRS whose secret is known (see
+// test.NewKZGSRS).
+//
+// Two formats are supported:
+//   - the .ptau files of snarkjs (https://github.com/iden3/snarkjs), on BN254 and BLS12-381, such
+//     as the Perpetual Powers of Tau (https://github.com/privacy-scaling-explorations/perpetualpowersoftau).
+//     ReadPtauKZG returns a KZG SRS for plonk.Setup, and ReadPtauPhase1 returns the phase 1 of the
+//     Groth16 MPC for mpcsetup.InitPhase2;
+//   - the transcript of the Ethereum KZG ceremony (https://github.com/ethereum/kzg-ceremony), on
+//     BLS12-381. ReadEthereumKZG returns a KZG SRS for plonk.Setup. The ceremony has no powers of
+//     τ multiplied by α and β, so it can't be used for the Groth16 MPC.
+//
+// The readers check that the points are on the curve and in the prime order subgroup, and that
+// they are consecutive powers of the same τ, with pairings on random linear combinations of the
+// powers. They don't verify the contributions of the ceremony, which must be checked with its own
+// tooling.
+package srs
+
+import "errors"
+
+var errNotPowers = errors.New("points are not consecutive powers of τ")
+
+// ErrUnsupportedCurve is returned when the reference string is on a curve that can't be read.
+var ErrUnsupportedCurve = errors.New("unsupported curve")
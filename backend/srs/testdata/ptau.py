@@ -0,0 +1,140 @@
+#!/usr/bin/env python3
+"""Writes the .ptau test files of this directory.
+
+The files follow the layout of the files of snarkjs (`snarkjs powersoftau new` and `contribute`):
+the magic "ptau", the version 1 and the sections header (1), [τⁱ]₁ (2), [τⁱ]₂ (3), [ατⁱ]₁ (4),
+[βτⁱ]₁ (5), [β]₂ (6) and the contributions (7, here none). Coordinates are little endian, in
+Montgomery form. The points are computed here, independently of gnark, from the toxic waste
+below, so that the tests check them against gnark-crypto.
+
+    python3 ptau.py
+"""
+
+TAU, ALPHA, BETA = 0x7461750001, 0x616C706861, 0x62657461
+POWER = 2
+
+CURVES = {
+    "bn254": {
+        "p": 21888242871839275222246405745257275088696311157297823662689037894645226208583,
+        "r": 21888242871839275222246405745257275088548364400416034343698204186575808495617,
+        "b1": 3,
+        "g1": (1, 2),
+        "g2": (
+            (10857046999023057135944570762232829481370756359578518086990519993285655852781,
+             11559732032986387107991004021392285783925812861821192530917403151452391805634),
+            (8495653923123431417604973247489272438418190587263600148770280649306958101930,
+             4082367875863433681332203403145435568316851327593401208105741076214120093531),
+        ),
+        "n8": 32,
+    },
+    "bls12381": {
+        "p": 0x1A0111EA397FE69A4B1BA7B6434BACD764774B84F38512BF6730D2A0F6B0F6241EABFFFEB153FFFFB9FEFFFFFFFFAAAB,
+        "r": 0x73EDA753299D7D483339D80809A1D80553BDA402FFFE5BFEFFFFFFFF00000001,
+        "b1": 4,
+        "g1": (
+            3685416753713387016781088315183077757961620795782546409894578378688607592378376318836054947676345821548104185464507,
+            1339506544944476473020471379941921221584933875938349620426543736416511423956333506472724655353366534992391756441569,
+        ),
+        "g2": (
+            (352701069587466618187139116011060144890029952792775240219908644239793785735715026873347600343865175952761926303160,
+             3059144344244213709971259814753781636986470325476647558659373206291635324768958432433509563104347017837885763365758),
+            (1985150602287291935568054521177171638300868978215655730859378665066344726373823718423869104263333984641494340347905,
+             927553665492332455747201965776037880757740193453592970025027978793976877002675564980949289727957565575433344219582),
+        ),
+        "n8": 48,
+    },
+}
+
+
+class Fp2:
+    """Elements a + bu of Fp[u]/(u²+1), which is the quadratic extension of both curves. The
+    elements of Fp are the ones with b = 0."""
+
+    def __init__(self, p, a, b=0):
+        self.p, self.a, self.b = p, a % p, b % p
+
+    def __add__(self, o):
+        return Fp2(self.p, self.a + o.a, self.b + o.b)
+
+    def __sub__(self, o):
+        return Fp2(self.p, self.a - o.a, self.b - o.b)
+
+    def __mul__(self, o):
+        if isinstance(o, int):
+            return Fp2(self.p, self.a * o, self.b * o)
+        return Fp2(self.p, self.a * o.a - self.b * o.b, self.a * o.b + self.b * o.a)
+
+    def inv(self):
+        n = pow(self.a * self.a + self.b * self.b, -1, self.p)
+        return Fp2(self.p, self.a * n, -self.b * n)
+
+    def __eq__(self, o):
+        return self.a == o.a and self.b == o.b
+
+
+def add(P, Q):
+    """Adds two affine points of a curve y² = x³ + b; None is the point at infinity."""
+    if P is None:
+        return Q
+    if Q is None:
+        return P
+    (x1, y1), (x2, y2) = P, Q
+    if x1 == x2:
+        if y1 != y2 or y1 == y1 * 0:
+            return None
+        l = x1 * x1 * 3 * (y1 * 2).inv()
+    else:
+        l = (y2 - y1) * (x2 - x1).inv()
+    x3 = l * l - x1 - x2
+    return (x3, l * (x1 - x3) - y1)
+
+
+def mul(P, k):
+    R = None
+    while k:
+        if k & 1:
+            R = add(R, P)
+        P = add(P, P)
+        k >>= 1
+    return R
+
+
+def write_ptau(path, c):
+    p, r, n8 = c["p"], c["r"], c["n8"]
+    R = 1 << (8 * n8)
+
+    def fp(x):
+        assert x.b == 0
+        return (x.a * R % p).to_bytes(n8, "little")
+
+    def g1(P):
+        return fp(P[0]) + fp(P[1])
+
+    def g2(P):
+        return b"".join(fp(Fp2(p, z)) for coordinate in P for z in (coordinate.a, coordinate.b))
+
+    G1 = (Fp2(p, c["g1"][0]), Fp2(p, c["g1"][1]))
+    G2 = tuple(Fp2(p, *coordinate) for coordinate in c["g2"])
+    assert G1[1] * G1[1] == G1[0] * G1[0] * G1[0] + Fp2(p, c["b1"])
+
+    N = 1 << POWER
+    taus = [pow(TAU, i, r) for i in range(2 * N - 1)]
+
+    sections = {
+        1: n8.to_bytes(4, "little") + p.to_bytes(n8, "little") + POWER.to_bytes(4, "little") + POWER.to_bytes(4, "little"),
+        2: b"".join(g1(mul(G1, t)) for t in taus),
+        3: b"".join(g2(mul(G2, t)) for t in taus[:N]),
+        4: b"".join(g1(mul(G1, ALPHA * t % r)) for t in taus[:N]),
+        5: b"".join(g1(mul(G1, BETA * t % r)) for t in taus[:N]),
+        6: g2(mul(G2, BETA)),
+        7: (0).to_bytes(4, "little"),
+    }
+    with open(path, "wb") as f:
+        f.write(b"ptau" + (1).to_bytes(4, "little") + len(sections).to_bytes(4, "little"))
+        for sType, data in sections.items():
+            f.write(sType.to_bytes(4, "little") + len(data).to_bytes(8, "little") + data)
+
+
+if __name__ == "__main__":
+    for name, c in CURVES.items():
+        write_ptau(f"{name}_{POWER:02d}.ptau", c)
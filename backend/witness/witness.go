@@ -40,7 +40,6 @@ import (
 	"bytes"
 	"encoding"
 	"encoding/binary"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -266,123 +265,62 @@ func (w *witness) Vector() any {
 
 // ToJSON returns the JSON encoding of the witness following the provided Schema. This is a
 // convenience method and should be avoided in most cases.
+//
+// Gadgets implementing schema.JSONHook (e.g. emulated elements) are encoded in their own
+// JSON representation instead of one value per leaf.
 func (w *witness) ToJSON(s *schema.Schema) ([]byte, error) {
 	if s.NbPublic != int(w.nbPublic) || (w.nbSecret != 0 && w.nbSecret != uint32(s.NbSecret)) {
 		return nil, errors.New("schema is inconsistent with Witness")
 	}
-	typ := reflect.PtrTo(leafType(w.vector))
-	instance := s.Instantiate(typ)
 
 	chValues := w.iterate()
-	if _, err := schema.Walk(instance, typ, func(field schema.LeafInfo, tValue reflect.Value) error {
-		if field.Visibility == schema.Public {
-			v := <-chValues
-			tValue.Set(reflect.ValueOf(v))
-		}
-		return nil
-	}); err != nil {
-		return nil, err
+	public := make([]any, 0, w.nbPublic)
+	for i := 0; i < int(w.nbPublic); i++ {
+		public = append(public, <-chValues)
 	}
-
+	var secret []any
 	if w.nbSecret != 0 {
-		// secret part.
-		if _, err := schema.Walk(instance, typ, func(field schema.LeafInfo, tValue reflect.Value) error {
-			if field.Visibility == schema.Secret {
-				v := <-chValues
-				tValue.Set(reflect.ValueOf(v))
-			}
-			return nil
-		}); err != nil {
-			return nil, err
+		secret = make([]any, 0, w.nbSecret)
+		for v := range chValues {
+			secret = append(secret, v)
 		}
 	}
 
-	if debug.Debug {
-		return json.MarshalIndent(instance, "  ", "    ")
-	} else {
-		return json.Marshal(instance)
-	}
+	return s.EncodeJSON(reflect.PtrTo(leafType(w.vector)), public, secret, debug.Debug)
 }
 
 // FromJSON parses a JSON data input and attempt to reconstruct a witness following the provided Schema.
 // This is a convenience method and should be avoided in most cases.
+//
+// Gadgets implementing schema.JSONHook (e.g. emulated elements) are decoded from their own
+// JSON representation and decomposed into leaves.
 func (w *witness) FromJSON(s *schema.Schema, data []byte) error {
-	typ := leafType(w.vector)
-	ptrTyp := reflect.PtrTo(typ)
-
-	// we instantiate an object matching the schema, with leaf type == field element
-	// note that we pass a pointer here to have nil for zero values
-	instance := s.Instantiate(ptrTyp)
-
-	dec := json.NewDecoder(bytes.NewReader(data))
-	dec.DisallowUnknownFields()
-
-	// field.Element (gnark-crypto) implements json.Unmarshaler
-	if err := dec.Decode(instance); err != nil {
-		return err
-	}
-	// walk through the public AND secret values
-	missingAssignment := func(name string) error {
-		return fmt.Errorf("missing assignment for %s", name)
-	}
-
-	// collect all public values; if any are missing, no point going further.
-	publicValues := make([]any, 0, s.NbPublic)
-	if _, err := schema.Walk(instance, ptrTyp, func(leaf schema.LeafInfo, tValue reflect.Value) error {
-		if leaf.Visibility == schema.Public {
-			if tValue.IsNil() {
-				return missingAssignment(leaf.FullName())
-			}
-			publicValues = append(publicValues, reflect.Indirect(tValue).Interface())
-		}
-		return nil
-	}); err != nil {
-		// missing public values
+	publicValues, secretValues, err := s.DecodeJSON(leafType(w.vector), data)
+	if err != nil {
 		return err
 	}
 
-	// collect all secret values; if any are missing, we just deal with the public part.
-	secretValues := make([]any, 0, s.NbSecret)
-	publicOnly := false
-	if _, err := schema.Walk(instance, ptrTyp, func(leaf schema.LeafInfo, tValue reflect.Value) error {
-		if leaf.Visibility == schema.Secret {
-			if tValue.IsNil() {
-				return missingAssignment(leaf.FullName())
-			}
-			secretValues = append(secretValues, reflect.Indirect(tValue).Interface())
-		}
-		return nil
-	}); err != nil {
-		// missing secret values, we just do the public part.
-		publicOnly = true
+	// if any secret value is missing, we just deal with the public part.
+	nbSecret := s.NbSecret
+	if secretValues == nil {
+		nbSecret = 0
 	}
 
 	// reconstruct the witness
 	// we use a buffered channel to ensure this go routine terminates, even if setting a witness
 	// value failed. All this is not really performant for large witnesses, but again, JSON
 	// shouldn't be used in perf-critical scenario.
-	var chValues chan any
-	if publicOnly {
-		chValues = make(chan any, len(publicValues))
-		s.NbSecret = 0
-	} else {
-		chValues = make(chan any, len(publicValues)+len(secretValues))
-	}
+	chValues := make(chan any, len(publicValues)+len(secretValues))
 	go func() {
 		defer close(chValues)
 
 		for _, v := range publicValues {
 			chValues <- v
 		}
-
-		if publicOnly {
-			return
-		}
-
 		for _, v := range secretValues {
 			chValues <- v
 		}
 	}()
 
-	return w.Fill(s.NbPublic, s.NbSecret, chValues)
+	return w.Fill(s.NbPublic, nbSecret, chValues)
 }
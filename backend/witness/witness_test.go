@@ -4,14 +4,19 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"reflect"
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
 	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/io"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
 	"github.com/stretchr/testify/require"
 )
 
@@ -181,3 +186,84 @@ func TestVariableInitHook(t *testing.T) {
 	assert.True(ok)
 	assert.Len(fw, 10, "invalid length")
 }
+
+type gadgetCircuit struct {
+	P   sw_emulated.AffinePoint[emulated.Secp256k1Fp] `gnark:",public"`
+	S   emulated.Element[emulated.Secp256k1Fr]
+	Msg []uints.U8
+	N   uints.U32
+	K   [2]uints.U64
+	X   frontend.Variable `gnark:",public"`
+}
+
+func (c *gadgetCircuit) Define(frontend.API) error {
+	return nil
+}
+
+func TestJSONHooks(t *testing.T) {
+	assert := require.New(t)
+
+	var g secp256k1.G1Affine
+	_, g1 := secp256k1.Generators()
+	g.ScalarMultiplication(&g1, big.NewInt(42))
+	assignment := gadgetCircuit{
+		P: sw_emulated.AffinePoint[emulated.Secp256k1Fp]{
+			X: emulated.ValueOf[emulated.Secp256k1Fp](g.X),
+			Y: emulated.ValueOf[emulated.Secp256k1Fp](g.Y),
+		},
+		S:   emulated.ValueOf[emulated.Secp256k1Fr](123456789),
+		Msg: uints.NewU8Array([]byte("gnark")),
+		N:   uints.NewU32(0xdeadbeef),
+		K:   [2]uints.U64{uints.NewU64(1), uints.NewU64(1 << 40)},
+		X:   7,
+	}
+	w, err := frontend.NewWitness(&assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	s, err := frontend.NewSchema(&gadgetCircuit{Msg: make([]uints.U8, 5)})
+	assert.NoError(err)
+
+	data, err := w.ToJSON(s)
+	assert.NoError(err)
+	expected := fmt.Sprintf(`{"P":{"X":"0x%s","Y":"0x%s"},"S":"0x75bcd15","Msg":"0x676e61726b","N":3735928559,"K":[1,1099511627776],"X":7}`,
+		g.X.Text(16), g.Y.Text(16))
+	assert.JSONEq(expected, string(data))
+
+	rw, err := witness.New(ecc.BN254.ScalarField())
+	assert.NoError(err)
+	assert.NoError(rw.FromJSON(s, data))
+	assert.True(reflect.DeepEqual(rw, w), "witness json round trip serialization")
+
+	// alternative forms: decimal strings, numbers and arrays of bytes
+	alternative := fmt.Sprintf(`{"P":{"X":"%s","Y":"0x%s"},"S":123456789,"Msg":[103,110,97,114,"0x6b"],"N":"0xdeadbeef","K":["1",1099511627776],"X":7}`,
+		g.X.String(), g.Y.Text(16))
+	rw, err = witness.New(ecc.BN254.ScalarField())
+	assert.NoError(err)
+	assert.NoError(rw.FromJSON(s, []byte(alternative)))
+	assert.True(reflect.DeepEqual(rw, w), "witness json alternative forms")
+
+	// public part only
+	pw, err := w.Public()
+	assert.NoError(err)
+	data, err = pw.ToJSON(s)
+	assert.NoError(err)
+	assert.JSONEq(fmt.Sprintf(`{"P":{"X":"0x%s","Y":"0x%s"},"X":7}`, g.X.Text(16), g.Y.Text(16)), string(data))
+	rw, err = witness.New(ecc.BN254.ScalarField())
+	assert.NoError(err)
+	assert.NoError(rw.FromJSON(s, data))
+	assert.True(reflect.DeepEqual(rw, pw), "public witness json round trip serialization")
+
+	// invalid values
+	for _, invalid := range []string{
+		`{"P":{"X":"0x1","Y":"0x2"},"S":1,"Msg":"0x0102","N":1,"K":[1,2],"X":7}`,         // wrong number of bytes
+		`{"P":{"X":"0x1","Y":"0x2"},"S":1,"Msg":"0x0102030405","N":-1,"K":[1,2],"X":7}`,  // negative
+		`{"P":{"X":"0x1","Y":"0x2"},"S":"x","Msg":"0x0102030405","N":1,"K":[1,2],"X":7}`, // not an integer
+		`{"P":{"X":"0x1","Y":"0x2"},"S":1,"Msg":"0x0102030405","N":1,"K":[1],"X":7}`,     // wrong array length
+		`{"P":{"X":"0x1"},"X":7}`, // missing public value
+		`{"P":{"X":"0x1","Y":"0x2"},"S":-1,"Msg":"0x0102030405","N":1,"K":[1,2],"X":7}`,                                                  // negative emulated element
+		fmt.Sprintf(`{"P":{"X":"0x1","Y":"0x2"},"S":"%s","Msg":"0x0102030405","N":1,"K":[1,2],"X":7}`, emulated.Secp256k1Fr{}.Modulus()), // emulated element ≥ p
+	} {
+		rw, err = witness.New(ecc.BN254.ScalarField())
+		assert.NoError(err)
+		assert.Error(rw.FromJSON(s, []byte(invalid)), invalid)
+	}
+}
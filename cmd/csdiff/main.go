@@ -0,0 +1,125 @@
+// csdiff compares two compiled constraint systems or two gnark profiles and prints
+// the constraint-level differences (see package constraint/diff).
+//
+// Usage:
+//
+//	csdiff -curve bn254 before.r1cs after.r1cs
+//	csdiff -profile before.pprof after.pprof
+//
+// Constraint systems must be serialized with WriteTo; profiles are the pprof files written by
+// package profile.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/constraint/diff"
+	"github.com/google/pprof/profile"
+)
+
+func main() {
+	var (
+		curveName = flag.String("curve", "bn254", "curve of the constraint systems")
+		profiles  = flag.Bool("profile", false, "compare two pprof profiles instead of two constraint systems")
+		exitCode  = flag.Bool("exit-code", false, "exit with status 1 if the inputs differ")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] before after\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var (
+		report *diff.Report
+		err    error
+	)
+	if *profiles {
+		report, err = diffProfiles(flag.Arg(0), flag.Arg(1))
+	} else {
+		report, err = diffSystems(*curveName, flag.Arg(0), flag.Arg(1))
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	fmt.Print(report)
+	if *exitCode && !report.IsEmpty() {
+		os.Exit(1)
+	}
+}
+
+func diffSystems(curveName, before, after string) (*diff.Report, error) {
+	curve, err := parseCurve(curveName)
+	if err != nil {
+		return nil, err
+	}
+	a, err := readSystem(curve, before)
+	if err != nil {
+		return nil, err
+	}
+	b, err := readSystem(curve, after)
+	if err != nil {
+		return nil, err
+	}
+	return diff.Systems(a, b)
+}
+
+func diffProfiles(before, after string) (*diff.Report, error) {
+	a, err := readProfile(before)
+	if err != nil {
+		return nil, err
+	}
+	b, err := readProfile(after)
+	if err != nil {
+		return nil, err
+	}
+	return diff.Profiles(a, b), nil
+}
+
+func parseCurve(name string) (ecc.ID, error) {
+	for _, id := range ecc.Implemented() {
+		if strings.EqualFold(id.String(), name) {
+			return id, nil
+		}
+	}
+	return ecc.UNKNOWN, fmt.Errorf("unknown curve %q", name)
+}
+
+func readSystem(curve ecc.ID, path string) (constraint.ConstraintSystem, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// R1CS and SparseR1CS share the same concrete type; the system type is serialized.
+	cs := groth16.NewCS(curve)
+	if _, err := cs.ReadFrom(f); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return cs, nil
+}
+
+func readProfile(path string) (*profile.Profile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	p, err := profile.Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return p, nil
+}
@@ -0,0 +1,455 @@
+// mpcsetup runs the multi-party computation ceremony producing the Groth16 keys of a circuit (see
+// package backend/groth16/mpcsetup), with one file per state of the ceremony and a JSON
+// transcript recording its contributions.
+//
+// Usage:
+//
+//	mpcsetup init-phase1 -curve bn254 -power 16 -out phase1_0
+//	mpcsetup contribute -name alice -in phase1_0 -out phase1_1
+//	mpcsetup beacon -seed 3ab6c1... -iterations 1048576 -in phase1_1 -out phase1_2
+//	mpcsetup verify -power 16 phase1_0 phase1_1 phase1_2
+//	mpcsetup init-phase2 -r1cs circuit.r1cs -phase1 phase1_2 -out phase2_0
+//	mpcsetup contribute -name bob -in phase2_0 -out phase2_1
+//	mpcsetup verify -r1cs circuit.r1cs -phase1 phase1_2 phase2_0 phase2_1
+//	mpcsetup extract -r1cs circuit.r1cs -phase1 phase1_2 -phase2 phase2_1 -pk circuit.pk -vk circuit.vk
+//
+// Every command reads and updates the transcript (-transcript, transcript.json by default), which
+// records the curve and the phase of the ceremony. Contributions must be made on the last state
+// of the transcript. The domain of the circuit, serialized with WriteTo, must have 2ᵖᵒʷᵉʳ elements.
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/groth16/mpcsetup"
+	"github.com/consensys/gnark/constraint"
+)
+
+var commands = map[string]func(args []string) error{
+	"init-phase1": initPhase1,
+	"contribute":  contribute,
+	"beacon":      beacon,
+	"verify":      verify,
+	"init-phase2": initPhase2,
+	"extract":     extract,
+}
+
+func main() {
+	if len(os.Args) < 2 || commands[os.Args[1]] == nil {
+		fmt.Fprintf(os.Stderr, "usage: %s init-phase1|contribute|beacon|verify|init-phase2|extract [flags]\n", os.Args[0])
+		os.Exit(2)
+	}
+	if err := commands[os.Args[1]](os.Args[2:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func initPhase1(args []string) error {
+	fs := flag.NewFlagSet("init-phase1", flag.ExitOnError)
+	var (
+		transcriptPath = fs.String("transcript", "transcript.json", "transcript of the ceremony, created")
+		curveName      = fs.String("curve", "bn254", "curve of the ceremony")
+		power          = fs.Int("power", 0, "log₂ of the size of the domain of the circuits")
+		out            = fs.String("out", "", "initial state of phase 1, written")
+	)
+	fs.Parse(args)
+	if *power <= 0 || *out == "" {
+		return errors.New("init-phase1: -power and -out are required")
+	}
+	curve, err := parseCurve(*curveName)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(*transcriptPath); err == nil {
+		return fmt.Errorf("%s already exists", *transcriptPath)
+	}
+
+	srs1 := mpcsetup.InitPhase1(curve, *power)
+	if err := writeFile(*out, srs1); err != nil {
+		return err
+	}
+	transcript := mpcsetup.NewTranscript(curve)
+	if err := transcript.Append(mpcsetup.Contribution{
+		Phase:       1,
+		Participant: "coordinator",
+		Comment:     fmt.Sprintf("power %d", *power),
+		Time:        time.Now(),
+		Hash:        srs1.ContributionHash(),
+	}); err != nil {
+		return err
+	}
+	return writeTranscript(*transcriptPath, transcript)
+}
+
+func contribute(args []string) error {
+	fs := flag.NewFlagSet("contribute", flag.ExitOnError)
+	var (
+		transcriptPath = fs.String("transcript", "transcript.json", "transcript of the ceremony, updated")
+		name           = fs.String("name", "", "name of the participant")
+		comment        = fs.String("comment", "", "comment recorded in the transcript")
+		in             = fs.String("in", "", "last state of the ceremony")
+		out            = fs.String("out", "", "state after the contribution, written")
+	)
+	fs.Parse(args)
+	if *name == "" || *in == "" || *out == "" {
+		return errors.New("contribute: -name, -in and -out are required")
+	}
+	return addContribution(*transcriptPath, *in, *out, mpcsetup.Contribution{Participant: *name, Comment: *comment})
+}
+
+func beacon(args []string) error {
+	fs := flag.NewFlagSet("beacon", flag.ExitOnError)
+	var (
+		transcriptPath = fs.String("transcript", "transcript.json", "transcript of the ceremony, updated")
+		seed           = fs.String("seed", "", "hex-encoded public seed of the beacon, e.g. a block hash")
+		nbIterations   = fs.Int("iterations", 1<<20, "number of times the seed is hashed")
+		comment        = fs.String("comment", "", "comment recorded in the transcript, e.g. the origin of the seed")
+		in             = fs.String("in", "", "last state of the ceremony")
+		out            = fs.String("out", "", "state after the contribution, written")
+	)
+	fs.Parse(args)
+	if *seed == "" || *in == "" || *out == "" {
+		return errors.New("beacon: -seed, -in and -out are required")
+	}
+	if *nbIterations <= 0 {
+		return errors.New("beacon: the number of iterations must be positive")
+	}
+	b, err := hex.DecodeString(strings.TrimPrefix(*seed, "0x"))
+	if err != nil {
+		return fmt.Errorf("beacon: invalid seed: %w", err)
+	}
+	return addContribution(*transcriptPath, *in, *out, mpcsetup.Contribution{
+		Participant: "beacon",
+		Comment:     *comment,
+		Beacon:      &mpcsetup.Beacon{Seed: b, NbIterations: *nbIterations},
+	})
+}
+
+// addContribution applies c to the state in, the last one of the transcript, and records it.
+func addContribution(transcriptPath, in, out string, c mpcsetup.Contribution) error {
+	transcript, curve, err := readTranscript(transcriptPath)
+	if err != nil {
+		return err
+	}
+	last := transcript.Contributions[len(transcript.Contributions)-1]
+
+	if err := checkHash(in, last.Hash); err != nil {
+		return err
+	}
+	var srs interface {
+		io.WriterTo
+		Contribute()
+		ContributeWithBeacon(seed []byte, nbIterations int)
+		ContributionHash() []byte
+	}
+	if last.Phase == 1 {
+		srs, err = readPhase1(curve, in)
+	} else {
+		srs, err = readPhase2(curve, in)
+	}
+	if err != nil {
+		return err
+	}
+
+	if c.Beacon != nil {
+		srs.ContributeWithBeacon(c.Beacon.Seed, c.Beacon.NbIterations)
+	} else {
+		srs.Contribute()
+	}
+	if err := writeFile(out, srs); err != nil {
+		return err
+	}
+	c.Phase = last.Phase
+	c.Time = time.Now()
+	c.Hash = srs.ContributionHash()
+	if err := transcript.Append(c); err != nil {
+		return err
+	}
+	fmt.Printf("contribution hash: %x\n", c.Hash)
+	return writeTranscript(transcriptPath, transcript)
+}
+
+func verify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	var (
+		transcriptPath = fs.String("transcript", "transcript.json", "transcript of the ceremony")
+		power          = fs.Int("power", 0, "log₂ of the size of the domain, to verify phase 1")
+		r1csPath       = fs.String("r1cs", "", "circuit, to verify phase 2")
+		phase1Path     = fs.String("phase1", "", "last state of phase 1, to verify phase 2")
+	)
+	fs.Parse(args)
+	if fs.NArg() == 0 || (*power <= 0) == (*r1csPath == "" || *phase1Path == "") {
+		return errors.New("verify: either -power, or -r1cs and -phase1 are required, followed by the states of the phase")
+	}
+	transcript, curve, err := readTranscript(*transcriptPath)
+	if err != nil {
+		return err
+	}
+
+	if *power > 0 {
+		contribs := make([]mpcsetup.Phase1, fs.NArg())
+		for i := range contribs {
+			if contribs[i], err = readPhase1(curve, fs.Arg(i)); err != nil {
+				return err
+			}
+		}
+		init := mpcsetup.InitPhase1(curve, *power)
+		if same, err := sameState(init, contribs[0]); err != nil || !same {
+			return fmt.Errorf("%s is not the initial state of phase 1", fs.Arg(0))
+		}
+		if err := transcript.VerifyPhase1(contribs...); err != nil {
+			return err
+		}
+	} else {
+		contribs := make([]mpcsetup.Phase2, fs.NArg())
+		for i := range contribs {
+			if contribs[i], err = readPhase2(curve, fs.Arg(i)); err != nil {
+				return err
+			}
+		}
+		init, _, err := initPhase2From(transcript, curve, *r1csPath, *phase1Path)
+		if err != nil {
+			return err
+		}
+		if same, err := sameState(init, contribs[0]); err != nil || !same {
+			return fmt.Errorf("%s is not the initial state of phase 2", fs.Arg(0))
+		}
+		if err := transcript.VerifyPhase2(contribs...); err != nil {
+			return err
+		}
+	}
+	fmt.Println("ok")
+	return nil
+}
+
+func initPhase2(args []string) error {
+	fs := flag.NewFlagSet("init-phase2", flag.ExitOnError)
+	var (
+		transcriptPath = fs.String("transcript", "transcript.json", "transcript of the ceremony, updated")
+		r1csPath       = fs.String("r1cs", "", "circuit")
+		phase1Path     = fs.String("phase1", "", "last state of phase 1")
+		out            = fs.String("out", "", "initial state of phase 2, written")
+	)
+	fs.Parse(args)
+	if *r1csPath == "" || *phase1Path == "" || *out == "" {
+		return errors.New("init-phase2: -r1cs, -phase1 and -out are required")
+	}
+	transcript, curve, err := readTranscript(*transcriptPath)
+	if err != nil {
+		return err
+	}
+	if len(transcript.Phase(2)) != 0 {
+		return errors.New("phase 2 is already initialized")
+	}
+	srs2, _, err := initPhase2From(transcript, curve, *r1csPath, *phase1Path)
+	if err != nil {
+		return err
+	}
+	if err := writeFile(*out, srs2); err != nil {
+		return err
+	}
+	if err := transcript.Append(mpcsetup.Contribution{
+		Phase:       2,
+		Participant: "coordinator",
+		Time:        time.Now(),
+		Hash:        srs2.ContributionHash(),
+	}); err != nil {
+		return err
+	}
+	return writeTranscript(*transcriptPath, transcript)
+}
+
+func extract(args []string) error {
+	fs := flag.NewFlagSet("extract", flag.ExitOnError)
+	var (
+		transcriptPath = fs.String("transcript", "transcript.json", "transcript of the ceremony")
+		r1csPath       = fs.String("r1cs", "", "circuit")
+		phase1Path     = fs.String("phase1", "", "last state of phase 1")
+		phase2Path     = fs.String("phase2", "", "last state of phase 2")
+		pkPath         = fs.String("pk", "", "proving key, written")
+		vkPath         = fs.String("vk", "", "verifying key, written")
+	)
+	fs.Parse(args)
+	if *r1csPath == "" || *phase1Path == "" || *phase2Path == "" || *pkPath == "" || *vkPath == "" {
+		return errors.New("extract: -r1cs, -phase1, -phase2, -pk and -vk are required")
+	}
+	transcript, curve, err := readTranscript(*transcriptPath)
+	if err != nil {
+		return err
+	}
+	phase2 := transcript.Phase(2)
+	if len(phase2) < 2 {
+		return errors.New("phase 2 has no contribution")
+	}
+	if err := checkHash(*phase2Path, phase2[len(phase2)-1].Hash); err != nil {
+		return err
+	}
+	srs2, err := readPhase2(curve, *phase2Path)
+	if err != nil {
+		return err
+	}
+
+	// the evaluations are recomputed rather than stored: phase 2 is initialized deterministically
+	_, evals, err := initPhase2From(transcript, curve, *r1csPath, *phase1Path)
+	if err != nil {
+		return err
+	}
+	srs1, err := readPhase1(curve, *phase1Path)
+	if err != nil {
+		return err
+	}
+	r1cs, err := readSystem(curve, *r1csPath)
+	if err != nil {
+		return err
+	}
+	pk, vk, err := mpcsetup.ExtractKeys(srs1, srs2, evals, r1cs.GetNbConstraints())
+	if err != nil {
+		return err
+	}
+	if err := writeFile(*pkPath, pk); err != nil {
+		return err
+	}
+	return writeFile(*vkPath, vk)
+}
+
+// initPhase2From checks that phase1Path is the last state of phase 1 recorded in the transcript,
+// and initializes phase 2 from it.
+func initPhase2From(transcript *mpcsetup.Transcript, curve ecc.ID, r1csPath, phase1Path string) (mpcsetup.Phase2, mpcsetup.Phase2Evaluations, error) {
+	phase1 := transcript.Phase(1)
+	if len(phase1) < 2 {
+		return nil, nil, errors.New("phase 1 has no contribution")
+	}
+	if err := checkHash(phase1Path, phase1[len(phase1)-1].Hash); err != nil {
+		return nil, nil, err
+	}
+	srs1, err := readPhase1(curve, phase1Path)
+	if err != nil {
+		return nil, nil, err
+	}
+	r1cs, err := readSystem(curve, r1csPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	return mpcsetup.InitPhase2(r1cs, srs1)
+}
+
+func parseCurve(name string) (ecc.ID, error) {
+	for _, id := range ecc.Implemented() {
+		if strings.EqualFold(id.String(), name) {
+			return id, nil
+		}
+	}
+	return ecc.UNKNOWN, fmt.Errorf("unknown curve %q", name)
+}
+
+func readTranscript(path string) (*mpcsetup.Transcript, ecc.ID, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, ecc.UNKNOWN, err
+	}
+	var transcript mpcsetup.Transcript
+	if err := json.Unmarshal(b, &transcript); err != nil {
+		return nil, ecc.UNKNOWN, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if err := transcript.Verify(); err != nil {
+		return nil, ecc.UNKNOWN, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if len(transcript.Contributions) == 0 {
+		return nil, ecc.UNKNOWN, fmt.Errorf("reading %s: empty transcript", path)
+	}
+	curve, err := parseCurve(transcript.Curve)
+	if err != nil {
+		return nil, ecc.UNKNOWN, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return &transcript, curve, nil
+}
+
+func writeTranscript(path string, transcript *mpcsetup.Transcript) error {
+	b, err := json.MarshalIndent(transcript, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0644)
+}
+
+// checkHash returns an error if the state in the file isn't the one of the given hash, before it
+// is decoded as a state of the wrong phase. States are written with their hash at the end.
+func checkHash(path string, hash []byte) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	tail := make([]byte, len(hash))
+	if _, err := f.Seek(-int64(len(hash)), io.SeekEnd); err == nil {
+		_, err = io.ReadFull(f, tail)
+	}
+	if err != nil || string(tail) != string(hash) {
+		return fmt.Errorf("%s is not the last state of the phase", path)
+	}
+	return nil
+}
+
+// sameState returns true if both states have the same encoding. Their hashes alone can't be
+// compared: the hash of a state is read from its file with it.
+func sameState(a, b io.WriterTo) (bool, error) {
+	var bufA, bufB bytes.Buffer
+	if _, err := a.WriteTo(&bufA); err != nil {
+		return false, err
+	}
+	if _, err := b.WriteTo(&bufB); err != nil {
+		return false, err
+	}
+	return bytes.Equal(bufA.Bytes(), bufB.Bytes()), nil
+}
+
+func readPhase1(curve ecc.ID, path string) (mpcsetup.Phase1, error) {
+	srs1 := mpcsetup.NewPhase1(curve)
+	return srs1, readFile(path, srs1)
+}
+
+func readPhase2(curve ecc.ID, path string) (mpcsetup.Phase2, error) {
+	srs2 := mpcsetup.NewPhase2(curve)
+	return srs2, readFile(path, srs2)
+}
+
+func readSystem(curve ecc.ID, path string) (constraint.ConstraintSystem, error) {
+	cs := groth16.NewCS(curve)
+	return cs, readFile(path, cs)
+}
+
+func readFile(path string, v io.ReaderFrom) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := v.ReadFrom(f); err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	return nil
+}
+
+func writeFile(path string, v io.WriterTo) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := v.WriteTo(f); err != nil {
+		f.Close()
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return f.Close()
+}
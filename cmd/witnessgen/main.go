@@ -0,0 +1,96 @@
+// witnessgen computes the full assignment of a circuit from its inputs, using a program
+// exported with constraint/witnessgen, without the Go code of the circuit.
+//
+// Usage:
+//
+//	witnessgen -program circuit.wgen -witness inputs.bin -out assignment.cbor
+//
+// The witness is the binary encoding of a full witness (see witness.Witness); the assignment
+// is written in the export_utils format. The hints of gnark/std are registered; circuits using
+// other hints need a runner registering them (see the witnessgen package).
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/consensys/gnark/constraint/witnessgen"
+	"github.com/consensys/gnark/std"
+)
+
+func main() {
+	var (
+		programPath = flag.String("program", "", "program exported with witnessgen.Export")
+		witnessPath = flag.String("witness", "", "binary encoding of the full witness")
+		outPath     = flag.String("out", "assignment.cbor", "output file")
+		listHints   = flag.Bool("hints", false, "print the hints the program depends on and exit")
+	)
+	flag.Parse()
+	if *programPath == "" || (*witnessPath == "" && !*listHints) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(*programPath, *witnessPath, *outPath, *listHints); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(programPath, witnessPath, outPath string, listHints bool) error {
+	std.RegisterHints()
+
+	f, err := os.Open(programPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	p, err := witnessgen.Read(bufio.NewReader(f))
+	if err != nil {
+		return err
+	}
+
+	if listHints {
+		for _, name := range p.Hints() {
+			fmt.Println(name)
+		}
+		return nil
+	}
+	missing, err := p.MissingHints()
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing hints: %s", strings.Join(missing, ", "))
+	}
+
+	data, err := os.ReadFile(witnessPath)
+	if err != nil {
+		return err
+	}
+	w, err := p.NewWitness()
+	if err != nil {
+		return err
+	}
+	if err := w.UnmarshalBinary(data); err != nil {
+		return fmt.Errorf("reading witness: %w", err)
+	}
+
+	values, err := p.Solve(w)
+	if err != nil {
+		return err
+	}
+
+	out, err := os.Create(outPath)
+	if err != nil {
+		return err
+	}
+	if err := p.WriteAssignment(out, values); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -83,6 +83,8 @@ func TestSerialization(t *testing.T) {
 						"System.genericHint",
 						"System.SymbolTable",
 						"System.lbOutputs",
+						"System.scopeStack",
+						"System.scopeIDs",
 						"System.bitLen")); diff != "" {
 					t.Fatalf("round trip mismatch (-want +got):\n%s", diff)
 				}
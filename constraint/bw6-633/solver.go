@@ -17,13 +17,17 @@
 package cs
 
 import (
+	"bufio"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/field/pool"
 	"github.com/consensys/gnark/constraint"
 	csolver "github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/internal/backend/ioutils"
 	"github.com/rs/zerolog"
+	"io"
 	"math"
 	"math/big"
 	"runtime"
@@ -53,6 +57,18 @@ type solver struct {
 	a, b, c fr.Vector // R1CS solver will compute the a,b,c matrices
 
 	q *big.Int
+
+	// assigned is set for the internal wires provided by the caller (see csolver.WithAssignedWires);
+	// errAssigned records the first mismatch between an assigned and a computed value.
+	assigned    []bool
+	muAssigned  sync.Mutex
+	errAssigned error
+
+	// snapshots of the solver state (see csolver.WithSnapshots); the solver starts at
+	// startLevel when resuming from a snapshot.
+	snapshotInterval int
+	snapshotSave     func(csolver.Snapshot) error
+	startLevel       int
 }
 
 func newSolver(cs *system, witness fr.Vector, opts ...csolver.Option) (*solver, error) {
@@ -91,12 +107,14 @@ func newSolver(cs *system, witness fr.Vector, opts ...csolver.Option) (*solver,
 	}
 
 	s := solver{
-		system:          cs,
-		values:          make([]fr.Element, nbWires),
-		solved:          make([]bool, nbWires),
-		mHintsFunctions: hintFunctions,
-		logger:          opt.Logger,
-		q:               cs.Field(),
+		system:           cs,
+		values:           make([]fr.Element, nbWires),
+		solved:           make([]bool, nbWires),
+		mHintsFunctions:  hintFunctions,
+		logger:           opt.Logger,
+		q:                cs.Field(),
+		snapshotInterval: opt.SnapshotInterval,
+		snapshotSave:     opt.SnapshotSave,
 	}
 
 	// set the witness indexes as solved
@@ -113,6 +131,12 @@ func newSolver(cs *system, witness fr.Vector, opts ...csolver.Option) (*solver,
 	// to ensure we instantiated all wires
 	s.nbSolved += uint64(len(witness) + witnessOffset)
 
+	if len(opt.AssignedWires) != 0 || len(opt.AssignedLabels) != 0 {
+		if err := s.assign(opt.AssignedWires, opt.AssignedLabels); err != nil {
+			return nil, err
+		}
+	}
+
 	if s.Type == constraint.SystemR1CS {
 		n := ecc.NextPowerOfTwo(uint64(cs.GetNbConstraints()))
 		s.a = make(fr.Vector, cs.GetNbConstraints(), n)
@@ -120,11 +144,84 @@ func newSolver(cs *system, witness fr.Vector, opts ...csolver.Option) (*solver,
 		s.c = make(fr.Vector, cs.GetNbConstraints(), n)
 	}
 
+	if opt.Resume != nil {
+		if err := s.resume(opt.Resume, witness, witnessOffset); err != nil {
+			return nil, fmt.Errorf("resuming from snapshot: %w", err)
+		}
+	}
+
 	return &s, nil
 }
 
+// assign sets the values of the internal wires provided by the caller, by wire ID or by label.
+func (s *solver) assign(wires map[int]any, labels map[string]any) error {
+	s.assigned = make([]bool, len(s.values))
+	nbInputs := len(s.Public) + len(s.Secret)
+	assign := func(id int, v any) error {
+		if id < nbInputs || id >= len(s.values) {
+			return fmt.Errorf("wire %d is not an internal wire", id)
+		}
+		if s.assigned[id] {
+			return fmt.Errorf("wire %d is assigned twice", id)
+		}
+		if _, err := s.values[id].SetInterface(v); err != nil {
+			return fmt.Errorf("wire %d: %w", id, err)
+		}
+		s.assigned[id] = true
+		s.solved[id] = true
+		s.nbSolved++
+		return nil
+	}
+	for id, v := range wires {
+		if err := assign(id, v); err != nil {
+			return err
+		}
+	}
+	for label, v := range labels {
+		id, ok := s.GetLabeledWire(label)
+		if !ok {
+			return fmt.Errorf("unknown wire label %q", label)
+		}
+		if err := assign(id, v); err != nil {
+			return fmt.Errorf("label %q: %w", label, err)
+		}
+	}
+	return nil
+}
+
+// checkAssigned records an error if the value computed for an assigned wire doesn't match
+// the provided one.
+func (s *solver) checkAssigned(id int, value fr.Element) {
+	if value.Equal(&s.values[id]) {
+		return
+	}
+	name, ok := s.WireLabel(id)
+	if !ok {
+		name = s.VariableToString(id)
+	}
+	s.muAssigned.Lock()
+	defer s.muAssigned.Unlock()
+	if s.errAssigned == nil {
+		s.errAssigned = fmt.Errorf("wire %s: assigned value %s doesn't match the computed value %s", name, s.values[id].String(), value.String())
+	}
+}
+
+// assignedError returns the first mismatch recorded by checkAssigned, if any.
+func (s *solver) assignedError() error {
+	if s.assigned == nil {
+		return nil
+	}
+	s.muAssigned.Lock()
+	defer s.muAssigned.Unlock()
+	return s.errAssigned
+}
+
 func (s *solver) set(id int, value fr.Element) {
 	if s.solved[id] {
+		if s.assigned != nil && s.assigned[id] {
+			s.checkAssigned(id, value)
+			return
+		}
 		panic("solving the same wire twice should never happen.")
 	}
 	s.values[id] = value
@@ -204,6 +301,21 @@ func (s *solver) solveWithHint(h *constraint.HintMapping) error {
 	// tmp IO big int memory
 	nbInputs := len(h.Inputs)
 	nbOutputs := int(h.OutputRange.End - h.OutputRange.Start)
+
+	// the hint isn't called if all its outputs are assigned; the outputs of a hint are not
+	// necessarily unique, assigned outputs are kept as is.
+	if s.assigned != nil {
+		nbAssigned := 0
+		for i := 0; i < nbOutputs; i++ {
+			if s.assigned[int(h.OutputRange.Start)+i] {
+				nbAssigned++
+			}
+		}
+		if nbAssigned == nbOutputs {
+			return nil
+		}
+	}
+
 	inputs := make([]*big.Int, nbInputs)
 	outputs := make([]*big.Int, nbOutputs)
 	for i := 0; i < nbOutputs; i++ {
@@ -231,8 +343,10 @@ func (s *solver) solveWithHint(h *constraint.HintMapping) error {
 
 	var v fr.Element
 	for i := range outputs {
-		v.SetBigInt(outputs[i])
-		s.set(int(h.OutputRange.Start)+i, v)
+		if id := int(h.OutputRange.Start) + i; s.assigned == nil || !s.assigned[id] {
+			v.SetBigInt(outputs[i])
+			s.set(id, v)
+		}
 		pool.BigInt.Put(outputs[i])
 	}
 
@@ -252,7 +366,11 @@ func (s *solver) printLogs(logs []constraint.LogEntry) {
 
 	for i := 0; i < len(logs); i++ {
 		logLine := s.logValue(logs[i])
-		s.logger.Debug().Str(zerolog.CallerFieldName, logs[i].Caller).Msg(logLine)
+		e := s.logger.Debug().Str(zerolog.CallerFieldName, logs[i].Caller)
+		if logs[i].Scope != "" {
+			e = e.Str("scope", logs[i].Scope)
+		}
+		e.Msg(logLine)
 	}
 }
 
@@ -460,7 +578,11 @@ func (solver *solver) run() error {
 	var scratch scratch
 
 	// for each level, we push the tasks
-	for _, level := range solver.Levels {
+	for l := solver.startLevel; l < len(solver.Levels); l++ {
+		level := solver.Levels[l]
+		if err := solver.saveSnapshot(l); err != nil {
+			return err
+		}
 
 		// max CPU to use
 		maxCPU := float64(len(level)) / minWorkPerCPU
@@ -472,6 +594,9 @@ func (solver *solver) run() error {
 					return err
 				}
 			}
+			if err := solver.assignedError(); err != nil {
+				return err
+			}
 			continue
 		}
 
@@ -514,6 +639,9 @@ func (solver *solver) run() error {
 		if len(chError) > 0 {
 			return <-chError
 		}
+		if err := solver.assignedError(); err != nil {
+			return err
+		}
 	}
 
 	if int(solver.nbSolved) != len(solver.values) {
@@ -622,13 +750,18 @@ type UnsatisfiedConstraintError struct {
 	Err       error
 	CID       int     // constraint ID
 	DebugInfo *string // optional debug info
+	Scope     string  // path of the scope of the constraint, if any
 }
 
 func (r *UnsatisfiedConstraintError) Error() string {
+	constraint := fmt.Sprintf("constraint #%d", r.CID)
+	if r.Scope != "" {
+		constraint = fmt.Sprintf("constraint #%d in %s", r.CID, r.Scope)
+	}
 	if r.DebugInfo != nil {
-		return fmt.Sprintf("constraint #%d is not satisfied: %s", r.CID, *r.DebugInfo)
+		return fmt.Sprintf("%s is not satisfied: %s", constraint, *r.DebugInfo)
 	}
-	return fmt.Sprintf("constraint #%d is not satisfied: %s", r.CID, r.Err.Error())
+	return fmt.Sprintf("%s is not satisfied: %s", constraint, r.Err.Error())
 }
 
 func (solver *solver) wrapErrWithDebugInfo(cID uint32, err error) *UnsatisfiedConstraintError {
@@ -637,7 +770,7 @@ func (solver *solver) wrapErrWithDebugInfo(cID uint32, err error) *UnsatisfiedCo
 		debugInfo = new(string)
 		*debugInfo = solver.logValue(solver.DebugInfo[dID])
 	}
-	return &UnsatisfiedConstraintError{CID: int(cID), Err: err, DebugInfo: debugInfo}
+	return &UnsatisfiedConstraintError{CID: int(cID), Err: err, DebugInfo: debugInfo, Scope: solver.ConstraintScope(int(cID))}
 }
 
 // temporary variables to avoid memallocs in hotloop
@@ -645,3 +778,154 @@ type scratch struct {
 	tR1C  constraint.R1C
 	tHint constraint.HintMapping
 }
+
+// snapshotMagic starts the binary encoding of a solver snapshot
+const snapshotMagic = "gnark solver snapshot v1"
+
+// snapshot implements csolver.Snapshot; it is only valid while the solver waits for
+// snapshotSave to return.
+type snapshot struct {
+	s     *solver
+	level int
+}
+
+func (sn *snapshot) Level() int {
+	return sn.level
+}
+
+// WriteTo writes the snapshot: header (magic, modulus, number of wires and constraints,
+// level), solved wires bitmap, wire values and, for R1CS, the evaluated constraints.
+func (sn *snapshot) WriteTo(w io.Writer) (int64, error) {
+	s := sn.s
+	cw := ioutils.WriterCounter{W: w}
+	bw := bufio.NewWriter(&cw)
+
+	modulus := s.q.Bytes()
+	header := []any{
+		[]byte(snapshotMagic),
+		uint32(len(modulus)), modulus,
+		uint64(len(s.values)), uint64(len(s.a)), uint64(sn.level),
+	}
+	for _, v := range header {
+		if err := binary.Write(bw, binary.BigEndian, v); err != nil {
+			return cw.N, err
+		}
+	}
+
+	bitmap := make([]byte, (len(s.solved)+7)/8)
+	for i, solved := range s.solved {
+		if solved {
+			bitmap[i/8] |= 1 << (i % 8)
+		}
+	}
+	if _, err := bw.Write(bitmap); err != nil {
+		return cw.N, err
+	}
+
+	var buf [fr.Bytes]byte
+	for _, v := range []fr.Vector{s.values, s.a, s.b, s.c} {
+		for i := range v {
+			fr.BigEndian.PutElement(&buf, v[i])
+			if _, err := bw.Write(buf[:]); err != nil {
+				return cw.N, err
+			}
+		}
+	}
+	err := bw.Flush()
+	return cw.N, err
+}
+
+// saveSnapshot saves a snapshot if level levels were solved since the last one.
+func (s *solver) saveSnapshot(level int) error {
+	if s.snapshotInterval <= 0 || level == s.startLevel || (level-s.startLevel)%s.snapshotInterval != 0 {
+		return nil
+	}
+	if err := s.snapshotSave(&snapshot{s: s, level: level}); err != nil {
+		return fmt.Errorf("saving snapshot at level %d: %w", level, err)
+	}
+	return nil
+}
+
+// resume restores the state of the solver from a snapshot, after checking that it matches
+// the constraint system and the witness.
+func (s *solver) resume(r io.Reader, witness fr.Vector, witnessOffset int) error {
+	br := bufio.NewReader(r)
+
+	magic := make([]byte, len(snapshotMagic))
+	if _, err := io.ReadFull(br, magic); err != nil {
+		return err
+	}
+	if string(magic) != snapshotMagic {
+		return errors.New("invalid snapshot header")
+	}
+	var modulusLen uint32
+	if err := binary.Read(br, binary.BigEndian, &modulusLen); err != nil {
+		return err
+	}
+	if modulusLen > 1024 {
+		return errors.New("invalid snapshot modulus")
+	}
+	modulus := make([]byte, modulusLen)
+	if _, err := io.ReadFull(br, modulus); err != nil {
+		return err
+	}
+	if new(big.Int).SetBytes(modulus).Cmp(s.q) != 0 {
+		return errors.New("the snapshot field doesn't match the constraint system")
+	}
+	var nbWires, nbConstraints, level uint64
+	for _, v := range []*uint64{&nbWires, &nbConstraints, &level} {
+		if err := binary.Read(br, binary.BigEndian, v); err != nil {
+			return err
+		}
+	}
+	if nbWires != uint64(len(s.values)) || nbConstraints != uint64(len(s.a)) {
+		return errors.New("the snapshot doesn't match the constraint system")
+	}
+	if level > uint64(len(s.Levels)) {
+		return fmt.Errorf("invalid snapshot level %d", level)
+	}
+
+	bitmap := make([]byte, (len(s.solved)+7)/8)
+	if _, err := io.ReadFull(br, bitmap); err != nil {
+		return err
+	}
+
+	// the inputs and the assigned wires must match the snapshot
+	expected := make(map[int]fr.Element)
+	for i := range witness {
+		expected[i+witnessOffset] = witness[i]
+	}
+	for i := range s.assigned {
+		if s.assigned[i] {
+			expected[i] = s.values[i]
+		}
+	}
+
+	var buf [fr.Bytes]byte
+	for _, v := range []fr.Vector{s.values, s.a, s.b, s.c} {
+		for i := range v {
+			if _, err := io.ReadFull(br, buf[:]); err != nil {
+				return err
+			}
+			if err := v[i].SetBytesCanonical(buf[:]); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, v := range expected {
+		if !v.Equal(&s.values[i]) {
+			return fmt.Errorf("wire %s doesn't match the snapshot", s.VariableToString(i))
+		}
+	}
+
+	s.nbSolved = 0
+	for i := range s.solved {
+		s.solved[i] = bitmap[i/8]&(1<<(i%8)) != 0
+		if s.solved[i] {
+			s.nbSolved++
+		}
+	}
+	s.startLevel = int(level)
+	return nil
+}
@@ -0,0 +1,145 @@
+// Package circom imports circuits compiled by circom (https://github.com/iden3/circom).
+//
+// ReadR1CS reads the iden3 .r1cs binary format and returns a constraint.R1CS for the curve
+// matching the prime of the file, which can then be used with the Groth16 backend:
+//
+//	ccs, err := circom.ReadR1CS(r1csFile)
+//	w, opt, err := circom.ReadWitness(wtnsFile, ccs)
+//	pk, vk, err := groth16.Setup(ccs)
+//	proof, err := groth16.Prove(ccs, pk, w, backend.WithSolverOptions(opt))
+//
+// Circom circuits are solved by the witness calculator generated by circom, not by the
+// constraints: many internal wires (e.g. bits of a decomposition) can't be deduced from the
+// constraints alone. The imported constraint system starts with a hint computing all the internal
+// wires; ReadWitness reads a .wtns file (output of the witness calculator) and returns the
+// public and secret inputs as a witness.Witness, and a solver option providing the hint with
+// the values of the internal wires.
+//
+// Wires keep the circom numbering: wire 0 is the constant 1, followed by the public outputs,
+// the public inputs, the private inputs and the internal wires. Inputs are named after their
+// circom wire index ("w1", "w2", ...). Circom custom gates (PLONK) are not supported.
+package circom
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// binFile is a file in the iden3 binary format: magic, version and a list of sections.
+type binFile struct {
+	version  uint32
+	sections map[uint32][]byte
+}
+
+func readBinFile(r io.Reader, magic string, maxVersion uint32) (*binFile, error) {
+	var header [12]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	if string(header[:4]) != magic {
+		return nil, fmt.Errorf("invalid file type %q, expected %q", header[:4], magic)
+	}
+	f := &binFile{
+		version:  binary.LittleEndian.Uint32(header[4:8]),
+		sections: make(map[uint32][]byte),
+	}
+	if f.version == 0 || f.version > maxVersion {
+		return nil, fmt.Errorf("unsupported %s version %d", magic, f.version)
+	}
+	nbSections := binary.LittleEndian.Uint32(header[8:12])
+	for i := uint32(0); i < nbSections; i++ {
+		var sh [12]byte
+		if _, err := io.ReadFull(r, sh[:]); err != nil {
+			return nil, fmt.Errorf("reading section header: %w", err)
+		}
+		sType := binary.LittleEndian.Uint32(sh[:4])
+		size := binary.LittleEndian.Uint64(sh[4:12])
+		if _, ok := f.sections[sType]; ok {
+			return nil, fmt.Errorf("duplicate section %d", sType)
+		}
+		var buf bytes.Buffer
+		n, err := buf.ReadFrom(io.LimitReader(r, int64(size)))
+		if err != nil {
+			return nil, fmt.Errorf("reading section %d: %w", sType, err)
+		}
+		if uint64(n) != size {
+			return nil, fmt.Errorf("reading section %d: %w", sType, io.ErrUnexpectedEOF)
+		}
+		f.sections[sType] = buf.Bytes()
+	}
+	return f, nil
+}
+
+// section returns a decoder over the section of the given type.
+func (f *binFile) section(sType uint32) (*decoder, error) {
+	s, ok := f.sections[sType]
+	if !ok {
+		return nil, fmt.Errorf("missing section %d", sType)
+	}
+	return &decoder{buf: s}, nil
+}
+
+var errShortSection = errors.New("section too short")
+
+// decoder reads little endian values from a section; the first error is sticky.
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || len(d.buf) < n {
+		d.err = errShortSection
+		return nil
+	}
+	r := d.buf[:n]
+	d.buf = d.buf[n:]
+	return r
+}
+
+func (d *decoder) uint32() uint32 {
+	b := d.next(4)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(b)
+}
+
+func (d *decoder) uint64() uint64 {
+	b := d.next(8)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(b)
+}
+
+// element reads a n8 bytes little endian integer.
+func (d *decoder) element(n8 int) *big.Int {
+	b := d.next(n8)
+	if b == nil {
+		return nil
+	}
+	be := make([]byte, n8)
+	for i := range b {
+		be[n8-1-i] = b[i]
+	}
+	return new(big.Int).SetBytes(be)
+}
+
+// readField reads the field definition (size in bytes and prime) at the start of the
+// header of both .r1cs and .wtns files.
+func (d *decoder) readField() (n8 int, prime *big.Int, err error) {
+	n8 = int(d.uint32())
+	if d.err == nil && (n8 == 0 || n8%8 != 0 || n8 > 1024) {
+		return 0, nil, fmt.Errorf("invalid field element size %d", n8)
+	}
+	prime = d.element(n8)
+	return n8, prime, d.err
+}
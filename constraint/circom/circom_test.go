@@ -0,0 +1,215 @@
+package circom_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/constraint/circom"
+	"github.com/stretchr/testify/require"
+)
+
+// the test files are written in the .r1cs and .wtns formats by the helpers below, for the
+// constraints circom produces for
+//
+//	template Main() {
+//	    signal input a;
+//	    signal input b;
+//	    signal output out;
+//	    signal bits[4];
+//	    out <== a * b;
+//	    var acc = 0;
+//	    for (var i = 0; i < 4; i++) {
+//	        bits[i] <-- (a >> i) & 1;
+//	        bits[i] * (bits[i] - 1) === 0;
+//	        acc += bits[i] * 2**i;
+//	    }
+//	    acc === a;
+//	}
+//
+// wires: 0 (one), 1 (out), 2 (a), 3 (b), 4..7 (bits).
+type term struct {
+	wire  uint32
+	coeff int64
+}
+
+type r1c [3][]term
+
+var testConstraints = []r1c{
+	{{{2, 1}}, {{3, 1}}, {{1, 1}}},
+	{{{4, 1}}, {{0, -1}, {4, 1}}, nil},
+	{{{5, 1}}, {{0, -1}, {5, 1}}, nil},
+	{{{6, 1}}, {{0, -1}, {6, 1}}, nil},
+	{{{7, 1}}, {{0, -1}, {7, 1}}, nil},
+	{nil, nil, {{2, -1}, {4, 1}, {5, 2}, {6, 4}, {7, 8}}},
+}
+
+// testWitness returns the circom witness for the given inputs.
+func testWitness(a, b int64) []int64 {
+	w := []int64{1, a * b, a, b}
+	for i := 0; i < 4; i++ {
+		w = append(w, (a>>i)&1)
+	}
+	return w
+}
+
+type section struct {
+	sType uint32
+	data  bytes.Buffer
+}
+
+func (s *section) uint32(v uint32) { _ = binary.Write(&s.data, binary.LittleEndian, v) }
+func (s *section) uint64(v uint64) { _ = binary.Write(&s.data, binary.LittleEndian, v) }
+
+func (s *section) element(v *big.Int, prime *big.Int, n8 int) {
+	v = new(big.Int).Mod(v, prime)
+	be := v.FillBytes(make([]byte, n8))
+	for i := n8 - 1; i >= 0; i-- {
+		s.data.WriteByte(be[i])
+	}
+}
+
+func binFile(magic string, version uint32, sections ...*section) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(magic)
+	_ = binary.Write(&buf, binary.LittleEndian, version)
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(len(sections)))
+	for _, s := range sections {
+		_ = binary.Write(&buf, binary.LittleEndian, s.sType)
+		_ = binary.Write(&buf, binary.LittleEndian, uint64(s.data.Len()))
+		buf.Write(s.data.Bytes())
+	}
+	return buf.Bytes()
+}
+
+func writeR1CS(prime *big.Int) []byte {
+	const nbWires = 8
+	n8 := ((prime.BitLen() + 63) / 64) * 8
+	header := &section{sType: 1}
+	header.uint32(uint32(n8))
+	header.element(prime, new(big.Int).Add(prime, big.NewInt(1)), n8)
+	header.uint32(nbWires)
+	header.uint32(1) // public outputs
+	header.uint32(0) // public inputs
+	header.uint32(2) // private inputs
+	header.uint64(nbWires)
+	header.uint32(uint32(len(testConstraints)))
+
+	constraints := &section{sType: 2}
+	for _, c := range testConstraints {
+		for _, l := range c {
+			constraints.uint32(uint32(len(l)))
+			for _, t := range l {
+				constraints.uint32(t.wire)
+				constraints.element(big.NewInt(t.coeff), prime, n8)
+			}
+		}
+	}
+
+	labels := &section{sType: 3}
+	for i := 0; i < nbWires; i++ {
+		labels.uint64(uint64(i))
+	}
+
+	// sections may appear in any order
+	return binFile("r1cs", 1, header, labels, constraints)
+}
+
+func writeWtns(prime *big.Int, values []int64) []byte {
+	n8 := ((prime.BitLen() + 63) / 64) * 8
+	header := &section{sType: 1}
+	header.uint32(uint32(n8))
+	header.element(prime, new(big.Int).Add(prime, big.NewInt(1)), n8)
+	header.uint32(uint32(len(values)))
+
+	data := &section{sType: 2}
+	for _, v := range values {
+		data.element(big.NewInt(v), prime, n8)
+	}
+	return binFile("wtns", 2, header, data)
+}
+
+func TestImport(t *testing.T) {
+	for _, curve := range []ecc.ID{ecc.BN254, ecc.BLS12_381} {
+		assert := require.New(t)
+		prime := curve.ScalarField()
+
+		ccs, err := circom.ReadR1CS(bytes.NewReader(writeR1CS(prime)))
+		assert.NoError(err)
+		assert.Equal(0, prime.Cmp(ccs.Field()))
+		assert.Equal(len(testConstraints), ccs.GetNbConstraints())
+		assert.Equal(2, ccs.GetNbPublicVariables())
+		assert.Equal(2, ccs.GetNbSecretVariables())
+		assert.Equal(4, ccs.GetNbInternalVariables())
+
+		w, opt, err := circom.ReadWitness(bytes.NewReader(writeWtns(prime, testWitness(11, 3))), ccs)
+		assert.NoError(err)
+		_, err = ccs.Solve(w, opt)
+		assert.NoError(err)
+
+		// the internal wires can't be solved without the circom witness
+		_, err = ccs.Solve(w)
+		assert.Error(err)
+
+		// wrong internal wire
+		bad := testWitness(11, 3)
+		bad[5] = 0
+		w, opt, err = circom.ReadWitness(bytes.NewReader(writeWtns(prime, bad)), ccs)
+		assert.NoError(err)
+		_, err = ccs.Solve(w, opt)
+		assert.Error(err)
+
+		// wrong output
+		bad = testWitness(11, 3)
+		bad[1] = 34
+		w, opt, err = circom.ReadWitness(bytes.NewReader(writeWtns(prime, bad)), ccs)
+		assert.NoError(err)
+		_, err = ccs.Solve(w, opt)
+		assert.Error(err)
+
+		// wrong number of values
+		_, _, err = circom.ReadWitness(bytes.NewReader(writeWtns(prime, testWitness(11, 3)[:7])), ccs)
+		assert.Error(err)
+	}
+}
+
+func TestImportGroth16(t *testing.T) {
+	assert := require.New(t)
+	prime := ecc.BN254.ScalarField()
+
+	ccs, err := circom.ReadR1CS(bytes.NewReader(writeR1CS(prime)))
+	assert.NoError(err)
+	w, opt, err := circom.ReadWitness(bytes.NewReader(writeWtns(prime, testWitness(5, 7))), ccs)
+	assert.NoError(err)
+
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, w, backend.WithSolverOptions(opt))
+	assert.NoError(err)
+	pw, err := w.Public()
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, vk, pw))
+}
+
+func TestReadR1CSErrors(t *testing.T) {
+	assert := require.New(t)
+	valid := writeR1CS(ecc.BN254.ScalarField())
+
+	_, err := circom.ReadR1CS(bytes.NewReader(valid[:len(valid)-3]))
+	assert.Error(err, "truncated file")
+
+	_, err = circom.ReadR1CS(bytes.NewReader(append([]byte("wtns"), valid[4:]...)))
+	assert.Error(err, "wrong magic")
+
+	_, err = circom.ReadR1CS(bytes.NewReader(writeR1CS(big.NewInt(101))))
+	assert.Error(err, "unknown prime")
+
+	custom := &section{sType: 4}
+	custom.uint32(0)
+	_, err = circom.ReadR1CS(bytes.NewReader(binFile("r1cs", 1, custom)))
+	assert.Error(err, "custom gates")
+}
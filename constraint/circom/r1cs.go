@@ -0,0 +1,175 @@
+package circom
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	cs_bls12377 "github.com/consensys/gnark/constraint/bls12-377"
+	cs_bls12381 "github.com/consensys/gnark/constraint/bls12-381"
+	cs_bls24315 "github.com/consensys/gnark/constraint/bls24-315"
+	cs_bls24317 "github.com/consensys/gnark/constraint/bls24-317"
+	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
+	cs_bw6633 "github.com/consensys/gnark/constraint/bw6-633"
+	cs_bw6761 "github.com/consensys/gnark/constraint/bw6-761"
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+// sections of the .r1cs format
+const (
+	r1csHeader           = 1
+	r1csConstraints      = 2
+	r1csWire2Label       = 3
+	r1csCustomGatesList  = 4
+	r1csCustomGatesApply = 5
+)
+
+// ReadR1CS reads a constraint system in the circom .r1cs binary format. The curve is
+// deduced from the prime of the file; it must be the scalar field of one of the curves
+// supported by gnark (circom's bn128 and bls12381 primes are BN254 and BLS12-381).
+func ReadR1CS(r io.Reader) (constraint.R1CS, error) {
+	f, err := readBinFile(r, "r1cs", 1)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := f.sections[r1csCustomGatesList]; ok {
+		return nil, errors.New("circom custom gates are not supported")
+	}
+	if _, ok := f.sections[r1csCustomGatesApply]; ok {
+		return nil, errors.New("circom custom gates are not supported")
+	}
+
+	// header
+	d, err := f.section(r1csHeader)
+	if err != nil {
+		return nil, err
+	}
+	n8, prime, err := d.readField()
+	if err != nil {
+		return nil, err
+	}
+	nbWires := int(d.uint32())
+	nbPubOut := int(d.uint32())
+	nbPubIn := int(d.uint32())
+	nbPrvIn := int(d.uint32())
+	d.uint64() // nbLabels
+	nbConstraints := int(d.uint32())
+	if d.err != nil {
+		return nil, fmt.Errorf("reading header: %w", d.err)
+	}
+	nbPublic := 1 + nbPubOut + nbPubIn
+	if nbWires < nbPublic+nbPrvIn {
+		return nil, fmt.Errorf("invalid header: %d wires for %d inputs", nbWires, nbPublic+nbPrvIn)
+	}
+
+	curve, err := curveOf(prime)
+	if err != nil {
+		return nil, err
+	}
+	ccs := newR1CS(curve, nbConstraints)
+	if ccs == nil {
+		return nil, fmt.Errorf("curve %s is not supported", curve)
+	}
+
+	// wires, keeping circom's order
+	ccs.AddPublicVariable("1")
+	for i := 1; i < nbPublic; i++ {
+		ccs.AddPublicVariable("w" + strconv.Itoa(i))
+	}
+	for i := nbPublic; i < nbPublic+nbPrvIn; i++ {
+		ccs.AddSecretVariable("w" + strconv.Itoa(i))
+	}
+	if nbInternal := nbWires - nbPublic - nbPrvIn; nbInternal > 0 {
+		inputs := make([]constraint.LinearExpression, nbPubOut+nbPubIn+nbPrvIn)
+		for i := range inputs {
+			inputs[i] = constraint.LinearExpression{ccs.MakeTerm(ccs.FromInterface(1), i+1)}
+		}
+		if _, err := ccs.AddSolverHint(internalWires, solver.GetHintID(internalWires), inputs, nbInternal); err != nil {
+			return nil, err
+		}
+	}
+
+	// constraints
+	d, err = f.section(r1csConstraints)
+	if err != nil {
+		return nil, err
+	}
+	blueprint := ccs.AddBlueprint(&constraint.BlueprintGenericR1C{})
+	readLinearExpression := func() constraint.LinearExpression {
+		nbTerms := int(d.uint32())
+		if d.err != nil || nbTerms > nbWires {
+			d.err = fmt.Errorf("invalid linear expression with %d terms", nbTerms)
+			return nil
+		}
+		l := make(constraint.LinearExpression, 0, nbTerms)
+		for i := 0; i < nbTerms; i++ {
+			wire := int(d.uint32())
+			coeff := d.element(n8)
+			if d.err != nil {
+				return nil
+			}
+			if wire >= nbWires {
+				d.err = fmt.Errorf("wire %d out of range", wire)
+				return nil
+			}
+			l = append(l, ccs.MakeTerm(ccs.FromInterface(coeff), wire))
+		}
+		return l
+	}
+	for i := 0; i < nbConstraints; i++ {
+		var c constraint.R1C
+		c.L = readLinearExpression()
+		c.R = readLinearExpression()
+		c.O = readLinearExpression()
+		if d.err != nil {
+			return nil, fmt.Errorf("reading constraint %d: %w", i, d.err)
+		}
+		ccs.AddR1C(c, blueprint)
+	}
+
+	if d, err := f.section(r1csWire2Label); err == nil && len(d.buf) != 8*nbWires {
+		return nil, fmt.Errorf("invalid wire to label map: %d bytes for %d wires", len(d.buf), nbWires)
+	}
+
+	return ccs, nil
+}
+
+// internalWires is the hint solving the internal wires of a circom circuit. It is a
+// placeholder: the values are provided by the solver option returned by ReadWitness.
+func internalWires(_ *big.Int, _ []*big.Int, _ []*big.Int) error {
+	return errors.New("the internal wires of circom circuits must be provided with the solver option returned by circom.ReadWitness")
+}
+
+func curveOf(prime *big.Int) (ecc.ID, error) {
+	for _, curve := range ecc.Implemented() {
+		if curve.ScalarField().Cmp(prime) == 0 {
+			return curve, nil
+		}
+	}
+	return ecc.UNKNOWN, fmt.Errorf("no curve with scalar field %s", prime.String())
+}
+
+func newR1CS(curve ecc.ID, capacity int) constraint.R1CS {
+	switch curve {
+	case ecc.BN254:
+		return cs_bn254.NewR1CS(capacity)
+	case ecc.BLS12_377:
+		return cs_bls12377.NewR1CS(capacity)
+	case ecc.BLS12_381:
+		return cs_bls12381.NewR1CS(capacity)
+	case ecc.BW6_761:
+		return cs_bw6761.NewR1CS(capacity)
+	case ecc.BLS24_317:
+		return cs_bls24317.NewR1CS(capacity)
+	case ecc.BLS24_315:
+		return cs_bls24315.NewR1CS(capacity)
+	case ecc.BW6_633:
+		return cs_bw6633.NewR1CS(capacity)
+	default:
+		return nil
+	}
+}
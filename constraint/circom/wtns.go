@@ -0,0 +1,104 @@
+package circom
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+// sections of the .wtns format
+const (
+	wtnsHeader = 1
+	wtnsValues = 2
+)
+
+// ReadWitness reads a witness in the circom .wtns binary format (version 2) for the constraint
+// system ccs returned by ReadR1CS.
+//
+// It returns the public and secret inputs as a witness.Witness, and a solver option providing
+// the values of the internal wires to the solver; the option must be passed to Solve, or to the
+// prover with backend.WithSolverOptions.
+func ReadWitness(r io.Reader, ccs constraint.ConstraintSystem) (witness.Witness, solver.Option, error) {
+	f, err := readBinFile(r, "wtns", 2)
+	if err != nil {
+		return nil, nil, err
+	}
+	if f.version != 2 {
+		return nil, nil, fmt.Errorf("unsupported wtns version %d", f.version)
+	}
+
+	d, err := f.section(wtnsHeader)
+	if err != nil {
+		return nil, nil, err
+	}
+	n8, prime, err := d.readField()
+	if err != nil {
+		return nil, nil, err
+	}
+	nbValues := int(d.uint32())
+	if d.err != nil {
+		return nil, nil, fmt.Errorf("reading header: %w", d.err)
+	}
+	if prime.Cmp(ccs.Field()) != 0 {
+		return nil, nil, fmt.Errorf("witness field %s doesn't match the constraint system field %s", prime.String(), ccs.Field().String())
+	}
+	nbInternal, nbSecret, nbPublic := ccs.GetNbVariables()
+	if nbValues != nbInternal+nbSecret+nbPublic {
+		return nil, nil, fmt.Errorf("witness has %d values, the constraint system has %d wires", nbValues, nbInternal+nbSecret+nbPublic)
+	}
+
+	d, err = f.section(wtnsValues)
+	if err != nil {
+		return nil, nil, err
+	}
+	values := make([]*big.Int, nbValues)
+	for i := range values {
+		values[i] = d.element(n8)
+		if d.err != nil {
+			return nil, nil, fmt.Errorf("reading value %d: %w", i, d.err)
+		}
+		if values[i].Cmp(prime) >= 0 {
+			return nil, nil, fmt.Errorf("value %d is not reduced", i)
+		}
+	}
+	if values[0].Cmp(big.NewInt(1)) != 0 {
+		return nil, nil, errors.New("the first value of the witness must be 1")
+	}
+
+	// wire 0 is the constant wire "1" of the R1CS, not part of the gnark witness.
+	nbInputs := nbPublic - 1 + nbSecret
+	w, err := witness.New(ccs.Field())
+	if err != nil {
+		return nil, nil, err
+	}
+	inputs := make(chan any, nbInputs)
+	for _, v := range values[1 : 1+nbInputs] {
+		inputs <- v
+	}
+	close(inputs)
+	if err := w.Fill(nbPublic-1, nbSecret, inputs); err != nil {
+		return nil, nil, err
+	}
+
+	internal := values[1+nbInputs:]
+	hint := func(_ *big.Int, in []*big.Int, out []*big.Int) error {
+		if len(in) != nbInputs || len(out) != len(internal) {
+			return fmt.Errorf("expected %d inputs and %d outputs, got %d and %d", nbInputs, len(internal), len(in), len(out))
+		}
+		for i := range in {
+			if in[i].Cmp(values[1+i]) != 0 {
+				return fmt.Errorf("input w%d doesn't match the circom witness", i+1)
+			}
+		}
+		for i := range out {
+			out[i].Set(internal[i])
+		}
+		return nil
+	}
+	return w, solver.OverrideHint(solver.GetHintID(internalWires), hint), nil
+}
@@ -3,6 +3,7 @@ package constraint
 import (
 	"fmt"
 	"math/big"
+	"sort"
 	"strconv"
 	"sync"
 
@@ -108,6 +109,14 @@ type System struct {
 	// maps hintID to hint string identifier
 	MHintsDependencies map[solver.HintID]string
 
+	// maps the labels of internal wires to their wire ID (see AddWireLabel)
+	WireLabels map[string]int
+
+	// scopes of the constraints (see PushScope): Scopes lists the scope paths, and
+	// ScopeRanges the first constraint of each run of constraints added in the same scope
+	Scopes      []string
+	ScopeRanges []ScopeRange
+
 	// each level contains independent constraints and can be parallelized
 	// it is guaranteed that all dependencies for constraints in a level l are solved
 	// in previous levels
@@ -124,6 +133,10 @@ type System struct {
 	lbWireLevel []int    `cbor:"-"` // at which level we solve a wire. init at -1.
 	lbOutputs   []uint32 `cbor:"-"` // wire outputs for current constraint.
 
+	// scope builder
+	scopeStack []string       `cbor:"-"` // paths of the open scopes
+	scopeIDs   map[string]int `cbor:"-"` // index of the paths in Scopes
+
 	CommitmentInfo Commitments
 	GkrInfo        GkrInfo
 
@@ -153,6 +166,13 @@ func NewSystem(scalarField *big.Int, capacity int, t SystemType) System {
 	return system
 }
 
+// GetSystem returns the curve agnostic part of the constraint system. Curve-typed systems
+// embed System; this gives tooling (diff, export, ...) access to the instructions,
+// blueprints and debug information without knowing the concrete curve.
+func (system *System) GetSystem() *System {
+	return system
+}
+
 // GetNbInstructions returns the number of instructions in the system
 func (system *System) GetNbInstructions() int {
 	return len(system.Instructions)
@@ -312,7 +332,143 @@ func (system *System) AddCommitment(c Commitment) error {
 	return nil
 }
 
+// AddWireLabel labels the internal wire wireID, so that its value can be provided to the
+// solver by name (see solver.WithAssignedLabels). In a scope, the label is prefixed with the
+// path of the scope.
+func (system *System) AddWireLabel(label string, wireID int) error {
+	if scope := system.CurrentScope(); scope != "" {
+		label = scope + "/" + label
+	}
+	nbInputs := system.GetNbPublicVariables() + system.GetNbSecretVariables()
+	if wireID < nbInputs || wireID >= nbInputs+system.NbInternalVariables {
+		return fmt.Errorf("label %q: wire %d is not an internal wire", label, wireID)
+	}
+	if system.WireLabels == nil {
+		system.WireLabels = make(map[string]int)
+	}
+	if _, ok := system.WireLabels[label]; ok {
+		return fmt.Errorf("label %q is already used", label)
+	}
+	system.WireLabels[label] = wireID
+	return nil
+}
+
+// GetLabeledWire returns the ID of the internal wire with the given label.
+func (system *System) GetLabeledWire(label string) (wireID int, ok bool) {
+	wireID, ok = system.WireLabels[label]
+	return
+}
+
+// ScopeRange records that the constraints starting at Start, until the next range, were
+// added in the scope Scopes[Scope]; Scope is -1 outside any scope.
+type ScopeRange struct {
+	Start int
+	Scope int
+}
+
+// PushScope opens the scope name, nested in the current scope; name may itself be a
+// "/"-separated path. The constraints added until the scope is closed with PopScope, and the
+// labels of wires (see AddWireLabel), are recorded under the path of the scope.
+func (system *System) PushScope(name string) {
+	path := name
+	if current := system.CurrentScope(); current != "" {
+		path = current + "/" + name
+	}
+	system.scopeStack = append(system.scopeStack, path)
+	system.enterScope(path)
+}
+
+// PopScope closes the current scope.
+func (system *System) PopScope() {
+	if len(system.scopeStack) == 0 {
+		panic("no open scope")
+	}
+	system.scopeStack = system.scopeStack[:len(system.scopeStack)-1]
+	system.enterScope(system.CurrentScope())
+}
+
+// CurrentScope returns the path of the current scope, or "" outside any scope.
+func (system *System) CurrentScope() string {
+	if len(system.scopeStack) == 0 {
+		return ""
+	}
+	return system.scopeStack[len(system.scopeStack)-1]
+}
+
+// enterScope records that the next constraints are added in the scope path.
+func (system *System) enterScope(path string) {
+	id := system.scopeID(path)
+	if n := len(system.ScopeRanges); n > 0 {
+		last := &system.ScopeRanges[n-1]
+		if last.Scope == id {
+			return
+		}
+		if last.Start == system.NbConstraints {
+			// no constraint was added in the last range, it is replaced or merged with the
+			// previous one
+			previous := -1
+			if n > 1 {
+				previous = system.ScopeRanges[n-2].Scope
+			}
+			if previous == id {
+				system.ScopeRanges = system.ScopeRanges[:n-1]
+			} else {
+				last.Scope = id
+			}
+			return
+		}
+	} else if id == -1 {
+		return
+	}
+	system.ScopeRanges = append(system.ScopeRanges, ScopeRange{Start: system.NbConstraints, Scope: id})
+}
+
+// scopeID returns the index of path in Scopes, registering it if needed, and -1 for "".
+func (system *System) scopeID(path string) int {
+	if path == "" {
+		return -1
+	}
+	if system.scopeIDs == nil {
+		system.scopeIDs = make(map[string]int, len(system.Scopes))
+		for i, s := range system.Scopes {
+			system.scopeIDs[s] = i
+		}
+	}
+	id, ok := system.scopeIDs[path]
+	if !ok {
+		id = len(system.Scopes)
+		system.Scopes = append(system.Scopes, path)
+		system.scopeIDs[path] = id
+	}
+	return id
+}
+
+// ConstraintScope returns the path of the scope in which the constraint cID was added, or ""
+// if it was added outside any scope.
+func (system *System) ConstraintScope(cID int) string {
+	i := sort.Search(len(system.ScopeRanges), func(i int) bool {
+		return system.ScopeRanges[i].Start > cID
+	})
+	if i == 0 || system.ScopeRanges[i-1].Scope < 0 {
+		return ""
+	}
+	return system.Scopes[system.ScopeRanges[i-1].Scope]
+}
+
+// WireLabel returns the label of the wire wireID, if it was labelled (see AddWireLabel).
+func (system *System) WireLabel(wireID int) (label string, ok bool) {
+	for l, id := range system.WireLabels {
+		if id == wireID {
+			return l, true
+		}
+	}
+	return "", false
+}
+
 func (system *System) AddLog(l LogEntry) {
+	if l.Scope == "" {
+		l.Scope = system.CurrentScope()
+	}
 	system.Logs = append(system.Logs, l)
 }
 
@@ -341,7 +497,7 @@ func (system *System) VariableToString(vID int) string {
 }
 
 func (cs *System) AddR1C(c R1C, bID BlueprintID) int {
-	profile.RecordConstraint()
+	profile.RecordScopedConstraint(cs.CurrentScope())
 
 	blueprint := cs.Blueprints[bID]
 
@@ -359,7 +515,7 @@ func (cs *System) AddR1C(c R1C, bID BlueprintID) int {
 }
 
 func (cs *System) AddSparseR1C(c SparseR1C, bID BlueprintID) int {
-	profile.RecordConstraint()
+	profile.RecordScopedConstraint(cs.CurrentScope())
 
 	blueprint := cs.Blueprints[bID]
 
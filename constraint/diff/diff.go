@@ -0,0 +1,316 @@
+// Package diff compares two compiled constraint systems (or two constraint profiles) and
+// reports where constraints were added or removed.
+//
+// It is meant to be used in code review: compile a circuit before and after a change,
+// and the Report shows the constraint-level impact of the change, grouped by blueprint type,
+// source location, hints, commitments and inputs.
+//
+// Constraint systems only record source locations for constraints carrying debug information
+// (assertions, or all constraints when compiled with -tags=debug). For an exhaustive attribution
+// by call site, compare two profiles (see package profile) with Profiles.
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/consensys/gnark/constraint"
+	"github.com/google/pprof/profile"
+)
+
+// UnknownLocation is the location used for constraints with no debug information attached.
+const UnknownLocation = "<unknown>"
+
+// Entry is a counter measured in the two compared objects.
+type Entry struct {
+	Name string
+	A, B int
+}
+
+// Delta returns B - A.
+func (e Entry) Delta() int {
+	return e.B - e.A
+}
+
+// Report is the result of a comparison between A (before) and B (after).
+type Report struct {
+	// Summary holds the global counters (constraints, instructions, wires, ...)
+	Summary []Entry
+
+	// Blueprints counts instructions per blueprint type.
+	Blueprints []Entry
+
+	// Locations counts constraints per source location ("function file:line").
+	Locations []Entry
+
+	// Functions counts constraints per function appearing in the call stack (cumulative).
+	// It is only set when comparing profiles.
+	Functions []Entry
+
+	// Hints counts hint calls per hint name.
+	Hints []Entry
+
+	// Commitments counts commitments and committed wires.
+	Commitments []Entry
+
+	// PublicAdded, PublicRemoved, SecretAdded and SecretRemoved list the input wire names
+	// present in only one of the two systems.
+	PublicAdded, PublicRemoved []string
+	SecretAdded, SecretRemoved []string
+}
+
+// Systems compares the constraint systems a and b. They must be defined over the same field.
+func Systems(a, b constraint.ConstraintSystem) (*Report, error) {
+	if a.Field().Cmp(b.Field()) != 0 {
+		return nil, fmt.Errorf("constraint systems are defined over different fields")
+	}
+	sa, sb := newStats(a.GetSystem()), newStats(b.GetSystem())
+
+	r := &Report{
+		Summary:     merge(sa.summary, sb.summary),
+		Blueprints:  merge(sa.blueprints, sb.blueprints),
+		Locations:   merge(sa.locations, sb.locations),
+		Hints:       merge(sa.hints, sb.hints),
+		Commitments: merge(sa.commitments, sb.commitments),
+	}
+	r.PublicAdded, r.PublicRemoved = diffNames(a.GetSystem().Public, b.GetSystem().Public)
+	r.SecretAdded, r.SecretRemoved = diffNames(a.GetSystem().Secret, b.GetSystem().Secret)
+
+	return r, nil
+}
+
+// Profiles compares two pprof profiles produced by package profile (one sample per constraint).
+//
+// Constraints are attributed to the innermost location of their call stack (Locations) and
+// cumulatively to every function of the stack (Functions).
+func Profiles(a, b *profile.Profile) *Report {
+	la, fa := profileStats(a)
+	lb, fb := profileStats(b)
+	return &Report{
+		Summary: []Entry{{
+			Name: "constraints",
+			A:    len(a.Sample),
+			B:    len(b.Sample),
+		}},
+		Locations: merge(la, lb),
+		Functions: merge(fa, fb),
+	}
+}
+
+// IsEmpty returns true if the report contains no difference.
+func (r *Report) IsEmpty() bool {
+	for _, entries := range [][]Entry{r.Summary, r.Blueprints, r.Locations, r.Functions, r.Hints, r.Commitments} {
+		if len(changed(entries)) != 0 {
+			return false
+		}
+	}
+	return len(r.PublicAdded)+len(r.PublicRemoved)+len(r.SecretAdded)+len(r.SecretRemoved) == 0
+}
+
+// String returns a human readable report listing only the changed entries.
+func (r *Report) String() string {
+	var sbb strings.Builder
+	w := tabwriter.NewWriter(&sbb, 0, 4, 2, ' ', 0)
+
+	writeSection := func(title string, entries []Entry) {
+		entries = changed(entries)
+		if len(entries) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "%s\tbefore\tafter\tdelta\n", title)
+		for _, e := range entries {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%+d\n", e.Name, e.A, e.B, e.Delta())
+		}
+		fmt.Fprintln(w)
+	}
+
+	writeSection("summary", r.Summary)
+	writeSection("blueprints (instructions)", r.Blueprints)
+	writeSection("locations (constraints)", r.Locations)
+	writeSection("functions (cumulative constraints)", r.Functions)
+	writeSection("hints (calls)", r.Hints)
+	writeSection("commitments", r.Commitments)
+	_ = w.Flush()
+
+	writeNames := func(title string, names []string) {
+		if len(names) == 0 {
+			return
+		}
+		sbb.WriteString(title)
+		sbb.WriteString(": ")
+		sbb.WriteString(strings.Join(names, ", "))
+		sbb.WriteByte('\n')
+	}
+	writeNames("public inputs added", r.PublicAdded)
+	writeNames("public inputs removed", r.PublicRemoved)
+	writeNames("secret inputs added", r.SecretAdded)
+	writeNames("secret inputs removed", r.SecretRemoved)
+
+	if sbb.Len() == 0 {
+		return "no difference\n"
+	}
+	return sbb.String()
+}
+
+// stats holds the counters of a single constraint system.
+type stats struct {
+	summary, blueprints, locations, hints, commitments map[string]int
+}
+
+func newStats(cs *constraint.System) stats {
+	s := stats{
+		summary:     make(map[string]int),
+		blueprints:  make(map[string]int),
+		locations:   make(map[string]int),
+		hints:       make(map[string]int),
+		commitments: make(map[string]int),
+	}
+
+	s.summary["constraints"] = cs.GetNbConstraints()
+	s.summary["instructions"] = cs.GetNbInstructions()
+	s.summary["internal wires"] = cs.GetNbInternalVariables()
+	s.summary["secret wires"] = cs.GetNbSecretVariables()
+	s.summary["public wires"] = cs.GetNbPublicVariables()
+	s.summary["levels"] = len(cs.Levels)
+
+	var hm constraint.HintMapping
+	for _, pi := range cs.Instructions {
+		blueprint := cs.Blueprints[pi.BlueprintID]
+		s.blueprints[blueprintName(blueprint)]++
+
+		inst := pi.Unpack(cs)
+		for i := 0; i < blueprint.NbConstraints(); i++ {
+			s.locations[constraintLocation(cs, int(inst.ConstraintOffset)+i)]++
+		}
+
+		if bh, ok := blueprint.(constraint.BlueprintHint); ok {
+			bh.DecompressHint(&hm, inst)
+			name, ok := cs.MHintsDependencies[hm.HintID]
+			if !ok {
+				name = fmt.Sprintf("%d", hm.HintID)
+			}
+			s.hints[name]++
+		}
+	}
+
+	switch c := cs.CommitmentInfo.(type) {
+	case constraint.Groth16Commitments:
+		s.commitments["commitments"] = len(c)
+		for i := range c {
+			s.commitments["committed public wires"] += len(c[i].PublicAndCommitmentCommitted)
+			s.commitments["committed private wires"] += len(c[i].PrivateCommitted)
+		}
+	case constraint.PlonkCommitments:
+		s.commitments["commitments"] = len(c)
+		for i := range c {
+			s.commitments["committed wires"] += len(c[i].Committed)
+		}
+	}
+	if cs.GkrInfo.Is() {
+		s.commitments["gkr sub-circuits"] = 1
+	}
+
+	return s
+}
+
+// constraintLocation returns the innermost location recorded in the debug info of the given constraint.
+func constraintLocation(cs *constraint.System, cID int) string {
+	dID, ok := cs.MDebug[cID]
+	if !ok || len(cs.DebugInfo[dID].Stack) == 0 {
+		return UnknownLocation
+	}
+	l := cs.SymbolTable.Locations[cs.DebugInfo[dID].Stack[0]]
+	f := cs.SymbolTable.Functions[l.FunctionID]
+	return fmt.Sprintf("%s %s:%d", f.Name, f.Filename, l.Line)
+}
+
+func blueprintName(b constraint.Blueprint) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", b), "*")
+}
+
+func profileStats(p *profile.Profile) (locations, functions map[string]int) {
+	locations = make(map[string]int)
+	functions = make(map[string]int)
+	for _, s := range p.Sample {
+		if len(s.Location) == 0 || len(s.Location[0].Line) == 0 {
+			locations[UnknownLocation]++
+			continue
+		}
+		line := s.Location[0].Line[0]
+		locations[fmt.Sprintf("%s %s:%d", line.Function.Name, line.Function.Filename, line.Line)]++
+
+		// count each function once per sample, even for recursive calls.
+		seen := make(map[string]struct{})
+		for _, l := range s.Location {
+			for _, line := range l.Line {
+				if _, ok := seen[line.Function.Name]; ok {
+					continue
+				}
+				seen[line.Function.Name] = struct{}{}
+				functions[line.Function.Name]++
+			}
+		}
+	}
+	return
+}
+
+// merge returns the entries of a and b sorted by decreasing absolute delta, then by name.
+func merge(a, b map[string]int) []Entry {
+	entries := make([]Entry, 0, len(a))
+	for name, n := range a {
+		entries = append(entries, Entry{Name: name, A: n, B: b[name]})
+	}
+	for name, n := range b {
+		if _, ok := a[name]; !ok {
+			entries = append(entries, Entry{Name: name, B: n})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		di, dj := abs(entries[i].Delta()), abs(entries[j].Delta())
+		if di != dj {
+			return di > dj
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
+
+func changed(entries []Entry) []Entry {
+	var r []Entry
+	for _, e := range entries {
+		if e.Delta() != 0 {
+			r = append(r, e)
+		}
+	}
+	return r
+}
+
+// diffNames returns the names present only in b (added) and only in a (removed), in order.
+func diffNames(a, b []string) (added, removed []string) {
+	inA := make(map[string]struct{}, len(a))
+	for _, n := range a {
+		inA[n] = struct{}{}
+	}
+	inB := make(map[string]struct{}, len(b))
+	for _, n := range b {
+		inB[n] = struct{}{}
+		if _, ok := inA[n]; !ok {
+			added = append(added, n)
+		}
+	}
+	for _, n := range a {
+		if _, ok := inB[n]; !ok {
+			removed = append(removed, n)
+		}
+	}
+	return
+}
+
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
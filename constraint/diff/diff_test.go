@@ -0,0 +1,128 @@
+package diff_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/constraint/diff"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/profile"
+	"github.com/consensys/gnark/std/math/bits"
+	pprof "github.com/google/pprof/profile"
+	"github.com/stretchr/testify/require"
+)
+
+type before struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *before) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
+type after struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+	Z frontend.Variable `gnark:",public"`
+}
+
+func (c *after) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	bits.ToBinary(api, c.Z, bits.WithNbDigits(8))
+	return nil
+}
+
+func TestSystems(t *testing.T) {
+	assert := require.New(t)
+
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		a, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &before{})
+		assert.NoError(err)
+		b, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &after{})
+		assert.NoError(err)
+
+		r, err := diff.Systems(a, a)
+		assert.NoError(err)
+		assert.True(r.IsEmpty())
+		assert.Equal("no difference\n", r.String())
+
+		r, err = diff.Systems(a, b)
+		assert.NoError(err)
+		assert.False(r.IsEmpty())
+		assert.Equal([]string{"Z"}, r.PublicAdded)
+		assert.Empty(r.PublicRemoved)
+		assert.Equal(b.GetNbConstraints()-a.GetNbConstraints(), find(r.Summary, "constraints").Delta())
+
+		var nbHints int
+		for _, e := range r.Hints {
+			nbHints += e.Delta()
+		}
+		assert.Equal(1, nbHints, "the binary decomposition should add one hint call")
+		assert.Contains(r.String(), "public inputs added: Z")
+	}
+
+	_, err := diff.Systems(newSystem(t, ecc.BN254), newSystem(t, ecc.BLS12_381))
+	assert.Error(err)
+}
+
+func TestProfiles(t *testing.T) {
+	assert := require.New(t)
+
+	dir := t.TempDir()
+	pa, pb := filepath.Join(dir, "a.pprof"), filepath.Join(dir, "b.pprof")
+
+	p := profile.Start(profile.WithPath(pa))
+	_, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &before{})
+	assert.NoError(err)
+	p.Stop()
+
+	p = profile.Start(profile.WithPath(pb))
+	_, err = frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &after{})
+	assert.NoError(err)
+	p.Stop()
+
+	r := diff.Profiles(readProfile(t, pa), readProfile(t, pb))
+	assert.False(r.IsEmpty())
+
+	var found bool
+	for _, e := range r.Functions {
+		if strings.HasSuffix(e.Name, "ToBinary") {
+			found = true
+			assert.Equal(0, e.A)
+			assert.Equal(find(r.Summary, "constraints").Delta(), e.B)
+		}
+	}
+	assert.True(found, "bits.ToBinary should appear in the added functions")
+}
+
+func find(entries []diff.Entry, name string) diff.Entry {
+	for _, e := range entries {
+		if e.Name == name {
+			return e
+		}
+	}
+	return diff.Entry{Name: name}
+}
+
+func newSystem(t *testing.T, curve ecc.ID) constraint.ConstraintSystem {
+	cs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, &before{})
+	require.NoError(t, err)
+	return cs
+}
+
+func readProfile(t *testing.T, path string) *pprof.Profile {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	p, err := pprof.Parse(f)
+	require.NoError(t, err)
+	return p
+}
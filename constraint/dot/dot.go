@@ -0,0 +1,451 @@
+// Package dot exports the wire dependency graph of a compiled constraint system in the
+// Graphviz DOT format.
+//
+// Nodes are the circuit inputs, the instructions of the constraint system (constraints, hints,
+// lookups) and the commitments. An edge A -> B means that B uses wires solved by A (or input
+// wires, or wires committed by B); edges are labelled with the wire names.
+//
+//	ccs, _ := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &circuit)
+//	_ = dot.Write(os.Stdout, ccs, dot.WithFunction("Define"), dot.WithLevels())
+//
+// and then render with "dot -Tsvg circuit.dot > circuit.svg".
+//
+// Constraint systems only record source locations for assertions, and only when compiled with
+// -tags=debug; the file and function filters select instructions with matching debug information.
+package dot
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark/constraint"
+)
+
+// Option configures the export.
+type Option func(*Config) error
+
+// Config is the configuration of the export with the options applied.
+type Config struct {
+	// File and Function select the instructions whose debug stack has a frame in a file
+	// (resp. function) containing the given string. Empty strings match everything.
+	File, Function string
+
+	// Levels groups the instructions by solver level (see constraint.System.Levels).
+	Levels bool
+
+	// MaxEdgeLabels bounds the number of wire names in an edge label. Defaults to 3.
+	MaxEdgeLabels int
+}
+
+// WithFile selects the instructions created from a source file whose path contains file.
+// The instructions they depend on, or which depend on them, are drawn in gray.
+func WithFile(file string) Option {
+	return func(opt *Config) error {
+		opt.File = file
+		return nil
+	}
+}
+
+// WithFunction selects the instructions created by a function whose (fully qualified) name
+// contains function. The instructions they depend on, or which depend on them, are drawn in gray.
+func WithFunction(function string) Option {
+	return func(opt *Config) error {
+		opt.Function = function
+		return nil
+	}
+}
+
+// WithLevels groups the instructions in clusters, one per solver level. Instructions in
+// the same level don't depend on each other and are solved in parallel.
+func WithLevels() Option {
+	return func(opt *Config) error {
+		opt.Levels = true
+		return nil
+	}
+}
+
+// WithMaxEdgeLabels sets the maximum number of wire names printed on an edge.
+func WithMaxEdgeLabels(n int) Option {
+	return func(opt *Config) error {
+		if n < 1 {
+			return fmt.Errorf("max edge labels must be positive")
+		}
+		opt.MaxEdgeLabels = n
+		return nil
+	}
+}
+
+type node struct {
+	id, label, shape string
+	level            int
+}
+
+type edge struct {
+	from, to string
+	wires    []uint32
+	dashed   bool
+}
+
+type graph struct {
+	cs     constraint.ConstraintSystem
+	system *constraint.System
+	config Config
+
+	nodes    []*node
+	edges    []*edge
+	mEdges   map[[2]string]*edge
+	producer map[uint32]string // wire to the node solving it
+	matched  map[string]bool   // nodes selected by the filters
+}
+
+// Write writes the wire dependency graph of cs to w in the DOT format.
+func Write(w io.Writer, cs constraint.ConstraintSystem, opts ...Option) error {
+	config := Config{MaxEdgeLabels: 3}
+	for _, opt := range opts {
+		if err := opt(&config); err != nil {
+			return err
+		}
+	}
+
+	g := graph{
+		cs:       cs,
+		system:   cs.GetSystem(),
+		config:   config,
+		mEdges:   make(map[[2]string]*edge),
+		producer: make(map[uint32]string),
+		matched:  make(map[string]bool),
+	}
+	g.addInputs()
+	g.addInstructions()
+	g.addCommitments()
+
+	return g.write(w)
+}
+
+func (g *graph) filtered() bool {
+	return g.config.File != "" || g.config.Function != ""
+}
+
+func (g *graph) addNode(n *node, matched bool) {
+	g.nodes = append(g.nodes, n)
+	if matched || !g.filtered() {
+		g.matched[n.id] = true
+	}
+}
+
+func (g *graph) addEdge(from, to string, wire uint32, hasWire, dashed bool) {
+	if from == to {
+		return
+	}
+	e, ok := g.mEdges[[2]string{from, to}]
+	if !ok {
+		e = &edge{from: from, to: to, dashed: dashed}
+		g.mEdges[[2]string{from, to}] = e
+		g.edges = append(g.edges, e)
+	}
+	if hasWire {
+		for _, w := range e.wires {
+			if w == wire {
+				return
+			}
+		}
+		e.wires = append(e.wires, wire)
+	}
+}
+
+// use records that node id uses the given wire; if the wire isn't solved yet, id solves it.
+func (g *graph) use(id string, wire uint32) {
+	if g.system.Type == constraint.SystemR1CS && wire == 0 {
+		return
+	}
+	if p, ok := g.producer[wire]; ok {
+		g.addEdge(p, id, wire, true, false)
+		return
+	}
+	g.producer[wire] = id
+}
+
+func (g *graph) addInputs() {
+	nbPublic := g.system.GetNbPublicVariables()
+	nbInputs := nbPublic + g.system.GetNbSecretVariables()
+	for i := 0; i < nbInputs; i++ {
+		if g.system.Type == constraint.SystemR1CS && i == 0 {
+			// the constant wire is not a dependency
+			continue
+		}
+		name := g.cs.VariableToString(i)
+		label := name + " (secret)"
+		if i < nbPublic {
+			label = name + " (public)"
+		}
+		id := "w" + strconv.Itoa(i)
+		g.addNode(&node{id: id, label: label, shape: "ellipse", level: -1}, false)
+		g.producer[uint32(i)] = id
+	}
+}
+
+func (g *graph) addInstructions() {
+	level := make(map[int]int)
+	for l, instructions := range g.system.Levels {
+		for _, iID := range instructions {
+			level[iID] = l
+		}
+	}
+
+	var c constraint.SparseR1C
+	for iID := 0; iID < g.system.GetNbInstructions(); iID++ {
+		inst := g.cs.GetInstruction(iID)
+		pi := g.system.Instructions[iID]
+		blueprint := g.system.Blueprints[pi.BlueprintID]
+		id := "i" + strconv.Itoa(iID)
+
+		n := &node{id: id, shape: "box", level: -1}
+		if l, ok := level[iID]; ok {
+			n.level = l
+		}
+		var sbb strings.Builder
+		if bh, ok := blueprint.(constraint.BlueprintHint); ok {
+			var hm constraint.HintMapping
+			bh.DecompressHint(&hm, inst)
+			name, ok := g.system.MHintsDependencies[hm.HintID]
+			if !ok {
+				name = strconv.Itoa(int(hm.HintID))
+			}
+			sbb.WriteString("hint " + filepath.Base(name))
+			n.shape = "hexagon"
+		} else {
+			sbb.WriteString(blueprintName(blueprint))
+			if nb := blueprint.NbConstraints(); nb == 1 {
+				fmt.Fprintf(&sbb, " #%d", pi.ConstraintOffset)
+			} else if nb > 1 {
+				fmt.Fprintf(&sbb, " #%d..%d", pi.ConstraintOffset, int(pi.ConstraintOffset)+nb-1)
+			}
+			if _, ok := blueprint.(*constraint.BlueprintLookupHint); ok {
+				n.shape = "cylinder"
+			}
+		}
+		matched := false
+		for cID := int(pi.ConstraintOffset); cID < int(pi.ConstraintOffset)+blueprint.NbConstraints(); cID++ {
+			location, match := g.location(cID)
+			matched = matched || match
+			if location != "" && cID == int(pi.ConstraintOffset) {
+				sbb.WriteString("\n" + location)
+			}
+		}
+		n.label = sbb.String()
+		g.addNode(n, matched)
+
+		if bs, ok := blueprint.(constraint.BlueprintSparseR1C); ok {
+			// unused wires of sparse constraints are set to 0, skip them
+			bs.DecompressSparseR1C(&c, inst)
+			if c.QL != constraint.CoeffIdZero || c.QM != constraint.CoeffIdZero {
+				g.use(id, c.XA)
+			}
+			if c.QR != constraint.CoeffIdZero || c.QM != constraint.CoeffIdZero {
+				g.use(id, c.XB)
+			}
+			if c.QO != constraint.CoeffIdZero {
+				g.use(id, c.XC)
+			}
+			continue
+		}
+		blueprint.WireWalker(inst)(func(wire uint32) {
+			g.use(id, wire)
+		})
+	}
+}
+
+func (g *graph) addCommitments() {
+	// instruction containing a given constraint
+	instructionOf := func(cID int) string {
+		for iID := range g.system.Instructions {
+			pi := g.system.Instructions[iID]
+			nb := g.system.Blueprints[pi.BlueprintID].NbConstraints()
+			if cID >= int(pi.ConstraintOffset) && cID < int(pi.ConstraintOffset)+nb {
+				return "i" + strconv.Itoa(iID)
+			}
+		}
+		return ""
+	}
+
+	switch c := g.system.CommitmentInfo.(type) {
+	case constraint.Groth16Commitments:
+		for i := range c {
+			id := "c" + strconv.Itoa(i)
+			g.addNode(&node{id: id, label: fmt.Sprintf("commitment %d", i), shape: "doubleoctagon", level: -1}, false)
+			committed := append(c[i].PublicAndCommitmentCommitted[:len(c[i].PublicAndCommitmentCommitted):len(c[i].PublicAndCommitmentCommitted)], c[i].PrivateCommitted...)
+			for _, w := range committed {
+				if p, ok := g.producer[uint32(w)]; ok {
+					g.addEdge(p, id, uint32(w), true, false)
+				}
+			}
+			if p, ok := g.producer[uint32(c[i].CommitmentIndex)]; ok {
+				g.addEdge(id, p, uint32(c[i].CommitmentIndex), true, true)
+			}
+		}
+	case constraint.PlonkCommitments:
+		for i := range c {
+			id := "c" + strconv.Itoa(i)
+			g.addNode(&node{id: id, label: fmt.Sprintf("commitment %d", i), shape: "doubleoctagon", level: -1}, false)
+			for _, cID := range c[i].Committed {
+				if p := instructionOf(cID); p != "" {
+					g.addEdge(p, id, 0, false, false)
+				}
+			}
+			if p := instructionOf(c[i].CommitmentIndex); p != "" {
+				g.addEdge(id, p, 0, false, true)
+			}
+		}
+	}
+}
+
+// location returns the innermost location of the debug stack of the constraint, and
+// whether the stack matches the filters.
+func (g *graph) location(cID int) (string, bool) {
+	dID, ok := g.system.MDebug[cID]
+	if !ok || len(g.system.DebugInfo[dID].Stack) == 0 {
+		return "", false
+	}
+	st := &g.system.SymbolTable
+	stack := g.system.DebugInfo[dID].Stack
+	matched := false
+	if g.filtered() {
+		for _, lID := range stack {
+			f := st.Functions[st.Locations[lID].FunctionID]
+			if strings.Contains(f.Filename, g.config.File) && strings.Contains(f.Name, g.config.Function) {
+				matched = true
+				break
+			}
+		}
+	}
+	l := st.Locations[stack[0]]
+	f := st.Functions[l.FunctionID]
+	return fmt.Sprintf("%s:%d", filepath.Base(f.Filename), l.Line), matched
+}
+
+func (g *graph) write(w io.Writer) error {
+	// with filters, we keep the selected nodes and their direct neighbours.
+	included := make(map[string]bool, len(g.matched))
+	for id := range g.matched {
+		included[id] = true
+	}
+	for _, e := range g.edges {
+		if g.matched[e.from] || g.matched[e.to] {
+			included[e.from] = true
+			included[e.to] = true
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph circuit {")
+	fmt.Fprintln(bw, "\tnode [fontname=\"monospace\"];")
+	fmt.Fprintln(bw, "\tedge [fontname=\"monospace\", fontsize=10];")
+
+	writeNode := func(indent string, n *node) {
+		attrs := fmt.Sprintf("label=%s, shape=%s", strconv.Quote(n.label), n.shape)
+		if !g.matched[n.id] {
+			attrs += ", color=gray, fontcolor=gray"
+		}
+		fmt.Fprintf(bw, "%s%s [%s];\n", indent, n.id, attrs)
+	}
+
+	if g.config.Levels {
+		var inputs, others []*node
+		levels := make([][]*node, len(g.system.Levels))
+		for _, n := range g.nodes {
+			if !included[n.id] {
+				continue
+			}
+			switch {
+			case n.id[0] == 'w':
+				inputs = append(inputs, n)
+			case n.level >= 0:
+				levels[n.level] = append(levels[n.level], n)
+			default:
+				others = append(others, n)
+			}
+		}
+		if len(inputs) > 0 {
+			fmt.Fprintln(bw, "\tsubgraph cluster_inputs {")
+			fmt.Fprintln(bw, "\t\tlabel=\"inputs\";")
+			for _, n := range inputs {
+				writeNode("\t\t", n)
+			}
+			fmt.Fprintln(bw, "\t}")
+		}
+		for l := range levels {
+			if len(levels[l]) == 0 {
+				continue
+			}
+			fmt.Fprintf(bw, "\tsubgraph cluster_level%d {\n", l)
+			fmt.Fprintf(bw, "\t\tlabel=\"level %d\";\n", l)
+			for _, n := range levels[l] {
+				writeNode("\t\t", n)
+			}
+			fmt.Fprintln(bw, "\t}")
+		}
+		for _, n := range others {
+			writeNode("\t", n)
+		}
+	} else {
+		for _, n := range g.nodes {
+			if included[n.id] {
+				writeNode("\t", n)
+			}
+		}
+	}
+
+	for _, e := range g.edges {
+		if !included[e.from] || !included[e.to] || !(g.matched[e.from] || g.matched[e.to]) {
+			continue
+		}
+		var attrs []string
+		if len(e.wires) > 0 {
+			attrs = append(attrs, "label="+strconv.Quote(g.edgeLabel(e.wires)))
+		}
+		if e.dashed {
+			attrs = append(attrs, "style=dashed")
+		}
+		if len(attrs) == 0 {
+			fmt.Fprintf(bw, "\t%s -> %s;\n", e.from, e.to)
+		} else {
+			fmt.Fprintf(bw, "\t%s -> %s [%s];\n", e.from, e.to, strings.Join(attrs, ", "))
+		}
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+func (g *graph) edgeLabel(wires []uint32) string {
+	names := make([]string, 0, g.config.MaxEdgeLabels+1)
+	for i, w := range wires {
+		if i == g.config.MaxEdgeLabels {
+			names = append(names, fmt.Sprintf("+%d", len(wires)-i))
+			break
+		}
+		names = append(names, g.cs.VariableToString(int(w)))
+	}
+	return strings.Join(names, ", ")
+}
+
+func blueprintName(b constraint.Blueprint) string {
+	switch b.(type) {
+	case *constraint.BlueprintGenericR1C:
+		return "r1c"
+	case *constraint.BlueprintGenericSparseR1C:
+		return "scs"
+	case *constraint.BlueprintSparseR1CMul:
+		return "mul"
+	case *constraint.BlueprintSparseR1CAdd:
+		return "add"
+	case *constraint.BlueprintSparseR1CBool:
+		return "bool"
+	case *constraint.BlueprintLookupHint:
+		return "lookup"
+	}
+	return strings.TrimPrefix(fmt.Sprintf("%T", b), "*constraint.")
+}
@@ -0,0 +1,89 @@
+package dot_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/constraint/dot"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+type circuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *circuit) Define(api frontend.API) error {
+	x3 := api.Mul(c.X, c.X, c.X)
+	api.AssertIsEqual(api.Add(x3, c.X, 5), c.Y)
+	assertSmall(api, c.X)
+
+	committer, ok := api.(frontend.Committer)
+	if !ok {
+		return nil
+	}
+	cmt, err := committer.Commit(c.X)
+	if err != nil {
+		return err
+	}
+	api.AssertIsDifferent(cmt, 0)
+	return nil
+}
+
+func assertSmall(api frontend.API, v frontend.Variable) {
+	api.ToBinary(v, 8)
+}
+
+func TestWrite(t *testing.T) {
+	assert := require.New(t)
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &circuit{})
+		assert.NoError(err)
+
+		var buf bytes.Buffer
+		assert.NoError(dot.Write(&buf, ccs))
+		full := buf.String()
+		assert.True(strings.HasPrefix(full, "digraph circuit {"))
+		assert.Contains(full, `label="X (secret)"`)
+		assert.Contains(full, `label="Y (public)"`)
+		assert.Contains(full, "hint bits.nBits")
+		assert.Contains(full, "commitment 0")
+		assert.Contains(full, "style=dashed")
+		assert.NotContains(full, "color=gray")
+
+		// debug information is only recorded with -tags=debug, attach it to the first constraint.
+		attachDebugInfo(ccs, 0)
+		buf.Reset()
+		assert.NoError(dot.Write(&buf, ccs, dot.WithFunction("TestWrite")))
+		filtered := buf.String()
+		assert.Contains(filtered, "dot_test.go")
+		assert.Contains(filtered, "color=gray")
+		assert.Less(len(filtered), len(full))
+
+		buf.Reset()
+		assert.NoError(dot.Write(&buf, ccs, dot.WithFile("no_such_file.go")))
+		assert.Equal("digraph circuit {", strings.SplitN(buf.String(), "\n", 2)[0])
+		assert.NotContains(buf.String(), "->")
+
+		buf.Reset()
+		assert.NoError(dot.Write(&buf, ccs, dot.WithLevels()))
+		assert.Contains(buf.String(), "subgraph cluster_inputs")
+		assert.Contains(buf.String(), "subgraph cluster_level0")
+		assert.Equal(strings.Count(full, "->"), strings.Count(buf.String(), "->"))
+	}
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &circuit{})
+	assert.NoError(err)
+	assert.Error(dot.Write(&bytes.Buffer{}, ccs, dot.WithMaxEdgeLabels(0)))
+}
+
+// attachDebugInfo attaches debug information to constraint cID, as the builders do for assertions.
+func attachDebugInfo(ccs constraint.ConstraintSystem, cID int) {
+	ccs.AttachDebugInfo(ccs.NewDebugInfo("test"), []int{cID})
+}
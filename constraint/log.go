@@ -23,6 +23,7 @@ import (
 // (which is the case for variables that need to be resolved in the R1CS)
 type LogEntry struct {
 	Caller    string
+	Scope     string // path of the scope of the log (see System.PushScope)
 	Format    string
 	ToResolve []LinearExpression // TODO @gbotrel we could store here a struct with a flag that says if we expand or evaluate the expression
 	Stack     []int
@@ -0,0 +1,196 @@
+package constraint
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// Fork initializes system, a new system of the same type and field as parent, to record a
+// section of the circuit traced independently of parent (see Merge). The wires of parent can
+// be used in system, and the wires created in system are numbered after them. The scope of
+// system starts at the current scope of parent.
+func (system *System) Fork(parent *System) {
+	system.Public = parent.Public[:len(parent.Public):len(parent.Public)]
+	system.Secret = parent.Secret[:len(parent.Secret):len(parent.Secret)]
+	system.NbInternalVariables = parent.NbInternalVariables
+	if scope := parent.CurrentScope(); scope != "" {
+		system.scopeStack = []string{scope}
+		system.enterScope(scope)
+	}
+}
+
+// Merge appends to dst the instructions of src, a system forked from dst (see System.Fork)
+// when dst had base wires, as if they were added to dst directly. The wires of src below
+// base are the wires of dst; the wires created in src are shifted by offset, which is
+// returned. The coefficients, the hints, the logs, the debug information, the wire labels and
+// the scopes of src are merged in dst.
+//
+// src must only contain R1C, sparse R1C and hint instructions, and no commitment nor GKR: the
+// calldata of the other blueprints is opaque, and blueprints such as the lookup tables of
+// BlueprintLookupHint hold wires in their own state, so their instructions can't be remapped.
+// Merge returns an error for them.
+func Merge(dst, src ConstraintSystem, base int) (offset int, err error) {
+	d, s := dst.GetSystem(), src.GetSystem()
+	if s.Type != d.Type {
+		return 0, errors.New("can't merge systems of different types")
+	}
+	if s.CommitmentInfo != nil && len(s.CommitmentInfo.CommitmentIndexes()) != 0 {
+		return 0, errors.New("can't merge a system with commitments")
+	}
+	if s.GkrInfo.Is() {
+		return 0, errors.New("can't merge a system with GKR")
+	}
+
+	// map the blueprints of src to the blueprints of dst
+	blueprints := make([]BlueprintID, len(s.Blueprints))
+	for i := range blueprints {
+		blueprints[i] = math.MaxUint32
+	}
+	for _, pi := range s.Instructions {
+		bID := pi.BlueprintID
+		if blueprints[bID] != math.MaxUint32 {
+			continue
+		}
+		switch s.Blueprints[bID].(type) {
+		case BlueprintR1C, BlueprintSparseR1C, BlueprintHint:
+		default:
+			return 0, fmt.Errorf("can't merge instructions of blueprint %T", s.Blueprints[bID])
+		}
+		for j, b := range d.Blueprints {
+			if reflect.DeepEqual(b, s.Blueprints[bID]) {
+				blueprints[bID] = BlueprintID(j)
+				break
+			}
+		}
+		if blueprints[bID] == math.MaxUint32 {
+			return 0, fmt.Errorf("blueprint %T isn't registered in the destination system", s.Blueprints[bID])
+		}
+	}
+	for id, name := range s.MHintsDependencies {
+		if registered, ok := d.MHintsDependencies[id]; ok && registered != name {
+			return 0, fmt.Errorf("hint dependency registration failed; %s previously register with same UUID as %s", name, registered)
+		}
+	}
+	for label := range s.WireLabels {
+		if _, ok := d.WireLabels[label]; ok {
+			return 0, fmt.Errorf("wire label %q is already used", label)
+		}
+	}
+
+	offset = d.nbWires() - base
+	shift := func(vID uint32) uint32 {
+		if vID == math.MaxUint32 || int(vID) < base {
+			return vID // constant or wire of dst
+		}
+		return vID + uint32(offset)
+	}
+	// the coefficients are added in the order of src, as they would have been in dst
+	coeffs := make([]uint32, src.GetNbCoefficients())
+	for i := range coeffs {
+		coeffs[i] = dst.AddCoeff(src.GetCoefficient(i))
+	}
+	remap := func(l LinearExpression) LinearExpression {
+		r := make(LinearExpression, len(l))
+		for i, t := range l {
+			r[i] = Term{CID: coeffs[t.CID], VID: shift(t.VID)}
+		}
+		return r
+	}
+	for _, scope := range s.Scopes {
+		d.scopeID(scope)
+	}
+	for id, name := range s.MHintsDependencies {
+		d.MHintsDependencies[id] = name
+	}
+
+	cOffset := d.NbConstraints
+	calldata := getBuffer()
+	defer putBuffer(calldata)
+	var (
+		r1c  R1C
+		sr1c SparseR1C
+		hint HintMapping
+	)
+	for _, pi := range s.Instructions {
+		inst := pi.Unpack(s)
+		bID := blueprints[pi.BlueprintID]
+
+		// the wires created in src before the instruction
+		for wireOffset := int(shift(pi.WireOffset)); d.nbWires() < wireOffset; {
+			dst.AddInternalVariable()
+		}
+		if s.Blueprints[pi.BlueprintID].NbConstraints() > 0 {
+			d.enterScope(s.ConstraintScope(int(pi.ConstraintOffset)))
+		}
+
+		*calldata = (*calldata)[:0]
+		switch b := s.Blueprints[pi.BlueprintID].(type) {
+		case BlueprintR1C:
+			b.DecompressR1C(&r1c, inst)
+			r1c.L, r1c.R, r1c.O = remap(r1c.L), remap(r1c.R), remap(r1c.O)
+			d.Blueprints[bID].(BlueprintR1C).CompressR1C(&r1c, calldata)
+		case BlueprintSparseR1C:
+			b.DecompressSparseR1C(&sr1c, inst)
+			sr1c.XA, sr1c.XB, sr1c.XC = shift(sr1c.XA), shift(sr1c.XB), shift(sr1c.XC)
+			sr1c.QL, sr1c.QR, sr1c.QO = coeffs[sr1c.QL], coeffs[sr1c.QR], coeffs[sr1c.QO]
+			sr1c.QM, sr1c.QC = coeffs[sr1c.QM], coeffs[sr1c.QC]
+			d.Blueprints[bID].(BlueprintSparseR1C).CompressSparseR1C(&sr1c, calldata)
+		case BlueprintHint:
+			b.DecompressHint(&hint, inst)
+			for i := range hint.Inputs {
+				hint.Inputs[i] = remap(hint.Inputs[i])
+			}
+			hint.OutputRange.Start, hint.OutputRange.End = shift(hint.OutputRange.Start), shift(hint.OutputRange.End)
+			d.Blueprints[bID].(BlueprintHint).CompressHint(hint, calldata)
+		}
+		dst.AddInstruction(bID, *calldata)
+	}
+	for nbWires := s.nbWires() + offset; d.nbWires() < nbWires; {
+		dst.AddInternalVariable()
+	}
+	d.enterScope(d.CurrentScope())
+
+	if len(s.WireLabels) != 0 && d.WireLabels == nil {
+		d.WireLabels = make(map[string]int, len(s.WireLabels))
+	}
+	for label, wireID := range s.WireLabels {
+		d.WireLabels[label] = int(shift(uint32(wireID)))
+	}
+	locations := d.SymbolTable.Import(&s.SymbolTable)
+	remapLog := func(l LogEntry) LogEntry {
+		if l.ToResolve != nil {
+			toResolve := make([]LinearExpression, len(l.ToResolve))
+			for i := range l.ToResolve {
+				toResolve[i] = remap(l.ToResolve[i])
+			}
+			l.ToResolve = toResolve
+		}
+		if l.Stack != nil {
+			stack := make([]int, len(l.Stack))
+			for i, id := range l.Stack {
+				stack[i] = locations[id]
+			}
+			l.Stack = stack
+		}
+		return l
+	}
+	for _, l := range s.Logs {
+		d.Logs = append(d.Logs, remapLog(l))
+	}
+	dOffset := len(d.DebugInfo)
+	for _, l := range s.DebugInfo {
+		d.DebugInfo = append(d.DebugInfo, remapLog(l))
+	}
+	for cID, id := range s.MDebug {
+		d.MDebug[cOffset+cID] = dOffset + id
+	}
+
+	return offset, nil
+}
+
+// nbWires returns the total number of wires of the system.
+func (system *System) nbWires() int {
+	return system.GetNbPublicVariables() + system.GetNbSecretVariables() + system.NbInternalVariables
+}
@@ -0,0 +1,123 @@
+package constraint_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	cs "github.com/consensys/gnark/constraint/bn254"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+type scopeCircuit struct {
+	X, Y frontend.Variable
+}
+
+func (c *scopeCircuit) Define(api frontend.API) error {
+	x := c.X
+	for _, round := range []string{"round 0", "round 1"} {
+		closeScope := api.Compiler().(frontend.Scoper).Scope("hash/" + round)
+		x = api.Mul(x, x)
+		api.AssertIsDifferent(x, 0)
+		closeScope()
+	}
+
+	defer api.Compiler().(frontend.Scoper).Scope("check")()
+	if labeler, ok := api.Compiler().(frontend.Labeler); ok {
+		var err error
+		if x, err = labeler.Label(x, "x4"); err != nil {
+			return err
+		}
+	}
+	func() {
+		defer api.Compiler().(frontend.Scoper).Scope("final")()
+		api.Println("x⁴ =", x)
+		api.AssertIsEqual(x, c.Y)
+	}()
+	return nil
+}
+
+func TestScopes(t *testing.T) {
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		assert := require.New(t)
+
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &scopeCircuit{})
+		assert.NoError(err)
+		system := ccs.GetSystem()
+		assert.Equal([]string{"hash/round 0", "hash/round 1", "check", "check/final"}, system.Scopes)
+		assert.Equal("", ccs.CurrentScope())
+
+		scopes := make(map[string]int)
+		for cID := 0; cID < ccs.GetNbConstraints(); cID++ {
+			scopes[ccs.ConstraintScope(cID)]++
+		}
+		assert.NotContains(scopes, "", "all the constraints are scoped")
+		assert.Equal(scopes["hash/round 0"], scopes["hash/round 1"])
+		assert.Contains(scopes, "check/final")
+
+		_, ok := ccs.GetLabeledWire("check/x4")
+		assert.True(ok)
+		assert.Len(system.Logs, 1)
+		assert.Equal("check/final", system.Logs[0].Scope)
+
+		// the scopes are serialized
+		var buf bytes.Buffer
+		_, err = ccs.WriteTo(&buf)
+		assert.NoError(err)
+		var read cs.R1CS
+		_, err = read.ReadFrom(&buf)
+		assert.NoError(err)
+		assert.Equal(system.Scopes, read.Scopes)
+		assert.Equal(system.ScopeRanges, read.ScopeRanges)
+
+		// the solver reports the scope of the unsatisfied constraint
+		w, err := frontend.NewWitness(&scopeCircuit{X: 2, Y: 15}, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		_, err = read.Solve(w)
+		var unsatisfied *cs.UnsatisfiedConstraintError
+		assert.True(errors.As(err, &unsatisfied), "unexpected error %v", err)
+		assert.Equal("check/final", unsatisfied.Scope)
+		assert.Contains(err.Error(), "in check/final")
+
+		w, err = frontend.NewWitness(&scopeCircuit{X: 0, Y: 0}, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		_, err = read.Solve(w)
+		assert.True(errors.As(err, &unsatisfied), "unexpected error %v", err)
+		assert.Equal("hash/round 0", unsatisfied.Scope)
+	}
+}
+
+func TestScopeRanges(t *testing.T) {
+	assert := require.New(t)
+
+	system := constraint.NewSystem(ecc.BN254.ScalarField(), 0, constraint.SystemR1CS)
+	addConstraint := func() {
+		system.NbConstraints++
+	}
+
+	addConstraint()
+	system.PushScope("a")
+	// empty scopes don't create ranges
+	system.PushScope("b")
+	system.PopScope()
+	addConstraint()
+	system.PushScope("b")
+	addConstraint()
+	addConstraint()
+	system.PopScope()
+	addConstraint()
+	system.PopScope()
+	addConstraint()
+
+	assert.Equal([]string{"a", "a/b"}, system.Scopes)
+	assert.Equal([]constraint.ScopeRange{{Start: 1, Scope: 0}, {Start: 2, Scope: 1}, {Start: 4, Scope: 0}, {Start: 5, Scope: -1}}, system.ScopeRanges)
+	for cID, scope := range []string{"", "a", "a/b", "a/b", "a", ""} {
+		assert.Equal(scope, system.ConstraintScope(cID), "constraint %d", cID)
+	}
+	assert.Panics(system.PopScope)
+}
@@ -85,11 +85,14 @@ type Hint func(field *big.Int, inputs []*big.Int, outputs []*big.Int) error
 
 // GetHintID is a reference function for computing the hint ID based on a function name
 func GetHintID(fn Hint) HintID {
-	hf := fnv.New32a()
-	name := GetHintName(fn)
-
 	// TODO relying on name to derive UUID is risky; if fn is an anonymous func, wil be package.glob..funcN
 	// and if new anonymous functions are added in the package, N may change, so will UUID.
+	return GetHintIDFromName(GetHintName(fn))
+}
+
+// GetHintIDFromName returns the ID of the hint function with the given name (see GetHintName).
+func GetHintIDFromName(name string) HintID {
+	hf := fnv.New32a()
 	hf.Write([]byte(name)) // #nosec G104 -- does not err
 
 	return HintID(hf.Sum32())
@@ -0,0 +1,492 @@
+package solver
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// Hints can be executed by an external process (for example to call code which isn't written
+// in Go), which reads requests on its input and writes responses on its output. The solver
+// sends the hint calls in batches: concurrent calls are grouped in a single round-trip.
+//
+// All the integers of the protocol are big-endian. A batch request is
+//
+//	nbCalls uint32
+//	nbCalls times:
+//	    hintID    uint32
+//	    nbOutputs uint32
+//	    modulus   bytes
+//	    nbInputs  uint32
+//	    nbInputs times: input bytes
+//
+// where bytes is a uint32 length followed by the big-endian encoding of a non-negative
+// integer. The response has one result per call, in the same order:
+//
+//	nbCalls uint32
+//	nbCalls times:
+//	    status uint8
+//	    status == 0: nbOutputs uint32, nbOutputs times: output bytes
+//	    status == 1: message bytes (the error message of the hint)
+//
+// ServeHints implements the worker side of the protocol in Go.
+
+const (
+	hintStatusOK    = 0
+	hintStatusError = 1
+
+	// bounds on the decoded lengths, against corrupted streams
+	maxHintBytes = 1 << 16
+	maxHintCount = 1 << 24
+)
+
+// HintProcess is a connection to a process executing hints (see WithHintProcess). It is safe
+// for concurrent use.
+type HintProcess struct {
+	r      *bufio.Reader
+	w      *bufio.Writer
+	closer io.Closer
+	cmd    *exec.Cmd
+
+	timeout   time.Duration
+	batchSize int
+
+	calls    chan *hintCall
+	done     chan struct{}
+	stopOnce sync.Once
+	err      error // set before done is closed
+	connOnce sync.Once
+}
+
+type hintCall struct {
+	id      HintID
+	field   *big.Int
+	inputs  []*big.Int
+	outputs []*big.Int
+	err     error
+	done    chan struct{}
+}
+
+// HintProcessOption configures a HintProcess.
+type HintProcessOption func(*HintProcess)
+
+// WithHintTimeout sets the maximum duration of a round-trip with the hint process. When it
+// expires, the connection is closed, a process started by StartHintProcess is killed, and the
+// pending and subsequent hint calls fail. Defaults
+// to one minute; 0 disables the timeout.
+func WithHintTimeout(d time.Duration) HintProcessOption {
+	return func(p *HintProcess) {
+		p.timeout = d
+	}
+}
+
+// WithHintBatchSize sets the maximum number of hint calls sent in a round-trip. Defaults
+// to 1024.
+func WithHintBatchSize(n int) HintProcessOption {
+	return func(p *HintProcess) {
+		if n > 0 {
+			p.batchSize = n
+		}
+	}
+}
+
+// NewHintProcess returns a HintProcess exchanging with a worker over conn, for example a
+// Unix socket connection. Close closes conn.
+func NewHintProcess(conn io.ReadWriteCloser, opts ...HintProcessOption) *HintProcess {
+	return newHintProcess(conn, conn, conn, opts)
+}
+
+// StartHintProcess starts cmd and returns a HintProcess exchanging with it over its standard
+// input and output. Close closes its standard input and waits for it to exit.
+func StartHintProcess(cmd *exec.Cmd, opts ...HintProcessOption) (*HintProcess, error) {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	p := newHintProcess(stdout, stdin, pipesCloser{stdin, stdout}, opts)
+	p.cmd = cmd
+	return p, nil
+}
+
+// pipesCloser closes the standard input and output of a hint process: closing the output
+// unblocks a pending read of the response.
+type pipesCloser struct {
+	stdin, stdout io.Closer
+}
+
+func (c pipesCloser) Close() error {
+	err := c.stdin.Close()
+	if oerr := c.stdout.Close(); err == nil {
+		err = oerr
+	}
+	return err
+}
+
+func newHintProcess(r io.Reader, w io.Writer, closer io.Closer, opts []HintProcessOption) *HintProcess {
+	p := &HintProcess{
+		r:         bufio.NewReader(r),
+		w:         bufio.NewWriter(w),
+		closer:    closer,
+		timeout:   time.Minute,
+		batchSize: 1024,
+		calls:     make(chan *hintCall),
+		done:      make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	go p.loop()
+	return p
+}
+
+// Close closes the connection to the worker. Pending hint calls fail.
+func (p *HintProcess) Close() error {
+	p.stop(errors.New("hint process closed"))
+	err := p.closeConn()
+	if p.cmd != nil {
+		if werr := p.cmd.Wait(); err == nil {
+			err = werr
+		}
+	}
+	return err
+}
+
+// Hint returns a hint function forwarding the calls to the process, under the given ID.
+func (p *HintProcess) Hint(id HintID) Hint {
+	return func(field *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+		c := &hintCall{id: id, field: field, inputs: inputs, outputs: outputs, done: make(chan struct{})}
+		select {
+		case p.calls <- c:
+		case <-p.done:
+			return p.err
+		}
+		<-c.done
+		return c.err
+	}
+}
+
+// stop records the first error and stops the loop.
+func (p *HintProcess) stop(err error) {
+	p.stopOnce.Do(func() {
+		p.err = err
+		close(p.done)
+	})
+}
+
+// closeConn closes the connection once; the round-trips in progress fail.
+func (p *HintProcess) closeConn() error {
+	var err error
+	p.connOnce.Do(func() {
+		err = p.closer.Close()
+	})
+	return err
+}
+
+func (p *HintProcess) loop() {
+	batch := make([]*hintCall, 0, p.batchSize)
+	for {
+		batch = batch[:0]
+		select {
+		case c := <-p.calls:
+			batch = append(batch, c)
+		case <-p.done:
+			return
+		}
+		// the calls made while the previous round-trip was in progress are sent together
+	gather:
+		for len(batch) < p.batchSize {
+			select {
+			case c := <-p.calls:
+				batch = append(batch, c)
+			default:
+				break gather
+			}
+		}
+
+		err := p.roundTripWithTimeout(batch)
+		if err != nil {
+			p.stop(err)
+			p.closeConn()
+		}
+		for _, c := range batch {
+			if err != nil {
+				c.err = err
+			}
+			close(c.done)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (p *HintProcess) roundTripWithTimeout(batch []*hintCall) error {
+	if p.timeout == 0 {
+		return p.roundTrip(batch)
+	}
+	res := make(chan error, 1)
+	go func() {
+		res <- p.roundTrip(batch)
+	}()
+	timer := time.NewTimer(p.timeout)
+	defer timer.Stop()
+	select {
+	case err := <-res:
+		return err
+	case <-timer.C:
+		// closing the connection unblocks the round-trip; a hung process wouldn't exit and
+		// Close would wait for it forever
+		p.closeConn()
+		if p.cmd != nil {
+			p.cmd.Process.Kill()
+		}
+		<-res
+		return fmt.Errorf("hint process: no response after %s", p.timeout)
+	case <-p.done:
+		<-res
+		return p.err
+	}
+}
+
+func (p *HintProcess) roundTrip(batch []*hintCall) error {
+	if err := writeUint32(p.w, len(batch)); err != nil {
+		return err
+	}
+	for _, c := range batch {
+		if err := writeUint32(p.w, int(c.id)); err != nil {
+			return err
+		}
+		if err := writeUint32(p.w, len(c.outputs)); err != nil {
+			return err
+		}
+		if err := writeHintInt(p.w, c.field); err != nil {
+			return err
+		}
+		if err := writeHintInts(p.w, c.inputs); err != nil {
+			return err
+		}
+	}
+	if err := p.w.Flush(); err != nil {
+		return fmt.Errorf("hint process: %w", err)
+	}
+
+	n, err := readUint32(p.r)
+	if err != nil {
+		return fmt.Errorf("hint process: %w", err)
+	}
+	if n != len(batch) {
+		return fmt.Errorf("hint process: got %d results for %d calls", n, len(batch))
+	}
+	for _, c := range batch {
+		status, err := p.r.ReadByte()
+		if err != nil {
+			return fmt.Errorf("hint process: %w", err)
+		}
+		switch status {
+		case hintStatusOK:
+			outputs, err := readHintInts(p.r)
+			if err != nil {
+				return fmt.Errorf("hint process: %w", err)
+			}
+			if len(outputs) != len(c.outputs) {
+				return fmt.Errorf("hint process: hint %d returned %d outputs, expected %d", c.id, len(outputs), len(c.outputs))
+			}
+			for i := range outputs {
+				c.outputs[i].Set(outputs[i])
+			}
+		case hintStatusError:
+			msg, err := readHintBytes(p.r)
+			if err != nil {
+				return fmt.Errorf("hint process: %w", err)
+			}
+			c.err = fmt.Errorf("hint %d: %s", c.id, msg)
+		default:
+			return fmt.Errorf("hint process: invalid status %d", status)
+		}
+	}
+	return nil
+}
+
+// WithHintProcess is a solver option that executes the hints with the given IDs in the
+// process p, instead of the registered hint functions. The IDs of hints which are not written
+// in Go can be derived from a name with GetHintIDFromName.
+func WithHintProcess(p *HintProcess, ids ...HintID) Option {
+	return func(opt *Config) error {
+		for _, id := range ids {
+			opt.HintFunctions[id] = p.Hint(id)
+		}
+		return nil
+	}
+}
+
+// ServeHints serves the hint calls read from r with the given hint functions, writing the
+// results to w, until r is exhausted. It is a reference implementation of a hint process
+// (see HintProcess).
+func ServeHints(r io.Reader, w io.Writer, hints map[HintID]Hint) error {
+	br, bw := bufio.NewReader(r), bufio.NewWriter(w)
+	for {
+		n, err := readUint32(br)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := writeUint32(bw, n); err != nil {
+			return err
+		}
+		for i := 0; i < n; i++ {
+			id, err := readUint32(br)
+			if err != nil {
+				return unexpectedEOF(err)
+			}
+			nbOutputs, err := readUint32(br)
+			if err != nil {
+				return unexpectedEOF(err)
+			}
+			if nbOutputs > maxHintCount {
+				return fmt.Errorf("invalid count %d", nbOutputs)
+			}
+			field, err := readHintInt(br)
+			if err != nil {
+				return err
+			}
+			inputs, err := readHintInts(br)
+			if err != nil {
+				return err
+			}
+			outputs := make([]*big.Int, nbOutputs)
+			for j := range outputs {
+				outputs[j] = new(big.Int)
+			}
+
+			f, ok := hints[HintID(id)]
+			if !ok {
+				err = errors.New("missing hint function")
+			} else {
+				err = f(field, inputs, outputs)
+			}
+			if err != nil {
+				if err := bw.WriteByte(hintStatusError); err != nil {
+					return err
+				}
+				if err := writeHintBytes(bw, []byte(err.Error())); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := bw.WriteByte(hintStatusOK); err != nil {
+				return err
+			}
+			if err := writeHintInts(bw, outputs); err != nil {
+				return err
+			}
+		}
+		if err := bw.Flush(); err != nil {
+			return err
+		}
+	}
+}
+
+func writeUint32(w io.Writer, v int) error {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], uint32(v))
+	_, err := w.Write(buf[:])
+	return err
+}
+
+func readUint32(r io.Reader) (int, error) {
+	var buf [4]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, err
+	}
+	return int(binary.BigEndian.Uint32(buf[:])), nil
+}
+
+func writeHintBytes(w io.Writer, b []byte) error {
+	if err := writeUint32(w, len(b)); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+func readHintBytes(r io.Reader) ([]byte, error) {
+	n, err := readUint32(r)
+	if err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	if n > maxHintBytes {
+		return nil, fmt.Errorf("invalid length %d", n)
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	return b, nil
+}
+
+func writeHintInt(w io.Writer, v *big.Int) error {
+	if v.Sign() < 0 {
+		return errors.New("negative hint value")
+	}
+	return writeHintBytes(w, v.Bytes())
+}
+
+func readHintInt(r io.Reader) (*big.Int, error) {
+	b, err := readHintBytes(r)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func writeHintInts(w io.Writer, values []*big.Int) error {
+	if err := writeUint32(w, len(values)); err != nil {
+		return err
+	}
+	for _, v := range values {
+		if err := writeHintInt(w, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readHintInts(r io.Reader) ([]*big.Int, error) {
+	n, err := readUint32(r)
+	if err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	if n > maxHintCount {
+		return nil, fmt.Errorf("invalid count %d", n)
+	}
+	values := make([]*big.Int, n)
+	for i := range values {
+		if values[i], err = readHintInt(r); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+	}
+	return values, nil
+}
+
+// unexpectedEOF reports the end of the stream in the middle of a message as an error.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,228 @@
+package solver_test
+
+import (
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/stretchr/testify/require"
+)
+
+// when set, the test binary runs as a hint process (see TestStartHintProcess), which never
+// answers if it is set to "hang"
+const hintWorkerEnv = "GNARK_TEST_HINT_WORKER"
+
+func TestMain(m *testing.M) {
+	switch os.Getenv(hintWorkerEnv) {
+	case "":
+	case "hang":
+		go io.Copy(io.Discard, os.Stdin)
+		time.Sleep(time.Hour)
+		os.Exit(0)
+	default:
+		if err := solver.ServeHints(os.Stdin, os.Stdout, workerHints()); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func workerHints() map[solver.HintID]solver.Hint {
+	return map[solver.HintID]solver.Hint{
+		solver.GetHintID(cube):        cube,
+		solver.GetHintID(failingCube): failingCube,
+	}
+}
+
+type cubesCircuit struct {
+	X [16]frontend.Variable
+}
+
+func (c *cubesCircuit) Define(api frontend.API) error {
+	for i := range c.X {
+		res, err := api.Compiler().NewHint(cube, 1, c.X[i])
+		if err != nil {
+			return err
+		}
+		api.AssertIsEqual(res[0], api.Mul(c.X[i], c.X[i], c.X[i]))
+	}
+	return nil
+}
+
+func cubesWitness() *cubesCircuit {
+	var assignment cubesCircuit
+	for i := range assignment.X {
+		assignment.X[i] = i + 2
+	}
+	return &assignment
+}
+
+// countingConn counts the writes of the worker, one per batch.
+type countingConn struct {
+	net.Conn
+	writes atomic.Int32
+}
+
+func (c *countingConn) Write(b []byte) (int, error) {
+	c.writes.Add(1)
+	return c.Conn.Write(b)
+}
+
+func serve(t *testing.T, hints map[solver.HintID]solver.Hint) (net.Conn, *countingConn) {
+	client, server := net.Pipe()
+	worker := &countingConn{Conn: server}
+	go func() {
+		defer server.Close()
+		if err := solver.ServeHints(worker, worker, hints); err != nil && !errors.Is(err, io.ErrClosedPipe) {
+			t.Error(err)
+		}
+	}()
+	return client, worker
+}
+
+func TestHintProcess(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &cubesCircuit{})
+	assert.NoError(err)
+	w, err := frontend.NewWitness(cubesWitness(), ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	conn, _ := serve(t, workerHints())
+	p := solver.NewHintProcess(conn)
+	defer p.Close()
+
+	// cube isn't registered: the solver fails without the hint process
+	_, err = ccs.Solve(w)
+	assert.Error(err)
+
+	_, err = ccs.Solve(w, solver.WithHintProcess(p, solver.GetHintID(cube)))
+	assert.NoError(err)
+
+	// errors of the hints are returned to the solver
+	_, err = ccs.Solve(w, solver.OverrideHint(solver.GetHintID(cube), p.Hint(solver.GetHintID(failingCube))))
+	assert.ErrorContains(err, "the hint must not be called")
+
+	// the connection is still usable
+	_, err = ccs.Solve(w, solver.WithHintProcess(p, solver.GetHintID(cube)))
+	assert.NoError(err)
+}
+
+func TestHintProcessBatching(t *testing.T) {
+	assert := require.New(t)
+
+	// the first call is slow, the other calls are queued meanwhile
+	var first sync.Once
+	slowCube := func(field *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+		first.Do(func() { time.Sleep(50 * time.Millisecond) })
+		return cube(field, inputs, outputs)
+	}
+	conn, worker := serve(t, map[solver.HintID]solver.Hint{solver.GetHintID(cube): slowCube})
+	p := solver.NewHintProcess(conn)
+	defer p.Close()
+
+	const nbCalls = 100
+	hint := p.Hint(solver.GetHintID(cube))
+	var wg sync.WaitGroup
+	errs := make([]error, nbCalls)
+	outputs := make([]*big.Int, nbCalls)
+	for i := 0; i < nbCalls; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			outputs[i] = new(big.Int)
+			errs[i] = hint(ecc.BN254.ScalarField(), []*big.Int{big.NewInt(int64(i))}, outputs[i:i+1])
+		}(i)
+	}
+	wg.Wait()
+	for i := 0; i < nbCalls; i++ {
+		assert.NoError(errs[i])
+		assert.Equal(int64(i*i*i), outputs[i].Int64())
+	}
+	assert.Less(int(worker.writes.Load()), nbCalls/2, "hint calls should be batched")
+
+	// missing hint in the worker
+	err := p.Hint(solver.GetHintIDFromName("missing"))(ecc.BN254.ScalarField(), nil, []*big.Int{new(big.Int)})
+	assert.ErrorContains(err, "missing hint function")
+}
+
+func TestHintProcessTimeout(t *testing.T) {
+	assert := require.New(t)
+
+	// the worker never answers
+	client, server := net.Pipe()
+	go io.Copy(io.Discard, server)
+	defer server.Close()
+
+	p := solver.NewHintProcess(client, solver.WithHintTimeout(50*time.Millisecond))
+	hint := p.Hint(solver.GetHintID(cube))
+	err := hint(ecc.BN254.ScalarField(), []*big.Int{big.NewInt(2)}, []*big.Int{new(big.Int)})
+	assert.ErrorContains(err, "no response")
+
+	// the process is unusable after a timeout
+	err = hint(ecc.BN254.ScalarField(), []*big.Int{big.NewInt(2)}, []*big.Int{new(big.Int)})
+	assert.Error(err)
+	p.Close()
+}
+
+func TestStartHintProcess(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &cubesCircuit{})
+	assert.NoError(err)
+	w, err := frontend.NewWitness(cubesWitness(), ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), hintWorkerEnv+"=1")
+	p, err := solver.StartHintProcess(cmd)
+	assert.NoError(err)
+
+	_, err = ccs.Solve(w, solver.WithHintProcess(p, solver.GetHintID(cube)))
+	assert.NoError(err)
+	assert.NoError(p.Close())
+}
+
+func TestStartHintProcessTimeout(t *testing.T) {
+	assert := require.New(t)
+
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), hintWorkerEnv+"=hang")
+	p, err := solver.StartHintProcess(cmd, solver.WithHintTimeout(50*time.Millisecond))
+	assert.NoError(err)
+
+	res := make(chan error, 1)
+	go func() {
+		res <- p.Hint(solver.GetHintID(cube))(ecc.BN254.ScalarField(), []*big.Int{big.NewInt(2)}, []*big.Int{new(big.Int)})
+	}()
+	select {
+	case err := <-res:
+		assert.ErrorContains(err, "no response")
+	case <-time.After(10 * time.Second):
+		t.Fatal("the hint call didn't time out")
+	}
+
+	// the worker was killed, Close doesn't wait for it
+	closed := make(chan struct{})
+	go func() {
+		p.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Close didn't return")
+	}
+}
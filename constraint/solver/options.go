@@ -1,6 +1,10 @@
 package solver
 
 import (
+	"errors"
+	"fmt"
+	"io"
+
 	"github.com/consensys/gnark/logger"
 	"github.com/rs/zerolog"
 )
@@ -14,6 +18,15 @@ type Option func(*Config) error
 type Config struct {
 	HintFunctions map[HintID]Hint // defaults to all built-in hint functions
 	Logger        zerolog.Logger  // defaults to gnark.Logger
+
+	// values of internal wires provided by the caller, by wire ID and by label
+	AssignedWires  map[int]any
+	AssignedLabels map[string]any
+
+	// snapshots of the solver state (see WithSnapshots and WithResume)
+	SnapshotInterval int
+	SnapshotSave     func(Snapshot) error
+	Resume           io.Reader
 }
 
 // WithHints is a solver option that specifies additional hint functions to be used
@@ -43,6 +56,67 @@ func OverrideHint(id HintID, f Hint) Option {
 	}
 }
 
+// WithAssignedWires is a solver option that provides the values of internal wires, indexed
+// by wire ID. Values must be convertible to field elements (see utils.FromInterface).
+//
+// The solver doesn't compute the assigned wires: the hints whose outputs are all assigned are
+// not called, and the assigned values are checked against the constraints instead. This
+// allows re-using the results of expensive sub-computations from a previous run.
+func WithAssignedWires(values map[int]any) Option {
+	return func(opt *Config) error {
+		if opt.AssignedWires == nil {
+			opt.AssignedWires = make(map[int]any, len(values))
+		}
+		for id, v := range values {
+			opt.AssignedWires[id] = v
+		}
+		return nil
+	}
+}
+
+// WithAssignedLabels is a solver option that provides the values of internal wires, indexed
+// by the labels given to the wires at compile time (see frontend.Labeler). It behaves like
+// WithAssignedWires.
+func WithAssignedLabels(values map[string]any) Option {
+	return func(opt *Config) error {
+		if opt.AssignedLabels == nil {
+			opt.AssignedLabels = make(map[string]any, len(values))
+		}
+		for label, v := range values {
+			opt.AssignedLabels[label] = v
+		}
+		return nil
+	}
+}
+
+// WithSnapshots is a solver option that saves a snapshot of the solver state every nbLevels
+// levels of the constraint system (see constraint.System.Levels). The solver stops and returns
+// the error if save fails.
+//
+// Solving can be resumed from a snapshot with WithResume.
+func WithSnapshots(nbLevels int, save func(Snapshot) error) Option {
+	return func(opt *Config) error {
+		if nbLevels <= 0 {
+			return fmt.Errorf("invalid snapshot interval %d", nbLevels)
+		}
+		if save == nil {
+			return errors.New("no function to save the snapshots")
+		}
+		opt.SnapshotInterval = nbLevels
+		opt.SnapshotSave = save
+		return nil
+	}
+}
+
+// WithResume is a solver option that resumes solving from a snapshot written by a solver
+// with the WithSnapshots option, for the same constraint system and witness.
+func WithResume(r io.Reader) Option {
+	return func(opt *Config) error {
+		opt.Resume = r
+		return nil
+	}
+}
+
 // WithLogger is a prover option that specifies zerolog.Logger as a destination for the
 // logs printed by api.Println(). By default, uses gnark/logger.
 // zerolog.Nop() will disable logging
@@ -0,0 +1,207 @@
+package solver_test
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+func cube(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	outputs[0].Exp(inputs[0], big.NewInt(3), nil)
+	return nil
+}
+
+func failingCube(_ *big.Int, _ []*big.Int, _ []*big.Int) error {
+	return errors.New("the hint must not be called")
+}
+
+type labelCircuit struct {
+	X, Y frontend.Variable
+}
+
+func (c *labelCircuit) Define(api frontend.API) error {
+	labeler := api.Compiler().(frontend.Labeler)
+	res, err := api.Compiler().NewHint(cube, 1, c.X)
+	if err != nil {
+		return err
+	}
+	x3, err := labeler.Label(res[0], "cube")
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(x3, api.Mul(c.X, c.X, c.X))
+
+	sum, err := labeler.Label(api.Add(x3, c.Y), "sum")
+	if err != nil {
+		return err
+	}
+	api.AssertIsDifferent(sum, 0)
+	return nil
+}
+
+func TestAssignedWires(t *testing.T) {
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		assert := require.New(t)
+
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &labelCircuit{})
+		assert.NoError(err)
+		w, err := frontend.NewWitness(&labelCircuit{X: 3, Y: 5}, ecc.BN254.ScalarField())
+		assert.NoError(err)
+
+		_, err = ccs.Solve(w, solver.WithHints(cube))
+		assert.NoError(err)
+
+		skipHint := solver.OverrideHint(solver.GetHintID(cube), failingCube)
+		_, err = ccs.Solve(w, skipHint)
+		assert.Error(err, "hint called")
+
+		// the hint isn't called
+		_, err = ccs.Solve(w, skipHint, solver.WithAssignedLabels(map[string]any{"cube": 27}))
+		assert.NoError(err)
+		_, err = ccs.Solve(w, skipHint, solver.WithAssignedLabels(map[string]any{"cube": 27, "sum": 32}))
+		assert.NoError(err)
+		cubeID, ok := ccs.GetLabeledWire("cube")
+		assert.True(ok)
+		_, err = ccs.Solve(w, skipHint, solver.WithAssignedWires(map[int]any{cubeID: big.NewInt(27)}))
+		assert.NoError(err)
+
+		// assigned values are checked against the constraints
+		_, err = ccs.Solve(w, skipHint, solver.WithAssignedLabels(map[string]any{"cube": 28}))
+		assert.Error(err, "wrong hint output")
+		_, err = ccs.Solve(w, solver.WithHints(cube), solver.WithAssignedLabels(map[string]any{"sum": 31}))
+		assert.Error(err, "wrong computed wire")
+
+		// invalid assignments
+		_, err = ccs.Solve(w, solver.WithHints(cube), solver.WithAssignedLabels(map[string]any{"unknown": 1}))
+		assert.Error(err, "unknown label")
+		_, err = ccs.Solve(w, solver.WithHints(cube), solver.WithAssignedWires(map[int]any{1: 1}))
+		assert.Error(err, "input wire")
+		_, err = ccs.Solve(w, solver.WithHints(cube), solver.WithAssignedWires(map[int]any{cubeID: 27}), solver.WithAssignedLabels(map[string]any{"cube": 27}))
+		assert.Error(err, "wire assigned twice")
+	}
+}
+
+func TestLabelErrors(t *testing.T) {
+	assert := require.New(t)
+	_, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &labelErrorCircuit{constant: true})
+	assert.Error(err)
+	_, err = frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &labelErrorCircuit{})
+	assert.Error(err)
+}
+
+type labelErrorCircuit struct {
+	X        frontend.Variable
+	constant bool
+}
+
+func (c *labelErrorCircuit) Define(api frontend.API) error {
+	labeler := api.Compiler().(frontend.Labeler)
+	if c.constant {
+		_, err := labeler.Label(42, "constant")
+		return err
+	}
+	if _, err := labeler.Label(api.Mul(c.X, c.X), "square"); err != nil {
+		return err
+	}
+	_, err := labeler.Label(api.Mul(c.X, c.X, c.X), "square")
+	return err
+}
+
+type chainCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *chainCircuit) Define(api frontend.API) error {
+	x := c.X
+	for i := 0; i < 20; i++ {
+		res, err := api.Compiler().NewHint(cube, 1, x)
+		if err != nil {
+			return err
+		}
+		api.AssertIsEqual(res[0], api.Mul(x, x, x))
+		x = api.Add(res[0], i)
+	}
+	api.AssertIsEqual(x, c.Y)
+	return nil
+}
+
+func TestSnapshots(t *testing.T) {
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		assert := require.New(t)
+
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &chainCircuit{})
+		assert.NoError(err)
+		y := big.NewInt(2)
+		for i := 0; i < 20; i++ {
+			y.Exp(y, big.NewInt(3), ecc.BN254.ScalarField()).Add(y, big.NewInt(int64(i)))
+		}
+		w, err := frontend.NewWitness(&chainCircuit{X: 2, Y: y}, ecc.BN254.ScalarField())
+		assert.NoError(err)
+
+		expected, err := ccs.Solve(w, solver.WithHints(cube))
+		assert.NoError(err)
+
+		// interrupt the solver after the third snapshot
+		var snapshots []bytes.Buffer
+		var levels []int
+		errInterrupted := errors.New("interrupted")
+		_, err = ccs.Solve(w, solver.WithHints(cube), solver.WithSnapshots(3, func(s solver.Snapshot) error {
+			if len(snapshots) == 3 {
+				return errInterrupted
+			}
+			var buf bytes.Buffer
+			if _, err := s.WriteTo(&buf); err != nil {
+				return err
+			}
+			snapshots = append(snapshots, buf)
+			levels = append(levels, s.Level())
+			return nil
+		}))
+		assert.ErrorIs(err, errInterrupted)
+		assert.Equal([]int{3, 6, 9}, levels)
+
+		// resume from the last snapshot; the hints of the solved levels are not called again
+		nbCalls := 0
+		countingCube := func(q *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+			nbCalls++
+			return cube(q, inputs, outputs)
+		}
+		solution, err := ccs.Solve(w, solver.OverrideHint(solver.GetHintID(cube), countingCube), solver.WithResume(bytes.NewReader(snapshots[2].Bytes())))
+		assert.NoError(err)
+		assert.Equal(expected, solution)
+		assert.Less(nbCalls, 20)
+
+		// resuming again from an earlier snapshot, with snapshots
+		var resumedLevels []int
+		solution, err = ccs.Solve(w, solver.WithHints(cube), solver.WithResume(bytes.NewReader(snapshots[0].Bytes())), solver.WithSnapshots(3, func(s solver.Snapshot) error {
+			resumedLevels = append(resumedLevels, s.Level())
+			return nil
+		}))
+		assert.NoError(err)
+		assert.Equal(expected, solution)
+		assert.Equal(6, resumedLevels[0])
+
+		// the snapshot doesn't match the witness
+		other, err := frontend.NewWitness(&chainCircuit{X: 3, Y: y}, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		_, err = ccs.Solve(other, solver.WithHints(cube), solver.WithResume(bytes.NewReader(snapshots[2].Bytes())))
+		assert.Error(err)
+
+		// truncated snapshot
+		_, err = ccs.Solve(w, solver.WithHints(cube), solver.WithResume(bytes.NewReader(snapshots[2].Bytes()[:100])))
+		assert.Error(err)
+
+		// no function to save the snapshots
+		_, err = ccs.Solve(w, solver.WithHints(cube), solver.WithSnapshots(3, nil))
+		assert.Error(err)
+	}
+}
@@ -0,0 +1,13 @@
+package solver
+
+import "io"
+
+// Snapshot is the state of a solver after solving some levels of a constraint system: the
+// values of the solved wires (and, for R1CS, of the evaluated constraints). It is written in
+// a binary format read by WithResume.
+type Snapshot interface {
+	io.WriterTo
+
+	// Level returns the number of levels of the constraint system solved in the snapshot.
+	Level() int
+}
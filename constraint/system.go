@@ -56,6 +56,22 @@ type ConstraintSystem interface {
 
 	AddLog(l LogEntry)
 
+	// AddWireLabel labels an internal wire, so that its value can be provided to the solver
+	// by name (see solver.WithAssignedLabels).
+	AddWireLabel(label string, wireID int) error
+	// GetLabeledWire returns the ID of the internal wire with the given label.
+	GetLabeledWire(label string) (wireID int, ok bool)
+
+	// PushScope opens a scope, nested in the current one; the constraints and wire labels
+	// added until PopScope is called are recorded under its path.
+	PushScope(name string)
+	// PopScope closes the current scope.
+	PopScope()
+	// CurrentScope returns the path of the current scope, or "" outside any scope.
+	CurrentScope() string
+	// ConstraintScope returns the path of the scope in which a constraint was added.
+	ConstraintScope(cID int) string
+
 	// MakeTerm returns a new Term. The constraint system may store coefficients in a map, so
 	// calls to this function will grow the memory usage of the constraint system.
 	MakeTerm(coeff Element, variableID int) Term
@@ -77,6 +93,15 @@ type ConstraintSystem interface {
 
 	GetInstruction(int) Instruction
 
+	// GetSystem returns the curve agnostic part of the constraint system.
+	GetSystem() *System
+
+	// WriteText writes the constraint system in a human readable text format, see WriteText.
+	WriteText(w io.Writer) error
+
+	// ReadText parses a constraint system written in the text format, see ReadText.
+	ReadText(r io.Reader) error
+
 	GetCoefficient(i int) Element
 }
 
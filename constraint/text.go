@@ -0,0 +1,888 @@
+package constraint
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+// The text format is a human readable representation of a constraint system, meant for small
+// circuits: reviewing them in pull requests, writing them by hand for tests, or feeding them to
+// the solver. It is line oriented; blank lines and lines starting with '#' are ignored.
+//
+// The header declares the system type, the field modulus (hex) and the wires:
+//
+//	system r1cs
+//	field 30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001
+//	public Y
+//	secret X
+//	internal 3
+//
+// Public and secret wires are referred to by name; names can't start with a digit or '-', nor
+// contain whitespace or any of "+*=(),#". Internal wires are named v0, v1, ... (in allocation
+// order). In a R1CS, the first public wire is the constant wire "1"; it is implicit in the header
+// and a term on this wire is written as its coefficient.
+//
+// A linear expression is a sum of terms "c*w", "w" (coefficient 1) or "c" (constant), where c is
+// a field element in decimal (possibly negative) or 0x-prefixed hexadecimal. Each following line
+// is an instruction:
+//
+//	r1c (X) * (X) == (v0)                                      # R1CS constraint L⋅R == O
+//	scs xa=X xb=Y xc=v0 qL=1 qR=1 qO=-1 qM=0 qC=0              # qL⋅xa + qR⋅xb + qO⋅xc + qM⋅(xa×xb) + qC == 0
+//	hint github.com/consensys/gnark/std/math/bits.nBits (8, X) -> v1..v8
+//	table t0                                                   # declares a lookup table
+//	insert t0 (X, 2*Y)                                         # appends entries to the table
+//	lookup t0 n=2 (v1, 1) -> v9..v10                           # n is the number of table entries used
+//
+// The specialized PLONK gates are written as scs constraints with the keywords mul, add and bool,
+// and committed constraints carry an additional "commitment=1" (committed) or "commitment=2"
+// (commitment) field. Hints are referred to by the name registered in the constraint system;
+// hints registered with a custom ID use the decimal ID as name. Commitments are written last:
+//
+//	commitment groth16 hint=<name> index=12 nbPublic=1 public=1,12 private=4,5
+//	commitment plonk hint=<name> index=12 committed=3,4
+//
+// where indexes are raw wire (groth16, in a R1CS) or constraint (plonk, in a sparse R1CS) indexes.
+//
+// Logs (api.Println), debug information and GKR sub-circuits are not represented.
+
+const textHeader = "# gnark constraint system"
+
+// WriteText writes cs in the text format described above.
+func WriteText(w io.Writer, cs ConstraintSystem) error {
+	system := cs.GetSystem()
+	if system.GkrInfo.Is() {
+		return errors.New("text format: GKR sub-circuits are not supported")
+	}
+
+	tw := textWriter{
+		cs:     cs,
+		system: system,
+		w:      bufio.NewWriter(w),
+		tables: make(map[BlueprintID]*textTable),
+	}
+	if err := tw.writeHeader(); err != nil {
+		return err
+	}
+	if err := tw.writeInstructions(); err != nil {
+		return err
+	}
+	if err := tw.writeCommitments(); err != nil {
+		return err
+	}
+	return tw.w.Flush()
+}
+
+type textTable struct {
+	name    string
+	entries []LinearExpression
+	written int
+}
+
+type textWriter struct {
+	cs     ConstraintSystem
+	system *System
+	w      *bufio.Writer
+	tables map[BlueprintID]*textTable
+}
+
+func (tw *textWriter) writeHeader() error {
+	system := tw.system
+	fmt.Fprintln(tw.w, textHeader)
+	switch system.Type {
+	case SystemR1CS:
+		fmt.Fprintln(tw.w, "system r1cs")
+	case SystemSparseR1CS:
+		fmt.Fprintln(tw.w, "system scs")
+	default:
+		return fmt.Errorf("text format: unknown system type %d", system.Type)
+	}
+	fmt.Fprintf(tw.w, "field %s\n", system.Field().Text(16))
+
+	// check the input names are valid and unique
+	public := system.Public
+	if system.Type == SystemR1CS {
+		if len(public) == 0 || public[0] != "1" {
+			return errors.New("text format: the first public wire of a R1CS must be the constant wire \"1\"")
+		}
+		public = public[1:]
+	}
+	seen := make(map[string]struct{}, len(public)+len(system.Secret))
+	for _, names := range [][]string{public, system.Secret} {
+		for _, name := range names {
+			if err := checkTextName(name); err != nil {
+				return err
+			}
+			if _, ok := seen[name]; ok {
+				return fmt.Errorf("text format: duplicate input name %q", name)
+			}
+			seen[name] = struct{}{}
+		}
+	}
+	fmt.Fprintln(tw.w, strings.TrimSpace("public "+strings.Join(public, " ")))
+	fmt.Fprintln(tw.w, strings.TrimSpace("secret "+strings.Join(system.Secret, " ")))
+	fmt.Fprintf(tw.w, "internal %d\n", system.NbInternalVariables)
+
+	// lookup tables are declared upfront, their entries are written when needed.
+	for i, b := range system.Blueprints {
+		if bt, ok := b.(*BlueprintLookupHint); ok {
+			t := &textTable{name: fmt.Sprintf("t%d", len(tw.tables))}
+			for j := 0; j < len(bt.EntriesCalldata); {
+				n := int(bt.EntriesCalldata[j])
+				l := make(LinearExpression, n)
+				for k := 0; k < n; k++ {
+					l[k] = Term{CID: bt.EntriesCalldata[j+1+2*k], VID: bt.EntriesCalldata[j+2+2*k]}
+				}
+				t.entries = append(t.entries, l)
+				j += 1 + 2*n
+			}
+			tw.tables[BlueprintID(i)] = t
+			fmt.Fprintf(tw.w, "table %s\n", t.name)
+		}
+	}
+	return nil
+}
+
+func (tw *textWriter) writeInstructions() error {
+	system := tw.system
+	var (
+		r1c SparseR1C
+		hm  HintMapping
+		rc  R1C
+	)
+	for _, pi := range system.Instructions {
+		inst := pi.Unpack(system)
+		switch b := system.Blueprints[pi.BlueprintID].(type) {
+		case BlueprintR1C:
+			b.DecompressR1C(&rc, inst)
+			fmt.Fprintf(tw.w, "r1c (%s) * (%s) == (%s)\n", tw.linearExpression(rc.L), tw.linearExpression(rc.R), tw.linearExpression(rc.O))
+		case BlueprintSparseR1C:
+			b.DecompressSparseR1C(&r1c, inst)
+			var keyword string
+			switch b.(type) {
+			case *BlueprintGenericSparseR1C:
+				keyword = "scs"
+			case *BlueprintSparseR1CMul:
+				keyword = "mul"
+			case *BlueprintSparseR1CAdd:
+				keyword = "add"
+			case *BlueprintSparseR1CBool:
+				keyword = "bool"
+			default:
+				return fmt.Errorf("text format: unsupported blueprint %T", b)
+			}
+			fmt.Fprintf(tw.w, "%s xa=%s xb=%s xc=%s qL=%s qR=%s qO=%s qM=%s qC=%s", keyword,
+				tw.wire(r1c.XA), tw.wire(r1c.XB), tw.wire(r1c.XC),
+				tw.cs.CoeffToString(int(r1c.QL)), tw.cs.CoeffToString(int(r1c.QR)), tw.cs.CoeffToString(int(r1c.QO)),
+				tw.cs.CoeffToString(int(r1c.QM)), tw.cs.CoeffToString(int(r1c.QC)))
+			if r1c.Commitment != NOT {
+				fmt.Fprintf(tw.w, " commitment=%d", r1c.Commitment)
+			}
+			fmt.Fprintln(tw.w)
+		case BlueprintHint:
+			b.DecompressHint(&hm, inst)
+			fmt.Fprintf(tw.w, "hint %s (%s) -> %s\n", tw.hintName(hm.HintID), tw.linearExpressions(hm.Inputs), tw.wireRange(hm.OutputRange.Start, hm.OutputRange.End))
+		case *BlueprintLookupHint:
+			t := tw.tables[pi.BlueprintID]
+			nbEntries := int(inst.Calldata[1])
+			tw.writeEntries(t, nbEntries)
+			nbInputs := int(inst.Calldata[2])
+			inputs := make([]LinearExpression, nbInputs)
+			j := 3
+			for i := 0; i < nbInputs; i++ {
+				n := int(inst.Calldata[j])
+				inputs[i] = make(LinearExpression, n)
+				for k := 0; k < n; k++ {
+					inputs[i][k] = Term{CID: inst.Calldata[j+1+2*k], VID: inst.Calldata[j+2+2*k]}
+				}
+				j += 1 + 2*n
+			}
+			fmt.Fprintf(tw.w, "lookup %s n=%d (%s) -> %s\n", t.name, nbEntries, tw.linearExpressions(inputs), tw.wireRange(inst.WireOffset, inst.WireOffset+uint32(nbInputs)))
+		default:
+			return fmt.Errorf("text format: unsupported blueprint %T", b)
+		}
+	}
+
+	// entries inserted after the last lookup
+	for i := range system.Blueprints {
+		if t, ok := tw.tables[BlueprintID(i)]; ok {
+			tw.writeEntries(t, len(t.entries))
+		}
+	}
+	return nil
+}
+
+func (tw *textWriter) writeEntries(t *textTable, upTo int) {
+	if upTo <= t.written {
+		return
+	}
+	fmt.Fprintf(tw.w, "insert %s (%s)\n", t.name, tw.linearExpressions(t.entries[t.written:upTo]))
+	t.written = upTo
+}
+
+func (tw *textWriter) writeCommitments() error {
+	joinInts := func(l []int) string {
+		s := make([]string, len(l))
+		for i := range l {
+			s[i] = strconv.Itoa(l[i])
+		}
+		return strings.Join(s, ",")
+	}
+	switch c := tw.system.CommitmentInfo.(type) {
+	case Groth16Commitments:
+		for i := range c {
+			fmt.Fprintf(tw.w, "commitment groth16 hint=%s index=%d nbPublic=%d public=%s private=%s\n",
+				tw.hintName(c[i].HintID), c[i].CommitmentIndex, c[i].NbPublicCommitted,
+				joinInts(c[i].PublicAndCommitmentCommitted), joinInts(c[i].PrivateCommitted))
+		}
+	case PlonkCommitments:
+		for i := range c {
+			fmt.Fprintf(tw.w, "commitment plonk hint=%s index=%d committed=%s\n",
+				tw.hintName(c[i].HintID), c[i].CommitmentIndex, joinInts(c[i].Committed))
+		}
+	}
+	return nil
+}
+
+func (tw *textWriter) hintName(id solver.HintID) string {
+	if name, ok := tw.system.MHintsDependencies[id]; ok {
+		return name
+	}
+	return strconv.Itoa(int(id))
+}
+
+func (tw *textWriter) wire(vID uint32) string {
+	return tw.system.VariableToString(int(vID))
+}
+
+func (tw *textWriter) wireRange(start, end uint32) string {
+	if end == start+1 {
+		return tw.wire(start)
+	}
+	return tw.wire(start) + ".." + tw.wire(end-1)
+}
+
+func (tw *textWriter) linearExpressions(l []LinearExpression) string {
+	s := make([]string, len(l))
+	for i := range l {
+		s[i] = tw.linearExpression(l[i])
+	}
+	return strings.Join(s, ", ")
+}
+
+func (tw *textWriter) linearExpression(l LinearExpression) string {
+	var sbb strings.Builder
+	for i, t := range l {
+		if i > 0 {
+			sbb.WriteString(" + ")
+		}
+		coeff := tw.cs.CoeffToString(t.CoeffID())
+		if t.IsConstant() || (tw.system.Type == SystemR1CS && t.VID == 0) {
+			sbb.WriteString(coeff)
+			continue
+		}
+		if t.CoeffID() != CoeffIdOne {
+			sbb.WriteString(coeff)
+			sbb.WriteByte('*')
+		}
+		sbb.WriteString(tw.wire(t.VID))
+	}
+	return sbb.String()
+}
+
+var (
+	reInternalWire = regexp.MustCompile(`^v([0-9]+)$`)
+	reR1C          = regexp.MustCompile(`^r1c\s+\(([^()]*)\)\s*\*\s*\(([^()]*)\)\s*==\s*\(([^()]*)\)$`)
+	reHint         = regexp.MustCompile(`^hint\s+(\S+)\s+\(([^()]*)\)\s*->\s*(\S+)$`)
+	reInsert       = regexp.MustCompile(`^insert\s+(\S+)\s+\(([^()]*)\)$`)
+	reLookup       = regexp.MustCompile(`^lookup\s+(\S+)\s+n=([0-9]+)\s+\(([^()]*)\)\s*->\s*(\S+)$`)
+)
+
+func checkTextName(name string) error {
+	if name == "" {
+		return errors.New("text format: empty input name")
+	}
+	if (name[0] >= '0' && name[0] <= '9') || name[0] == '-' || strings.ContainsAny(name, " \t\r\n+*=(),#") {
+		return fmt.Errorf("text format: invalid input name %q", name)
+	}
+	if reInternalWire.MatchString(name) {
+		return fmt.Errorf("text format: input name %q is reserved for internal wires", name)
+	}
+	return nil
+}
+
+// ReadText parses a constraint system in the text format described above. newSystem is called
+// once the system type is parsed and must return an empty constraint system of that type,
+// defined over the field declared in the header.
+func ReadText(r io.Reader, newSystem func(SystemType) ConstraintSystem) error {
+	tr := textReader{
+		wires:  make(map[string]int),
+		tables: make(map[string]BlueprintID),
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<30)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		if err := tr.readLine(line, newSystem); err != nil {
+			return fmt.Errorf("text format: line %d: %w", lineNumber, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if tr.cs == nil {
+		return errors.New("text format: missing header")
+	}
+	if tr.nbInternal < 0 {
+		return errors.New("text format: missing internal wires declaration")
+	}
+	if tr.system.NbInternalVariables > tr.nbInternal {
+		return fmt.Errorf("text format: %d internal wires used, %d declared", tr.system.NbInternalVariables, tr.nbInternal)
+	}
+	for tr.system.NbInternalVariables < tr.nbInternal {
+		tr.cs.AddInternalVariable()
+	}
+	return nil
+}
+
+type textReader struct {
+	cs     ConstraintSystem
+	system *System
+
+	// header state
+	fieldSet, publicSet, secretSet bool
+	nbInternal                     int
+
+	wires  map[string]int // input names to wire ID
+	tables map[string]BlueprintID
+
+	bR1C, bSCS, bMul, bAdd, bBool BlueprintID
+	hasR1C, hasSCS                bool
+}
+
+func (tr *textReader) readLine(line string, newSystem func(SystemType) ConstraintSystem) error {
+	fields := strings.Fields(line)
+	keyword := fields[0]
+
+	if tr.cs == nil {
+		if keyword != "system" || len(fields) != 2 {
+			return errors.New("expected system declaration")
+		}
+		switch fields[1] {
+		case "r1cs":
+			tr.cs = newSystem(SystemR1CS)
+		case "scs":
+			tr.cs = newSystem(SystemSparseR1CS)
+		default:
+			return fmt.Errorf("unknown system type %q", fields[1])
+		}
+		tr.system = tr.cs.GetSystem()
+		tr.nbInternal = -1
+		return nil
+	}
+
+	// header
+	switch keyword {
+	case "field":
+		if len(fields) != 2 {
+			return errors.New("invalid field declaration")
+		}
+		q, ok := new(big.Int).SetString(fields[1], 16)
+		if !ok || q.Cmp(tr.cs.Field()) != 0 {
+			return fmt.Errorf("field %s doesn't match the constraint system field %s", fields[1], tr.cs.Field().Text(16))
+		}
+		tr.fieldSet = true
+		return nil
+	case "public":
+		if !tr.fieldSet || tr.publicSet {
+			return errors.New("public wires must be declared once, after the field")
+		}
+		if tr.system.Type == SystemR1CS {
+			tr.cs.AddPublicVariable("1")
+		}
+		tr.publicSet = true
+		return tr.addInputs(fields[1:], tr.cs.AddPublicVariable)
+	case "secret":
+		if !tr.publicSet || tr.secretSet {
+			return errors.New("secret wires must be declared once, after the public wires")
+		}
+		tr.secretSet = true
+		return tr.addInputs(fields[1:], tr.cs.AddSecretVariable)
+	case "internal":
+		if !tr.secretSet || tr.nbInternal >= 0 || len(fields) != 2 {
+			return errors.New("internal wires must be declared once, after the secret wires")
+		}
+		n, err := strconv.Atoi(fields[1])
+		if err != nil || n < 0 || n > math.MaxUint32-tr.system.GetNbPublicVariables()-tr.system.GetNbSecretVariables() {
+			return fmt.Errorf("invalid number of internal wires %q", fields[1])
+		}
+		tr.nbInternal = n
+		return nil
+	}
+	if tr.nbInternal < 0 {
+		return errors.New("incomplete header")
+	}
+
+	switch keyword {
+	case "r1c":
+		return tr.readR1C(line)
+	case "scs", "mul", "add", "bool":
+		return tr.readSparseR1C(keyword, fields[1:])
+	case "hint":
+		return tr.readHint(line)
+	case "table":
+		if len(fields) != 2 {
+			return errors.New("invalid table declaration")
+		}
+		if _, ok := tr.tables[fields[1]]; ok {
+			return fmt.Errorf("table %s declared twice", fields[1])
+		}
+		tr.tables[fields[1]] = tr.cs.AddBlueprint(&BlueprintLookupHint{})
+		return nil
+	case "insert":
+		return tr.readInsert(line)
+	case "lookup":
+		return tr.readLookup(line)
+	case "commitment":
+		return tr.readCommitment(fields[1:])
+	}
+	return fmt.Errorf("unknown instruction %q", keyword)
+}
+
+func (tr *textReader) addInputs(names []string, add func(string) int) error {
+	for _, name := range names {
+		if err := checkTextName(name); err != nil {
+			return err
+		}
+		if _, ok := tr.wires[name]; ok {
+			return fmt.Errorf("duplicate input name %q", name)
+		}
+		tr.wires[name] = add(name)
+	}
+	return nil
+}
+
+func (tr *textReader) readR1C(line string) error {
+	if tr.system.Type != SystemR1CS {
+		return errors.New("r1c constraint in a sparse R1CS")
+	}
+	m := reR1C.FindStringSubmatch(line)
+	if m == nil {
+		return errors.New("invalid r1c constraint")
+	}
+	var (
+		c   R1C
+		err error
+	)
+	if c.L, err = tr.linearExpression(m[1]); err != nil {
+		return err
+	}
+	if c.R, err = tr.linearExpression(m[2]); err != nil {
+		return err
+	}
+	if c.O, err = tr.linearExpression(m[3]); err != nil {
+		return err
+	}
+	if !tr.hasR1C {
+		tr.bR1C = tr.cs.AddBlueprint(&BlueprintGenericR1C{})
+		tr.hasR1C = true
+	}
+	tr.cs.(R1CS).AddR1C(c, tr.bR1C)
+	return nil
+}
+
+func (tr *textReader) readSparseR1C(keyword string, fields []string) error {
+	if tr.system.Type != SystemSparseR1CS {
+		return fmt.Errorf("%s constraint in a R1CS", keyword)
+	}
+	if !tr.hasSCS {
+		tr.bSCS = tr.cs.AddBlueprint(&BlueprintGenericSparseR1C{})
+		tr.bMul = tr.cs.AddBlueprint(&BlueprintSparseR1CMul{})
+		tr.bAdd = tr.cs.AddBlueprint(&BlueprintSparseR1CAdd{})
+		tr.bBool = tr.cs.AddBlueprint(&BlueprintSparseR1CBool{})
+		tr.hasSCS = true
+	}
+
+	var c SparseR1C
+	for _, f := range fields {
+		kv := strings.SplitN(f, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("invalid field %q", f)
+		}
+		var err error
+		switch kv[0] {
+		case "xa":
+			c.XA, err = tr.wireID(kv[1])
+		case "xb":
+			c.XB, err = tr.wireID(kv[1])
+		case "xc":
+			c.XC, err = tr.wireID(kv[1])
+		case "qL":
+			c.QL, err = tr.coeffID(kv[1])
+		case "qR":
+			c.QR, err = tr.coeffID(kv[1])
+		case "qO":
+			c.QO, err = tr.coeffID(kv[1])
+		case "qM":
+			c.QM, err = tr.coeffID(kv[1])
+		case "qC":
+			c.QC, err = tr.coeffID(kv[1])
+		case "commitment":
+			var v uint64
+			v, err = strconv.ParseUint(kv[1], 10, 32)
+			c.Commitment = CommitmentConstraint(v)
+		default:
+			err = fmt.Errorf("unknown field %q", kv[0])
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	bID := tr.bSCS
+	switch keyword {
+	case "mul":
+		bID = tr.bMul
+	case "add":
+		bID = tr.bAdd
+	case "bool":
+		bID = tr.bBool
+	}
+	tr.cs.(SparseR1CS).AddSparseR1C(c, bID)
+	return nil
+}
+
+func (tr *textReader) readHint(line string) error {
+	m := reHint.FindStringSubmatch(line)
+	if m == nil {
+		return errors.New("invalid hint")
+	}
+	id := tr.hintID(m[1])
+	if registered, ok := tr.system.MHintsDependencies[id]; ok && registered != m[1] {
+		return fmt.Errorf("hint %s has the same ID as %s", m[1], registered)
+	}
+	inputs, err := tr.linearExpressions(m[2])
+	if err != nil {
+		return err
+	}
+	start, end, err := tr.outputs(m[3])
+	if err != nil {
+		return err
+	}
+	tr.system.MHintsDependencies[id] = m[1]
+
+	// outputs are allocated before the hint instruction, see System.AddSolverHint
+	for i := start; i < end; i++ {
+		tr.cs.AddInternalVariable()
+	}
+	hm := HintMapping{HintID: id, Inputs: inputs}
+	hm.OutputRange.Start, hm.OutputRange.End = start, end
+
+	calldata := getBuffer()
+	tr.system.Blueprints[tr.system.genericHint].(BlueprintHint).CompressHint(hm, calldata)
+	tr.cs.AddInstruction(tr.system.genericHint, *calldata)
+	putBuffer(calldata)
+	return nil
+}
+
+func (tr *textReader) readInsert(line string) error {
+	m := reInsert.FindStringSubmatch(line)
+	if m == nil {
+		return errors.New("invalid insert")
+	}
+	b, err := tr.table(m[1])
+	if err != nil {
+		return err
+	}
+	entries, err := tr.linearExpressions(m[2])
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		e.Compress(&b.EntriesCalldata)
+	}
+	return nil
+}
+
+func (tr *textReader) readLookup(line string) error {
+	m := reLookup.FindStringSubmatch(line)
+	if m == nil {
+		return errors.New("invalid lookup")
+	}
+	b, err := tr.table(m[1])
+	if err != nil {
+		return err
+	}
+	nbEntries, err := strconv.Atoi(m[2])
+	if err != nil {
+		return err
+	}
+	available := 0
+	for j := 0; j < len(b.EntriesCalldata); j += 1 + 2*int(b.EntriesCalldata[j]) {
+		available++
+	}
+	if nbEntries > available {
+		return fmt.Errorf("lookup uses %d entries, table %s has %d", nbEntries, m[1], available)
+	}
+	inputs, err := tr.linearExpressions(m[3])
+	if err != nil {
+		return err
+	}
+	start, end, err := tr.outputs(m[4])
+	if err != nil {
+		return err
+	}
+	if int(end-start) != len(inputs) {
+		return fmt.Errorf("lookup has %d inputs but %d outputs", len(inputs), end-start)
+	}
+
+	calldata := make([]uint32, 3, 3+len(inputs)*3)
+	calldata[1] = uint32(nbEntries)
+	calldata[2] = uint32(len(inputs))
+	for _, in := range inputs {
+		in.Compress(&calldata)
+	}
+	calldata[0] = uint32(len(calldata))
+	tr.cs.AddInstruction(tr.tables[m[1]], calldata)
+	return nil
+}
+
+// commitmentFields lists the fields of the commitments of each type.
+var commitmentFields = map[string]map[string]bool{
+	"groth16": {"hint": true, "index": true, "nbPublic": true, "public": true, "private": true},
+	"plonk":   {"hint": true, "index": true, "committed": true},
+}
+
+func (tr *textReader) readCommitment(fields []string) error {
+	if len(fields) == 0 {
+		return errors.New("invalid commitment")
+	}
+	allowed, ok := commitmentFields[fields[0]]
+	if !ok {
+		return fmt.Errorf("unknown commitment type %q", fields[0])
+	}
+	if fields[0] == "groth16" && tr.system.Type != SystemR1CS {
+		return errors.New("groth16 commitment in a sparse R1CS")
+	}
+	if fields[0] == "plonk" && tr.system.Type != SystemSparseR1CS {
+		return errors.New("plonk commitment in a R1CS")
+	}
+	values := make(map[string]string)
+	for _, f := range fields[1:] {
+		kv := strings.SplitN(f, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("invalid field %q", f)
+		}
+		if !allowed[kv[0]] {
+			return fmt.Errorf("unknown %s commitment field %q", fields[0], kv[0])
+		}
+		values[kv[0]] = kv[1]
+	}
+	if _, ok := values["hint"]; !ok {
+		return errors.New("commitment without hint")
+	}
+
+	// indexes are wire indexes in a groth16 commitment and constraint indexes in a plonk one
+	var err error
+	bound := tr.cs.GetNbConstraints()
+	if fields[0] == "groth16" {
+		bound = tr.system.GetNbPublicVariables() + tr.system.GetNbSecretVariables() + tr.nbInternal
+	}
+	index := func(key, v string) int {
+		i, e := strconv.Atoi(v)
+		if err == nil {
+			if e != nil {
+				err = fmt.Errorf("invalid %s %q", key, values[key])
+			} else if i < 0 || i >= bound {
+				err = fmt.Errorf("%s %d out of range [0, %d)", key, i, bound)
+			}
+		}
+		return i
+	}
+	indexes := func(key string) []int {
+		if values[key] == "" {
+			return nil
+		}
+		s := strings.Split(values[key], ",")
+		r := make([]int, len(s))
+		for i := range s {
+			r[i] = index(key, s[i])
+		}
+		return r
+	}
+	hintID := tr.hintID(values["hint"])
+
+	var c Commitment
+	if fields[0] == "groth16" {
+		g := Groth16Commitment{
+			PublicAndCommitmentCommitted: indexes("public"),
+			PrivateCommitted:             indexes("private"),
+			CommitmentIndex:              index("index", values["index"]),
+			HintID:                       hintID,
+		}
+		nbPublic, e := strconv.Atoi(values["nbPublic"])
+		if err == nil && (e != nil || nbPublic < 0 || nbPublic > len(g.PublicAndCommitmentCommitted)) {
+			err = fmt.Errorf("invalid nbPublic %q", values["nbPublic"])
+		}
+		g.NbPublicCommitted = nbPublic
+		c = g
+	} else {
+		c = PlonkCommitment{
+			Committed:       indexes("committed"),
+			CommitmentIndex: index("index", values["index"]),
+			HintID:          hintID,
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return tr.cs.AddCommitment(c)
+}
+
+func (tr *textReader) table(name string) (*BlueprintLookupHint, error) {
+	bID, ok := tr.tables[name]
+	if !ok {
+		return nil, fmt.Errorf("undeclared table %s", name)
+	}
+	return tr.system.Blueprints[bID].(*BlueprintLookupHint), nil
+}
+
+func (tr *textReader) hintID(name string) solver.HintID {
+	if id, err := strconv.ParseUint(name, 10, 32); err == nil {
+		return solver.HintID(id)
+	}
+	return solver.GetHintIDFromName(name)
+}
+
+// outputs parses a range of fresh internal wires "vA..vB" or "vA" and returns the
+// corresponding wire IDs [start, end). Internal wires before vA are allocated.
+func (tr *textReader) outputs(s string) (start, end uint32, err error) {
+	first, last := s, s
+	if i := strings.Index(s, ".."); i >= 0 {
+		first, last = s[:i], s[i+2:]
+	}
+	a, b := reInternalWire.FindStringSubmatch(first), reInternalWire.FindStringSubmatch(last)
+	if a == nil || b == nil {
+		return 0, 0, fmt.Errorf("invalid output wires %q", s)
+	}
+	ia, errA := strconv.Atoi(a[1])
+	ib, errB := strconv.Atoi(b[1])
+	if errA != nil || errB != nil || ib < ia {
+		return 0, 0, fmt.Errorf("invalid output wires %q", s)
+	}
+	if ia < tr.system.NbInternalVariables {
+		return 0, 0, fmt.Errorf("output wire v%d is already allocated", ia)
+	}
+	if ib >= tr.nbInternal {
+		return 0, 0, fmt.Errorf("output wire v%d exceeds the number of internal wires", ib)
+	}
+	offset := tr.system.GetNbPublicVariables() + tr.system.GetNbSecretVariables()
+	if offset+ib+1 > math.MaxUint32 {
+		return 0, 0, fmt.Errorf("output wires %q overflow the wire IDs", s)
+	}
+	for tr.system.NbInternalVariables < ia {
+		tr.cs.AddInternalVariable()
+	}
+	return uint32(offset + ia), uint32(offset + ib + 1), nil
+}
+
+func (tr *textReader) wireID(name string) (uint32, error) {
+	if id, ok := tr.wires[name]; ok {
+		return uint32(id), nil
+	}
+	m := reInternalWire.FindStringSubmatch(name)
+	if m == nil {
+		return 0, fmt.Errorf("unknown wire %q", name)
+	}
+	i, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, fmt.Errorf("unknown wire %q", name)
+	}
+	if i >= tr.nbInternal {
+		return 0, fmt.Errorf("wire %s exceeds the number of internal wires", name)
+	}
+	for tr.system.NbInternalVariables <= i {
+		tr.cs.AddInternalVariable()
+	}
+	return uint32(tr.system.GetNbPublicVariables() + tr.system.GetNbSecretVariables() + i), nil
+}
+
+func (tr *textReader) coeff(s string) (Element, error) {
+	v, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return Element{}, fmt.Errorf("invalid coefficient %q", s)
+	}
+	return tr.cs.FromInterface(v), nil
+}
+
+func (tr *textReader) coeffID(s string) (uint32, error) {
+	c, err := tr.coeff(s)
+	if err != nil {
+		return 0, err
+	}
+	return tr.cs.AddCoeff(c), nil
+}
+
+func (tr *textReader) linearExpressions(s string) ([]LinearExpression, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	parts := strings.Split(s, ",")
+	r := make([]LinearExpression, len(parts))
+	for i := range parts {
+		var err error
+		if r[i], err = tr.linearExpression(parts[i]); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (tr *textReader) linearExpression(s string) (LinearExpression, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return LinearExpression{}, nil
+	}
+	parts := strings.Split(s, "+")
+	l := make(LinearExpression, len(parts))
+	for i, p := range parts {
+		p = strings.TrimSpace(p)
+		coeff, wire := "1", p
+		if j := strings.IndexByte(p, '*'); j >= 0 {
+			coeff, wire = strings.TrimSpace(p[:j]), strings.TrimSpace(p[j+1:])
+		} else if p != "" && (p[0] == '-' || (p[0] >= '0' && p[0] <= '9')) {
+			coeff, wire = p, ""
+		}
+		c, err := tr.coeff(coeff)
+		if err != nil {
+			return nil, err
+		}
+		if wire == "" {
+			// constant term
+			l[i] = tr.cs.MakeTerm(c, 0)
+			if tr.system.Type != SystemR1CS {
+				l[i].MarkConstant()
+			}
+			continue
+		}
+		vID, err := tr.wireID(wire)
+		if err != nil {
+			return nil, err
+		}
+		l[i] = tr.cs.MakeTerm(c, int(vID))
+	}
+	return l, nil
+}
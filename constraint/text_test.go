@@ -0,0 +1,149 @@
+package constraint_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/constraint"
+	cs "github.com/consensys/gnark/constraint/bn254"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/stretchr/testify/require"
+)
+
+type textCircuit struct {
+	X, Z frontend.Variable
+	Y    frontend.Variable `gnark:",public"`
+}
+
+func (c *textCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(c.X, c.X, c.X)
+	api.AssertIsEqual(api.Add(x3, c.X, 5), c.Y)
+	api.AssertIsBoolean(c.Z)
+	api.ToBinary(c.X, 8)
+	api.Sub(api.Inverse(c.Y), -3)
+
+	t := logderivlookup.New(api)
+	for i := 0; i < 4; i++ {
+		t.Insert(api.Mul(c.X, i+1))
+	}
+	r := t.Lookup(c.Z, 3)
+	t.Insert(c.Y)
+	r = append(r, t.Lookup(4)...)
+	api.AssertIsDifferent(r[0], r[2])
+	return nil
+}
+
+func TestTextRoundTrip(t *testing.T) {
+	assert := require.New(t)
+	assignment := &textCircuit{X: 3, Y: 35, Z: 1}
+	w, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &textCircuit{})
+		assert.NoError(err)
+
+		var text bytes.Buffer
+		assert.NoError(ccs.WriteText(&text))
+
+		parsed := groth16.NewCS(ecc.BN254)
+		assert.NoError(parsed.ReadText(bytes.NewReader(text.Bytes())), text.String())
+
+		var again bytes.Buffer
+		assert.NoError(parsed.WriteText(&again))
+		assert.Equal(text.String(), again.String())
+
+		assert.Equal(ccs.GetNbConstraints(), parsed.GetNbConstraints())
+		assert.Equal(ccs.GetNbInternalVariables(), parsed.GetNbInternalVariables())
+		assert.Equal(ccs.GetNbPublicVariables(), parsed.GetNbPublicVariables())
+		assert.Equal(ccs.GetNbSecretVariables(), parsed.GetNbSecretVariables())
+
+		_, err = parsed.Solve(w)
+		assert.NoError(err)
+
+		bad, err := frontend.NewWitness(&textCircuit{X: 3, Y: 36, Z: 1}, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		_, err = parsed.Solve(bad)
+		assert.Error(err)
+	}
+}
+
+func TestReadTextErrors(t *testing.T) {
+	header := "system r1cs\nfield 30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001\npublic Y\nsecret X\ninternal 1\n"
+	scsHeader := strings.Replace(header, "r1cs", "scs", 1)
+	for _, tc := range []struct {
+		name, text string
+	}{
+		{"missing header", "r1c (X) * (X) == (Y)\n"},
+		{"wrong field", strings.Replace(header, "3064", "3065", 1)},
+		{"unknown wire", header + "r1c (X) * (W) == (Y)\n"},
+		{"undeclared internal wire", header + "r1c (X) * (X) == (v1)\n"},
+		{"reserved name", strings.Replace(header, "secret X", "secret v3", 1)},
+		{"duplicate name", strings.Replace(header, "secret X", "secret Y", 1)},
+		{"sparse constraint", header + "scs xa=X xb=X xc=Y qL=0 qR=0 qO=-1 qM=1 qC=0\n"},
+		{"allocated hint output", header + "r1c (X) * (X) == (v0)\nhint foo (X) -> v0\n"},
+		{"undeclared hint output", header + "hint foo (X) -> v0..v1\n"},
+		{"hint output overflow", header + "hint foo (X) -> v0..v4294967296\n"},
+		{"undeclared lookup output", header + "table t0\ninsert t0 (X)\nlookup t0 n=1 (0) -> v1\n"},
+		{"too many internal wires", strings.Replace(header, "internal 1", "internal 4294967296", 1)},
+		{"commitment index", header + "commitment groth16 hint=bar index=100 nbPublic=0 private=2\n"},
+		{"committed wire", header + "commitment groth16 hint=bar index=3 nbPublic=0 private=7,9\n"},
+		{"nbPublic", header + "commitment groth16 hint=bar index=3 nbPublic=2 public=1 private=2\n"},
+		{"plonk commitment in a R1CS", header + "commitment plonk hint=bar index=0 committed=0\n"},
+		{"plonk field in a groth16 commitment", header + "commitment groth16 hint=bar index=3 nbPublic=0 committed=2\n"},
+		{"groth16 commitment in a sparse R1CS", scsHeader + "scs xa=X xb=Y xc=v0 qL=1 qR=1 qO=-1 qM=0 qC=0\ncommitment groth16 hint=bar index=3 nbPublic=0 private=2\n"},
+		{"committed constraint", scsHeader + "scs xa=X xb=Y xc=v0 qL=1 qR=1 qO=-1 qM=0 qC=0\ncommitment plonk hint=bar index=0 committed=5\n"},
+		{"undeclared table", header + "lookup t0 n=0 (X) -> v0\n"},
+	} {
+		err := groth16.NewCS(ecc.BN254).ReadText(strings.NewReader(tc.text))
+		require.Error(t, err, tc.name)
+	}
+}
+
+func ExampleWriteText() {
+	// build a small R1CS "by hand": X³ + X + 5 == Y
+	r1cs := cs.NewR1CS(0)
+	blueprint := r1cs.AddBlueprint(&constraint.BlueprintGenericR1C{})
+
+	cOne := r1cs.FromInterface(1)
+
+	ONE := r1cs.AddPublicVariable("1")
+	Y := r1cs.AddPublicVariable("Y")
+	X := r1cs.AddSecretVariable("X")
+	v0 := r1cs.AddInternalVariable() // X²
+
+	r1cs.AddR1C(constraint.R1C{
+		L: constraint.LinearExpression{r1cs.MakeTerm(cOne, X)},
+		R: constraint.LinearExpression{r1cs.MakeTerm(cOne, X)},
+		O: constraint.LinearExpression{r1cs.MakeTerm(cOne, v0)},
+	}, blueprint)
+	r1cs.AddR1C(constraint.R1C{
+		L: constraint.LinearExpression{r1cs.MakeTerm(cOne, v0)},
+		R: constraint.LinearExpression{r1cs.MakeTerm(cOne, X)},
+		O: constraint.LinearExpression{
+			r1cs.MakeTerm(cOne, Y),
+			r1cs.MakeTerm(r1cs.FromInterface(-1), X),
+			r1cs.MakeTerm(r1cs.FromInterface(-5), ONE),
+		},
+	}, blueprint)
+
+	if err := r1cs.WriteText(os.Stdout); err != nil {
+		panic(err)
+	}
+	// Output:
+	// # gnark constraint system
+	// system r1cs
+	// field 30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001
+	// public Y
+	// secret X
+	// internal 1
+	// r1c (X) * (X) == (v0)
+	// r1c (v0) * (X) == (Y + -1*X + -5)
+}
@@ -187,6 +187,20 @@ func (cs *system) ReadFrom(r io.Reader) (int64, error) {
 	return int64(decoder.NumBytesRead()), nil
 }
 
+// WriteText writes the constraint system in a human readable text format (see constraint.WriteText)
+func (cs *system) WriteText(w io.Writer) error {
+	return constraint.WriteText(w, cs)
+}
+
+// ReadText parses a constraint system written in the text format (see constraint.ReadText).
+// The system type (R1CS or SparseR1CS) is read from the text.
+func (cs *system) ReadText(r io.Reader) error {
+	return constraint.ReadText(r, func(t constraint.SystemType) constraint.ConstraintSystem {
+		*cs = *newSystem(0, t)
+		return cs
+	})
+}
+
 func (cs *system) GetCoefficient(i int) (r constraint.Element) {
 	copy(r[:], cs.Coefficients[i][:])
 	return
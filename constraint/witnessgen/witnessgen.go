@@ -0,0 +1,266 @@
+// Package witnessgen exports the solving program of a compiled constraint system, to compute
+// full assignments on machines which don't have the Go code of the circuit.
+//
+// Export writes a program: the parts of a constraint system needed to solve it (instructions,
+// blueprints, coefficients, wire layout, commitments and identifiers of the hints it depends on)
+// prefixed with a header identifying the curve, so that it can be loaded without knowing the
+// circuit. The debug information (stack traces, symbol table, logs and scopes) is left out. Only
+// R1CS constraint systems are supported:
+//
+//	// on the machine compiling the circuit
+//	ccs, _ := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &circuit)
+//	err := witnessgen.Export(f, ccs)
+//
+//	// on the prover machine
+//	p, err := witnessgen.Read(f)
+//	values, err := p.Solve(w, solver.WithHints(myHints...))
+//	err = p.WriteAssignment(out, values)
+//
+// The hints of the circuit are not part of the program; they must be registered in the runner
+// (see solver.RegisterHint) or given as solver options. The witnessgen command is a runner with
+// the hints of gnark/std registered.
+package witnessgen
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	cs_bls12377 "github.com/consensys/gnark/constraint/bls12-377"
+	cs_bls12381 "github.com/consensys/gnark/constraint/bls12-381"
+	cs_bls24315 "github.com/consensys/gnark/constraint/bls24-315"
+	cs_bls24317 "github.com/consensys/gnark/constraint/bls24-317"
+	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
+	cs_bw6633 "github.com/consensys/gnark/constraint/bw6-633"
+	cs_bw6761 "github.com/consensys/gnark/constraint/bw6-761"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/debug"
+	"github.com/consensys/gnark/std/utils/export_utils"
+)
+
+// magic starts the encoding of a program
+const magic = "gnark witnessgen\n"
+
+// ErrUnsupported is returned for constraint systems which are not R1CS.
+var ErrUnsupported = errors.New("witnessgen: only R1CS constraint systems are supported")
+
+// Program is a compiled constraint system loaded for witness generation.
+type Program struct {
+	Curve  ecc.ID
+	System constraint.ConstraintSystem
+}
+
+// Export writes the solving program of the constraint system ccs to w.
+func Export(w io.Writer, ccs constraint.ConstraintSystem) error {
+	if ccs.GetSystem().Type != constraint.SystemR1CS {
+		return ErrUnsupported
+	}
+	curve, err := curveOf(ccs.Field())
+	if err != nil {
+		return err
+	}
+	program, err := solvingProgram(ccs)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, magic); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, uint16(curve)); err != nil {
+		return err
+	}
+	_, err = program.WriteTo(w)
+	return err
+}
+
+// solvingProgram returns a shallow copy of ccs without its debug information, which the solver
+// only uses to describe the unsatisfied constraints.
+func solvingProgram(ccs constraint.ConstraintSystem) (constraint.ConstraintSystem, error) {
+	var (
+		program constraint.ConstraintSystem
+		system  *constraint.System
+	)
+	switch c := ccs.(type) {
+	case *cs_bn254.R1CS:
+		cp := *c
+		program, system = &cp, &cp.System
+	case *cs_bls12377.R1CS:
+		cp := *c
+		program, system = &cp, &cp.System
+	case *cs_bls12381.R1CS:
+		cp := *c
+		program, system = &cp, &cp.System
+	case *cs_bw6761.R1CS:
+		cp := *c
+		program, system = &cp, &cp.System
+	case *cs_bls24317.R1CS:
+		cp := *c
+		program, system = &cp, &cp.System
+	case *cs_bls24315.R1CS:
+		cp := *c
+		program, system = &cp, &cp.System
+	case *cs_bw6633.R1CS:
+		cp := *c
+		program, system = &cp, &cp.System
+	default:
+		return nil, fmt.Errorf("unexpected constraint system type %T", ccs)
+	}
+	system.Logs = nil
+	system.DebugInfo = nil
+	system.SymbolTable = debug.NewSymbolTable()
+	system.MDebug = nil
+	system.Scopes = nil
+	system.ScopeRanges = nil
+	return program, nil
+}
+
+// Read reads a program written by Export.
+func Read(r io.Reader) (*Program, error) {
+	header := make([]byte, len(magic)+2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	if !bytes.Equal(header[:len(magic)], []byte(magic)) {
+		return nil, errors.New("not a witnessgen program")
+	}
+	curve := ecc.ID(binary.BigEndian.Uint16(header[len(magic):]))
+
+	var ccs constraint.ConstraintSystem
+	switch curve {
+	case ecc.BN254:
+		ccs = new(cs_bn254.R1CS)
+	case ecc.BLS12_377:
+		ccs = new(cs_bls12377.R1CS)
+	case ecc.BLS12_381:
+		ccs = new(cs_bls12381.R1CS)
+	case ecc.BW6_761:
+		ccs = new(cs_bw6761.R1CS)
+	case ecc.BLS24_317:
+		ccs = new(cs_bls24317.R1CS)
+	case ecc.BLS24_315:
+		ccs = new(cs_bls24315.R1CS)
+	case ecc.BW6_633:
+		ccs = new(cs_bw6633.R1CS)
+	default:
+		return nil, fmt.Errorf("unsupported curve %s", curve)
+	}
+	if _, err := ccs.ReadFrom(r); err != nil {
+		return nil, fmt.Errorf("reading constraint system: %w", err)
+	}
+	if ccs.GetSystem().Type != constraint.SystemR1CS {
+		return nil, ErrUnsupported
+	}
+	return &Program{Curve: curve, System: ccs}, nil
+}
+
+// Hints returns the names of the hints the program depends on, sorted.
+func (p *Program) Hints() []string {
+	hints := make([]string, 0, len(p.System.GetSystem().MHintsDependencies))
+	for _, name := range p.System.GetSystem().MHintsDependencies {
+		hints = append(hints, name)
+	}
+	sort.Strings(hints)
+	return hints
+}
+
+// MissingHints returns the names of the hints the program depends on which are neither
+// registered nor given in the solver options, sorted.
+func (p *Program) MissingHints(opts ...solver.Option) ([]string, error) {
+	config, err := solver.NewConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	for id, name := range p.System.GetSystem().MHintsDependencies {
+		if _, ok := config.HintFunctions[id]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	return missing, nil
+}
+
+// NewWitness returns an empty witness for the field of the program, to be read from the
+// binary encoding of a full witness (see witness.Witness).
+func (p *Program) NewWitness() (witness.Witness, error) {
+	return witness.New(p.System.Field())
+}
+
+// Solve solves the program for the full witness w and returns the values of all the wires,
+// starting with the constant "1", followed by the public, secret and internal wires.
+func (p *Program) Solve(w witness.Witness, opts ...solver.Option) ([]*big.Int, error) {
+	if p.System.GetSystem().Type != constraint.SystemR1CS {
+		return nil, ErrUnsupported
+	}
+	solution, err := p.System.Solve(w, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		n     int
+		value func(i int, res *big.Int)
+	)
+	switch s := solution.(type) {
+	case *cs_bn254.R1CSSolution:
+		n, value = len(s.W), func(i int, res *big.Int) { s.W[i].BigInt(res) }
+	case *cs_bls12377.R1CSSolution:
+		n, value = len(s.W), func(i int, res *big.Int) { s.W[i].BigInt(res) }
+	case *cs_bls12381.R1CSSolution:
+		n, value = len(s.W), func(i int, res *big.Int) { s.W[i].BigInt(res) }
+	case *cs_bw6761.R1CSSolution:
+		n, value = len(s.W), func(i int, res *big.Int) { s.W[i].BigInt(res) }
+	case *cs_bls24317.R1CSSolution:
+		n, value = len(s.W), func(i int, res *big.Int) { s.W[i].BigInt(res) }
+	case *cs_bls24315.R1CSSolution:
+		n, value = len(s.W), func(i int, res *big.Int) { s.W[i].BigInt(res) }
+	case *cs_bw6633.R1CSSolution:
+		n, value = len(s.W), func(i int, res *big.Int) { s.W[i].BigInt(res) }
+	default:
+		return nil, fmt.Errorf("unexpected solution type %T", solution)
+	}
+	values := make([]*big.Int, n)
+	for i := range values {
+		values[i] = new(big.Int)
+		value(i, values[i])
+	}
+	return values, nil
+}
+
+// WriteAssignment writes the values returned by Solve in the format of
+// export_utils.SerializeAssignment: CBOR encoded little-endian 64-bit words, for fields of
+// at most 256 bits.
+func (p *Program) WriteAssignment(w io.Writer, values []*big.Int) error {
+	var maxWords export_utils.Element
+	if p.System.Field().BitLen() > 64*len(maxWords) {
+		return fmt.Errorf("the assignment format supports fields of at most %d bits", 64*len(maxWords))
+	}
+	assignment := export_utils.AssignmentRaw{
+		Variables:       make([]export_utils.Element, len(values)),
+		NumPublicInputs: uint(p.System.GetNbPublicVariables()),
+	}
+	buf := make([]byte, 8*len(maxWords))
+	for i, v := range values {
+		v.FillBytes(buf)
+		for j := range assignment.Variables[i] {
+			assignment.Variables[i][j] = binary.BigEndian.Uint64(buf[len(buf)-8*(j+1):])
+		}
+	}
+	return export_utils.WriteAssignment(w, &assignment)
+}
+
+func curveOf(field *big.Int) (ecc.ID, error) {
+	for _, curve := range ecc.Implemented() {
+		if curve.ScalarField().Cmp(field) == 0 {
+			return curve, nil
+		}
+	}
+	return ecc.UNKNOWN, fmt.Errorf("no curve with scalar field %s", field.String())
+}
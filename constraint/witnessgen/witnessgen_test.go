@@ -0,0 +1,104 @@
+package witnessgen_test
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	cs "github.com/consensys/gnark/constraint/bls12-377"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/constraint/witnessgen"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/utils/export_utils"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+)
+
+type circuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *circuit) Define(api frontend.API) error {
+	api.Println("x", c.X)
+	b := bits.ToBinary(api, c.X, bits.WithNbDigits(8))
+	api.AssertIsEqual(api.Mul(bits.FromBinary(api, b), b[0]), c.Y)
+	return nil
+}
+
+func TestExport(t *testing.T) {
+	assert := require.New(t)
+	field := ecc.BLS12_377.ScalarField()
+
+	ccs, err := frontend.Compile(field, r1cs.NewBuilder, &circuit{})
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&circuit{X: 201, Y: 201}, field)
+	assert.NoError(err)
+
+	var program bytes.Buffer
+	assert.NoError(witnessgen.Export(&program, ccs))
+
+	p, err := witnessgen.Read(bytes.NewReader(program.Bytes()))
+	assert.NoError(err)
+	assert.Equal(ecc.BLS12_377, p.Curve)
+	assert.Equal([]string{solver.GetHintName(bits.GetHints()[1])}, p.Hints())
+	// the logs and the debug information aren't exported
+	assert.NotEmpty(ccs.GetSystem().Logs)
+	assert.Empty(p.System.GetSystem().Logs)
+	assert.Empty(p.System.GetSystem().DebugInfo)
+	missing, err := p.MissingHints()
+	assert.NoError(err)
+	assert.Empty(missing)
+
+	// the witness is read from its binary encoding, as a runner would
+	data, err := w.MarshalBinary()
+	assert.NoError(err)
+	rw, err := p.NewWitness()
+	assert.NoError(err)
+	assert.NoError(rw.UnmarshalBinary(data))
+
+	values, err := p.Solve(rw)
+	assert.NoError(err)
+	solution, err := ccs.Solve(w)
+	assert.NoError(err)
+	expected := solution.(*cs.R1CSSolution)
+	assert.Equal(len(expected.W), len(values))
+	for i := range values {
+		var v big.Int
+		expected.W[i].BigInt(&v)
+		assert.Equal(0, v.Cmp(values[i]), "wire %d", i)
+	}
+
+	// the assignment matches export_utils.SerializeAssignment
+	var buf bytes.Buffer
+	assert.NoError(p.WriteAssignment(&buf, values))
+	var assignment export_utils.AssignmentRaw
+	assert.NoError(cbor.Unmarshal(buf.Bytes(), &assignment))
+	assert.Equal(uint(ccs.GetNbPublicVariables()), assignment.NumPublicInputs)
+	for i := range expected.W {
+		assert.Equal(export_utils.Element(expected.W[i].Bits()), assignment.Variables[i])
+	}
+
+	// wrong witness
+	w, err = frontend.NewWitness(&circuit{X: 201, Y: 200}, field)
+	assert.NoError(err)
+	_, err = p.Solve(w)
+	assert.Error(err)
+}
+
+func TestExportErrors(t *testing.T) {
+	assert := require.New(t)
+
+	_, err := witnessgen.Read(bytes.NewReader([]byte("not a program")))
+	assert.Error(err)
+
+	// PLONK constraint systems aren't supported
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &circuit{})
+	assert.NoError(err)
+	var program bytes.Buffer
+	assert.ErrorIs(witnessgen.Export(&program, ccs), witnessgen.ErrUnsupported)
+}
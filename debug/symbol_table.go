@@ -111,3 +111,40 @@ func (st *SymbolTable) locationID(frame *runtime.Frame) int {
 
 	return lID
 }
+
+// Import adds the locations of other, and their functions, to st. It returns the ids in st of
+// the locations of other.
+func (st *SymbolTable) Import(other *SymbolTable) []int {
+	if st.mFunctions == nil {
+		st.mFunctions = map[string]int{}
+	}
+	if st.mLocations == nil {
+		st.mLocations = map[uint64]int{}
+	}
+	pcs := make(map[int]uint64, len(other.mLocations))
+	for pc, lID := range other.mLocations {
+		pcs[lID] = pc
+	}
+
+	ids := make([]int, len(other.Locations))
+	for i, l := range other.Locations {
+		pc, hasPC := pcs[i]
+		if lID, ok := st.mLocations[pc]; hasPC && ok {
+			ids[i] = lID
+			continue
+		}
+		f := other.Functions[l.FunctionID]
+		fID, ok := st.mFunctions[f.Filename+f.SystemName]
+		if !ok {
+			st.Functions = append(st.Functions, f)
+			fID = len(st.Functions) - 1
+			st.mFunctions[f.Filename+f.SystemName] = fID
+		}
+		st.Locations = append(st.Locations, Location{FunctionID: fID, Line: l.Line})
+		ids[i] = len(st.Locations) - 1
+		if hasPC {
+			st.mLocations[pc] = ids[i]
+		}
+	}
+	return ids
+}
@@ -0,0 +1,68 @@
+package frontend
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Budget defines upper bounds on the size of the constraint system built by Compile. A zero
+// value for a field means no limit. See [WithBudget].
+type Budget struct {
+	// MaxConstraints bounds the number of constraints.
+	MaxConstraints int
+
+	// MaxWires bounds the number of wires (public, secret and internal).
+	MaxWires int
+
+	// MaxHints bounds the number of hint calls.
+	MaxHints int
+
+	// MaxLookupRows bounds the number of lookup queries: outputs of lookup instructions
+	// (see std/lookup/logderivlookup) and limbs looked up by the Varuna range checker
+	// (see std/rangecheck/varuna).
+	MaxLookupRows int
+}
+
+// IsSet returns true if at least one of the budget limits is set.
+func (b Budget) IsSet() bool {
+	return b.MaxConstraints > 0 || b.MaxWires > 0 || b.MaxHints > 0 || b.MaxLookupRows > 0
+}
+
+// BudgetError is returned by Compile when the circuit exceeds the compile budget.
+type BudgetError struct {
+	// Resource is the exceeded resource ("constraints", "wires", "hints" or "lookup rows").
+	Resource string
+
+	// Limit is the budget limit for the resource, Count the number reached when the
+	// compilation was aborted.
+	Limit, Count int
+
+	// Top lists the call stacks contributing the most constraints up to the abort point,
+	// in pprof top format (see package profile).
+	Top string
+}
+
+func (e *BudgetError) Error() string {
+	var sbb strings.Builder
+	sbb.WriteString(fmt.Sprintf("compile budget exceeded: %d %s > %d", e.Count, e.Resource, e.Limit))
+	if e.Top != "" {
+		sbb.WriteString("\ntop contributors (constraints):\n")
+		sbb.WriteString(e.Top)
+	}
+	return sbb.String()
+}
+
+// WithBudget is a compile option that aborts the compilation as soon as the constraint system
+// exceeds one of the limits of budget. In that case, Compile returns a *BudgetError which
+// includes the call stacks contributing the most constraints.
+//
+// Enabling a budget profiles the compilation (see package profile) which slows it down.
+func WithBudget(budget Budget) CompileOption {
+	return func(opt *CompileConfig) error {
+		if budget.MaxConstraints < 0 || budget.MaxWires < 0 || budget.MaxHints < 0 || budget.MaxLookupRows < 0 {
+			return fmt.Errorf("budget limits must be positive")
+		}
+		opt.Budget = budget
+		return nil
+	}
+}
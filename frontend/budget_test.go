@@ -0,0 +1,99 @@
+package frontend_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/rangecheck"
+	"github.com/stretchr/testify/require"
+)
+
+type budgetCircuit struct {
+	X [10]frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *budgetCircuit) Define(api frontend.API) error {
+	acc := c.X[0]
+	for i := 1; i < len(c.X); i++ {
+		acc = api.Mul(acc, c.X[i])
+	}
+	api.AssertIsEqual(acc, c.Y)
+	api.ToBinary(c.Y, 8)
+	api.ToBinary(c.X[0], 8)
+
+	t := logderivlookup.New(api)
+	for i := 0; i < 4; i++ {
+		t.Insert(i)
+	}
+	t.Lookup(0, 1, 2)
+	return nil
+}
+
+func TestCompileBudget(t *testing.T) {
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		// a large budget doesn't change the compiled system
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &budgetCircuit{})
+		require.NoError(t, err)
+		ccsBudget, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &budgetCircuit{}, frontend.WithBudget(frontend.Budget{
+			MaxConstraints: 1 << 20,
+			MaxWires:       1 << 20,
+			MaxHints:       1 << 20,
+			MaxLookupRows:  1 << 20,
+		}))
+		require.NoError(t, err)
+		require.Equal(t, ccs.GetNbConstraints(), ccsBudget.GetNbConstraints())
+		require.Equal(t, ccs.GetNbInternalVariables(), ccsBudget.GetNbInternalVariables())
+
+		for _, tc := range []struct {
+			budget   frontend.Budget
+			resource string
+		}{
+			{frontend.Budget{MaxConstraints: 5}, "constraints"},
+			{frontend.Budget{MaxWires: 8}, "wires"},
+			{frontend.Budget{MaxHints: 1}, "hints"},
+			{frontend.Budget{MaxLookupRows: 2}, "lookup rows"},
+		} {
+			_, err = frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &budgetCircuit{}, frontend.WithBudget(tc.budget))
+			var budgetErr *frontend.BudgetError
+			require.True(t, errors.As(err, &budgetErr), "expected a budget error, got %v", err)
+			require.Equal(t, tc.resource, budgetErr.Resource)
+			require.Greater(t, budgetErr.Count, budgetErr.Limit)
+			if tc.resource == "constraints" {
+				require.Contains(t, budgetErr.Top, "budgetCircuit")
+			}
+		}
+	}
+
+	_, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &budgetCircuit{}, frontend.WithBudget(frontend.Budget{MaxHints: -1}))
+	require.Error(t, err)
+}
+
+type rangeCheckCircuit struct {
+	X [4]frontend.Variable
+}
+
+func (c *rangeCheckCircuit) Define(api frontend.API) error {
+	rc := rangecheck.New(api)
+	for i := range c.X {
+		rc.Check(c.X[i], 64)
+	}
+	return nil
+}
+
+func TestCompileBudgetVaruna(t *testing.T) {
+	t.Setenv("DISABLE_VARUNA_RANGE_CHECK_METHODS", "")
+
+	_, err := frontend.Compile(ecc.BLS12_377.ScalarField(), r1cs.NewBuilder, &rangeCheckCircuit{}, frontend.WithBudget(frontend.Budget{MaxLookupRows: 1 << 10}))
+	require.NoError(t, err)
+
+	_, err = frontend.Compile(ecc.BLS12_377.ScalarField(), r1cs.NewBuilder, &rangeCheckCircuit{}, frontend.WithBudget(frontend.Budget{MaxLookupRows: 4}))
+	var budgetErr *frontend.BudgetError
+	require.True(t, errors.As(err, &budgetErr), "expected a budget error, got %v", err)
+	require.Equal(t, "lookup rows", budgetErr.Resource)
+}
@@ -68,6 +68,10 @@ type Compiler interface {
 	SetGkrInfo(constraint.GkrInfo) error
 }
 
+// Section is an independent section of a circuit traced by Parallelizer.Parallel. It returns the
+// variables used by the rest of the circuit.
+type Section func(api API) ([]Variable, error)
+
 // Builder represents a constraint system builder
 type Builder interface {
 	API
@@ -101,6 +105,87 @@ type Rangechecker interface {
 	Check(v Variable, bits int)
 }
 
+// Labeler allows to label variables, so that the values of the corresponding wires can be
+// provided to the solver by name (see [solver.WithAssignedLabels]). Not all compilers
+// implement this interface.
+type Labeler interface {
+	// Label labels the variable v, which must not be a constant, and returns the labelled
+	// variable. If v is not an internal wire, a new internal wire constrained to be equal
+	// to v is labelled and returned instead; circuits should then use the returned
+	// variable in place of v. In a scope (see Scoper), the label is prefixed with
+	// the path of the scope.
+	Label(v Variable, label string) (Variable, error)
+}
+
+// Scoper allows to group the constraints of a circuit in named scopes. Not all compilers
+// implement this interface.
+type Scoper interface {
+	// Scope opens the scope name, nested in the current scope, and returns a function
+	// closing it:
+	//
+	//	if scoper, ok := api.Compiler().(frontend.Scoper); ok {
+	//		defer scoper.Scope("sha256/round 3")()
+	//	}
+	//
+	// The constraints, the logs and the wire labels (see Labeler) created in the scope are
+	// recorded in the constraint system under the "/"-separated path of the scope, which is
+	// reported in the solver errors, the logs and the profiles.
+	Scope(name string) (closeScope func())
+}
+
+// Conditioner allows to gate the assertions of a circuit by a condition. Not all compilers
+// implement this interface; gadgets whose assertions should be gated (e.g.
+// [github.com/consensys/gnark/std/rangecheck]) check for it.
+type Conditioner interface {
+	// When opens a conditional scope on the boolean cond and returns a function closing it:
+	//
+	//	defer api.Compiler().(frontend.Conditioner).When(isDeposit)()
+	//
+	// Inside the scope, AssertIsEqual, AssertIsDifferent, AssertIsBoolean,
+	// AssertIsLessOrEqual and the range checks of std/rangecheck only hold when cond is 1,
+	// and are trivially satisfied when cond is 0. cond is constrained to be boolean. Nested
+	// scopes are gated by the conjunction of their conditions.
+	//
+	// Only the assertions are gated: the other constraints (Inverse, Div, the checks of the
+	// hints...) must hold whatever the condition, and the values computed inside the scope
+	// should not be relied upon outside of it when cond is 0.
+	When(cond Variable) (closeScope func())
+
+	// Condition returns the condition gating the assertions in the current conditional
+	// scope (see When), and false outside of any conditional scope.
+	Condition() (cond Variable, ok bool)
+}
+
+// Parallelizer allows to trace independent sections of a circuit concurrently. Not all
+// compilers implement this interface; circuits can fall back to calling the sections one
+// after the other.
+type Parallelizer interface {
+	// Parallel traces the independent sections concurrently and adds their constraints to
+	// the circuit, in order, as if the sections were called one after the other. It returns
+	// the outputs of each section.
+	//
+	// Each section is traced on a sub-builder, on which it may use the variables defined
+	// before the call; the variables it creates must only leave the section through its
+	// outputs. In particular, the gadgets keeping a state, such as std/rangecheck or
+	// std/math/emulated, must be instantiated in the section with its api. The calls
+	// deferred in a section (see Defer) are made at the end of the section, so that the
+	// checks batched by such gadgets are batched per section.
+	//
+	// The variables marked as boolean in the sections are marked in the builder; the other
+	// caches (the deduplicated constraints) aren't shared. The sections can only add R1C,
+	// sparse R1C and hint instructions: they can't use commitments (the range checker of
+	// std/rangecheck based on commitments), GKR, nor the other blueprints (std/lookup).
+	Parallel(sections ...Section) ([][]Variable, error)
+}
+
+// LookupRowsRecorder is implemented by builders enforcing a compile budget (see [WithBudget]).
+// Gadgets which create lookup rows outside of the constraint system (for example the Varuna
+// range checker) record them so that the builder can abort when the budget is exceeded.
+type LookupRowsRecorder interface {
+	// RecordLookupRows records nbRows new lookup rows.
+	RecordLookupRows(nbRows int)
+}
+
 // CanonicalVariable represents a variable that's encoded in a constraint system specific way.
 // For example a R1CS builder may represent this as a constraint.LinearExpression,
 // a PLONK builder --> constraint.Term
@@ -11,6 +11,7 @@ import (
 	"github.com/consensys/gnark/frontend/schema"
 	"github.com/consensys/gnark/internal/circuitdefer"
 	"github.com/consensys/gnark/logger"
+	"github.com/consensys/gnark/profile"
 )
 
 // Compile will generate a ConstraintSystem from the given circuit
@@ -33,7 +34,7 @@ import (
 //
 // initialCapacity is an optional parameter that reserves memory in slices
 // it should be set to the estimated number of constraints in the circuit, if known.
-func Compile(field *big.Int, newBuilder NewBuilder, circuit Circuit, opts ...CompileOption) (constraint.ConstraintSystem, error) {
+func Compile(field *big.Int, newBuilder NewBuilder, circuit Circuit, opts ...CompileOption) (_ constraint.ConstraintSystem, err error) {
 	log := logger.Logger()
 	log.Info().Msg("compiling circuit")
 	// parse options
@@ -45,6 +46,19 @@ func Compile(field *big.Int, newBuilder NewBuilder, circuit Circuit, opts ...Com
 		}
 	}
 
+	// when a budget is set, we profile the compilation to report the top contributors if
+	// the budget is exceeded.
+	if opt.Budget.IsSet() {
+		p := profile.Start(profile.WithNoOutput())
+		defer func() {
+			p.Stop()
+			var budgetErr *BudgetError
+			if errors.As(err, &budgetErr) {
+				budgetErr.Top = p.Top()
+			}
+		}()
+	}
+
 	// instantiate new builder
 	builder, err := newBuilder(field, opt)
 	if err != nil {
@@ -115,6 +129,11 @@ func parseCircuit(builder Builder, circuit Circuit) (err error) {
 	// recover from panics to print user-friendlier messages
 	defer func() {
 		if r := recover(); r != nil {
+			if budgetErr, ok := r.(*BudgetError); ok {
+				// the builder aborted the compilation, no need for the stack.
+				err = budgetErr
+				return
+			}
 			err = fmt.Errorf("%v\n%s", r, debug.Stack())
 		}
 	}()
@@ -154,6 +173,7 @@ type CompileConfig struct {
 	Capacity                  int
 	IgnoreUnconstrainedInputs bool
 	CompressThreshold         int
+	Budget                    Budget
 }
 
 // WithCapacity is a compile option that specifies the estimated capacity needed
@@ -0,0 +1,55 @@
+package frontend_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/rangecheck"
+	"github.com/consensys/gnark/test"
+)
+
+// conditionalCircuit checks X only when Enabled is 1, and Y only when both Enabled and
+// Nested are 1.
+type conditionalCircuit struct {
+	Enabled, Nested frontend.Variable
+	X, Y            frontend.Variable
+	Expected        frontend.Variable `gnark:",public"`
+}
+
+func (c *conditionalCircuit) Define(api frontend.API) error {
+	rc := rangecheck.New(api)
+
+	defer api.Compiler().(frontend.Conditioner).When(c.Enabled)()
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Expected)
+	api.AssertIsDifferent(c.X, 0)
+	api.AssertIsLessOrEqual(c.X, 1000)
+	rc.Check(c.X, 10)
+
+	defer api.Compiler().(frontend.Conditioner).When(c.Nested)()
+	api.AssertIsBoolean(c.Y)
+	api.AssertIsEqual(c.Y, 1)
+	return nil
+}
+
+func TestConditionalScope(t *testing.T) {
+	// the varuna range checker only supports the R1CS builder
+	t.Setenv("DISABLE_VARUNA_RANGE_CHECK_METHODS", "true")
+	assert := test.NewAssert(t)
+
+	circuit := &conditionalCircuit{}
+	assert.ProverSucceeded(circuit, &conditionalCircuit{Enabled: 1, Nested: 1, X: 5, Y: 1, Expected: 25}, test.WithCurves(ecc.BN254))
+	// the assertions are gated when the condition is 0
+	assert.ProverSucceeded(circuit, &conditionalCircuit{Enabled: 0, Nested: 1, X: 0, Y: 5, Expected: 1}, test.WithCurves(ecc.BN254))
+	assert.ProverSucceeded(circuit, &conditionalCircuit{Enabled: 0, Nested: 1, X: 1 << 40, Y: 5, Expected: 1}, test.WithCurves(ecc.BN254))
+	assert.ProverSucceeded(circuit, &conditionalCircuit{Enabled: 1, Nested: 0, X: 5, Y: 5, Expected: 25}, test.WithCurves(ecc.BN254))
+
+	// the assertions hold when the condition is 1
+	assert.ProverFailed(circuit, &conditionalCircuit{Enabled: 1, Nested: 1, X: 5, Y: 1, Expected: 24}, test.WithCurves(ecc.BN254))
+	assert.ProverFailed(circuit, &conditionalCircuit{Enabled: 1, Nested: 0, X: 0, Y: 1, Expected: 0}, test.WithCurves(ecc.BN254))
+	assert.ProverFailed(circuit, &conditionalCircuit{Enabled: 1, Nested: 0, X: 1001, Y: 1, Expected: 1002001}, test.WithCurves(ecc.BN254))
+	assert.ProverFailed(circuit, &conditionalCircuit{Enabled: 1, Nested: 1, X: 5, Y: 2, Expected: 25}, test.WithCurves(ecc.BN254))
+	// the conditions must be boolean
+	assert.ProverFailed(circuit, &conditionalCircuit{Enabled: 2, Nested: 1, X: 0, Y: 1, Expected: 1}, test.WithCurves(ecc.BN254))
+	assert.ProverFailed(circuit, &conditionalCircuit{Enabled: 1, Nested: 2, X: 5, Y: 5, Expected: 25}, test.WithCurves(ecc.BN254))
+}
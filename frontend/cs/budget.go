@@ -0,0 +1,54 @@
+package cs
+
+import (
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+)
+
+// BudgetTracker keeps track of the resources used by a constraint system under construction
+// and aborts the compilation when a frontend.Budget is exceeded.
+type BudgetTracker struct {
+	budget       frontend.Budget
+	nbHints      int
+	nbLookupRows int
+}
+
+// NewBudgetTracker returns a tracker enforcing the given budget.
+func NewBudgetTracker(budget frontend.Budget) *BudgetTracker {
+	return &BudgetTracker{budget: budget}
+}
+
+// AddHint records a hint call.
+func (t *BudgetTracker) AddHint() {
+	t.nbHints++
+}
+
+// AddLookupRows records nbRows lookup rows created outside of the constraint system.
+func (t *BudgetTracker) AddLookupRows(nbRows int) {
+	t.nbLookupRows += nbRows
+}
+
+// AddInstruction records an instruction of the given blueprint creating nbOutputs wires.
+func (t *BudgetTracker) AddInstruction(b constraint.Blueprint, nbOutputs int) {
+	switch b.(type) {
+	case *constraint.BlueprintLookupHint:
+		t.nbLookupRows += nbOutputs
+	case constraint.BlueprintHint:
+		t.nbHints++
+	}
+}
+
+// Check panics with a *frontend.BudgetError if cs exceeds the budget. The panic is recovered
+// by frontend.Compile which returns the error.
+func (t *BudgetTracker) Check(cs constraint.ConstraintSystem) {
+	check := func(resource string, limit, count int) {
+		if limit > 0 && count > limit {
+			panic(&frontend.BudgetError{Resource: resource, Limit: limit, Count: count})
+		}
+	}
+	internal, secret, public := cs.GetNbVariables()
+	check("constraints", t.budget.MaxConstraints, cs.GetNbConstraints())
+	check("wires", t.budget.MaxWires, internal+secret+public)
+	check("hints", t.budget.MaxHints, t.nbHints)
+	check("lookup rows", t.budget.MaxLookupRows, t.nbLookupRows)
+}
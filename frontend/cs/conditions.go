@@ -0,0 +1,44 @@
+package cs
+
+import (
+	"github.com/consensys/gnark/frontend"
+)
+
+// Conditions is the stack of the conditional scopes opened in a builder (see
+// frontend.Conditioner). The zero value has no conditional scope.
+type Conditions struct {
+	stack []frontend.Variable
+}
+
+// Push opens a conditional scope on cond and returns a function closing it. cond is
+// constrained to be boolean, under the condition of the enclosing scope; the condition of
+// the new scope is the conjunction of cond and of the enclosing condition.
+func (c *Conditions) Push(api frontend.API, cond frontend.Variable) (closeScope func()) {
+	api.AssertIsBoolean(cond)
+	if current, ok := c.Current(); ok {
+		cond = api.Mul(current, cond)
+	}
+	c.stack = append(c.stack, cond)
+	n := len(c.stack)
+	return func() {
+		if len(c.stack) != n {
+			panic("conditional scopes must be closed in reverse order of opening")
+		}
+		c.stack = c.stack[:n-1]
+	}
+}
+
+// Current returns the condition of the innermost conditional scope, and false if there is
+// no conditional scope open.
+func (c *Conditions) Current() (cond frontend.Variable, ok bool) {
+	if len(c.stack) == 0 {
+		return nil, false
+	}
+	return c.stack[len(c.stack)-1], true
+}
+
+// Fork returns the conditions of a sub-builder tracing a section in the current conditional
+// scope (see frontend.Parallelizer).
+func (c *Conditions) Fork() Conditions {
+	return Conditions{stack: c.stack[:len(c.stack):len(c.stack)]}
+}
@@ -0,0 +1,68 @@
+package cs
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/circuitdefer"
+)
+
+// TraceSections traces the sections concurrently, each on a sub-builder returned by fork, and
+// returns the sub-builders and the outputs of the sections (see frontend.Parallelizer).
+// The calls deferred in a section are made on its sub-builder once the section returns, as
+// Compile does at the end of the circuit. A panic in a section is returned as an error.
+func TraceSections[B frontend.API](sections []frontend.Section, fork func() B) ([]B, [][]frontend.Variable, error) {
+	builders := make([]B, len(sections))
+	outputs := make([][]frontend.Variable, len(sections))
+	errs := make([]error, len(sections))
+
+	trace := func(i int) {
+		defer func() {
+			if r := recover(); r != nil {
+				errs[i] = fmt.Errorf("%v\n%s", r, debug.Stack())
+			}
+		}()
+		builders[i] = fork()
+		if outputs[i], errs[i] = sections[i](builders[i]); errs[i] != nil {
+			return
+		}
+		// a deferred call may defer other calls
+		for j := 0; j < len(circuitdefer.GetAll[func(frontend.API) error](builders[i])); j++ {
+			if err := circuitdefer.GetAll[func(frontend.API) error](builders[i])[j](builders[i]); err != nil {
+				errs[i] = fmt.Errorf("defer fn %d: %w", j, err)
+				return
+			}
+		}
+	}
+
+	next := make(chan int, len(sections))
+	for i := range sections {
+		next <- i
+	}
+	close(next)
+	nbWorkers := runtime.GOMAXPROCS(0)
+	if nbWorkers > len(sections) {
+		nbWorkers = len(sections)
+	}
+	var wg sync.WaitGroup
+	wg.Add(nbWorkers)
+	for w := 0; w < nbWorkers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				trace(i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, nil, fmt.Errorf("section %d: %w", i, err)
+		}
+	}
+	return builders, outputs, nil
+}
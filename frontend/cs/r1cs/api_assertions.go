@@ -28,6 +28,20 @@ import (
 
 // AssertIsEqual adds an assertion in the constraint builder (i1 == i2)
 func (builder *builder) AssertIsEqual(i1, i2 frontend.Variable) {
+	if cond, ok := builder.conditions.Current(); ok {
+		// encoded cond * (i1 - i2) == 0
+		l := builder.getLinearExpression(builder.toVariable(cond))
+		r := builder.getLinearExpression(builder.Sub(i1, i2))
+
+		cID := builder.cs.AddR1C(builder.newR1C(l, r, builder.cstZero()), builder.genericGate)
+
+		if debug.Debug {
+			debug := builder.newDebugInfo("assertIsEqual", l, " * ", r, " == 0")
+			builder.cs.AttachDebugInfo(debug, []int{cID})
+		}
+		return
+	}
+
 	// encoded 1 * i1 == i2
 	r := builder.getLinearExpression(builder.toVariable(i1))
 	o := builder.getLinearExpression(builder.toVariable(i2))
@@ -46,6 +60,11 @@ func (builder *builder) AssertIsDifferent(i1, i2 frontend.Variable) {
 	if len(s) == 1 && s[0].Coeff.IsZero() {
 		panic("AssertIsDifferent(x,x) will never be satisfied")
 	}
+	if cond, ok := builder.conditions.Current(); ok {
+		// s is replaced by 1 when cond == 0
+		builder.Inverse(builder.Select(cond, s, 1))
+		return
+	}
 
 	builder.Inverse(s)
 }
@@ -55,6 +74,12 @@ func (builder *builder) AssertIsBoolean(i1 frontend.Variable) {
 
 	v := builder.toVariable(i1)
 
+	if _, ok := builder.conditions.Current(); ok && !builder.IsBoolean(v) {
+		// gated v * (1 - v) == 0
+		builder.AssertIsEqual(builder.Mul(v, builder.Sub(builder.cstOne(), v)), 0)
+		return
+	}
+
 	if b, ok := builder.constantValue(v); ok {
 		if !(b.IsZero() || builder.isCstOne(b)) {
 			panic("assertIsBoolean failed: constant is not 0 or 1") // TODO @gbotrel print
@@ -88,6 +113,10 @@ func (builder *builder) AssertIsBoolean(i1 frontend.Variable) {
 // derived from:
 // https://github.com/zcash/zips/blob/main/protocol/protocol.pdf
 func (builder *builder) AssertIsLessOrEqual(v frontend.Variable, bound frontend.Variable) {
+	if cond, ok := builder.conditions.Current(); ok {
+		// v is replaced by 0 when cond == 0
+		v = builder.Mul(cond, v)
+	}
 	cv, vConst := builder.constantValue(v)
 	cb, bConst := builder.constantValue(bound)
 
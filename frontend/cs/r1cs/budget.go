@@ -0,0 +1,51 @@
+package r1cs
+
+import (
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend/cs"
+)
+
+// budgetSystem wraps the constraint system under construction to enforce the compile
+// budget (see frontend.WithBudget).
+type budgetSystem struct {
+	constraint.R1CS
+	tracker *cs.BudgetTracker
+}
+
+func (s *budgetSystem) AddR1C(r1c constraint.R1C, bID constraint.BlueprintID) int {
+	cID := s.R1CS.AddR1C(r1c, bID)
+	s.tracker.Check(s.R1CS)
+	return cID
+}
+
+func (s *budgetSystem) AddInternalVariable() int {
+	vID := s.R1CS.AddInternalVariable()
+	s.tracker.Check(s.R1CS)
+	return vID
+}
+
+func (s *budgetSystem) AddSolverHint(f solver.Hint, id solver.HintID, input []constraint.LinearExpression, nbOutput int) ([]int, error) {
+	internalVariables, err := s.R1CS.AddSolverHint(f, id, input, nbOutput)
+	if err != nil {
+		return nil, err
+	}
+	s.tracker.AddHint()
+	s.tracker.Check(s.R1CS)
+	return internalVariables, nil
+}
+
+// RecordLookupRows implements frontend.LookupRowsRecorder.
+func (builder *builder) RecordLookupRows(nbRows int) {
+	if s, ok := builder.cs.(*budgetSystem); ok {
+		s.tracker.AddLookupRows(nbRows)
+		s.tracker.Check(s.R1CS)
+	}
+}
+
+func (s *budgetSystem) AddInstruction(bID constraint.BlueprintID, calldata []uint32) []uint32 {
+	wires := s.R1CS.AddInstruction(bID, calldata)
+	s.tracker.AddInstruction(s.GetSystem().Blueprints[bID], len(wires))
+	s.tracker.Check(s.R1CS)
+	return wires
+}
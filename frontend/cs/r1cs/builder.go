@@ -18,6 +18,7 @@ package r1cs
 
 import (
 	"errors"
+	"fmt"
 	"math/big"
 	"reflect"
 	"sort"
@@ -26,6 +27,7 @@ import (
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/debug"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs"
 	"github.com/consensys/gnark/frontend/internal/expr"
 	"github.com/consensys/gnark/frontend/schema"
 	"github.com/consensys/gnark/internal/circuitdefer"
@@ -60,6 +62,9 @@ type builder struct {
 	// map for recording boolean constrained variables (to not constrain them twice)
 	mtBooleans map[uint64][]expr.LinearExpression
 
+	// conditions of the open conditional scopes (see When)
+	conditions cs.Conditions
+
 	tOne        constraint.Element
 	eZero, eOne expr.LinearExpression
 	cZero, cOne constraint.LinearExpression
@@ -117,6 +122,10 @@ func newBuilder(field *big.Int, config frontend.CompileConfig) *builder {
 		panic("not implemented")
 	}
 
+	if config.Budget.IsSet() {
+		builder.cs = &budgetSystem{R1CS: builder.cs, tracker: cs.NewBudgetTracker(config.Budget)}
+	}
+
 	builder.tOne = builder.cs.One()
 	builder.cs.AddPublicVariable("1")
 
@@ -221,7 +230,8 @@ func (builder *builder) getLinearExpression(_l interface{}) constraint.LinearExp
 
 // MarkBoolean sets (but do not **constraint**!) v to be boolean
 // This is useful in scenarios where a variable is known to be boolean through a constraint
-// that is not api.AssertIsBoolean. If v is a constant, this is a no-op.
+// that is not api.AssertIsBoolean. If v is a constant, or in a conditional scope (see When),
+// this is a no-op.
 func (builder *builder) MarkBoolean(v frontend.Variable) {
 	if b, ok := builder.constantValue(v); ok {
 		if !(b.IsZero() || builder.isCstOne(b)) {
@@ -229,6 +239,9 @@ func (builder *builder) MarkBoolean(v frontend.Variable) {
 		}
 		return
 	}
+	if _, ok := builder.conditions.Current(); ok {
+		return // the boolean constraints of a conditional scope may not hold
+	}
 	// v is a linear expression
 	l := v.(expr.LinearExpression)
 	sort.Sort(l)
@@ -286,6 +299,9 @@ func (builder *builder) Compile() (constraint.ConstraintSystem, error) {
 		}
 	}
 
+	if s, ok := builder.cs.(*budgetSystem); ok {
+		return s.R1CS, nil
+	}
 	return builder.cs, nil
 }
 
@@ -507,3 +523,91 @@ func (builder *builder) ToCanonicalVariable(in frontend.Variable) frontend.Canon
 		return constraint.LinearExpression{term}
 	}
 }
+
+// Scope implements frontend.Scoper.
+func (builder *builder) Scope(name string) func() {
+	builder.cs.PushScope(name)
+	return builder.cs.PopScope
+}
+
+// When implements frontend.Conditioner.
+func (builder *builder) When(cond frontend.Variable) func() {
+	return builder.conditions.Push(builder, cond)
+}
+
+// Condition implements frontend.Conditioner.
+func (builder *builder) Condition() (frontend.Variable, bool) {
+	return builder.conditions.Current()
+}
+
+// Parallel implements frontend.Parallelizer.
+func (builder *builder) Parallel(sections ...frontend.Section) ([][]frontend.Variable, error) {
+	internal, secret, public := builder.cs.GetNbVariables()
+	base := internal + secret + public
+	children, outputs, err := cs.TraceSections(sections, builder.fork)
+	if err != nil {
+		return nil, err
+	}
+	// the sections are merged in order, so that the result doesn't depend on the scheduling
+	for i, child := range children {
+		offset, err := constraint.Merge(builder.cs, child.cs, base)
+		if err != nil {
+			return nil, fmt.Errorf("section %d: %w", i, err)
+		}
+		for j := range outputs[i] {
+			outputs[i][j] = shiftWires(outputs[i][j], base, offset)
+		}
+		for _, list := range child.mtBooleans {
+			for _, l := range list {
+				builder.MarkBoolean(shiftWires(l, base, offset))
+			}
+		}
+	}
+	return outputs, nil
+}
+
+// fork returns a sub-builder tracing a section of the circuit (see Parallel). The budget is
+// enforced when the section is merged.
+func (builder *builder) fork() *builder {
+	config := builder.config
+	config.Capacity = 0
+	config.Budget = frontend.Budget{}
+	child := newBuilder(builder.Field(), config)
+	child.cs.GetSystem().Fork(builder.cs.GetSystem())
+	child.conditions = builder.conditions.Fork()
+	return child
+}
+
+// shiftWires returns v with the wires from base shifted by offset.
+func shiftWires(v frontend.Variable, base, offset int) frontend.Variable {
+	l, ok := v.(expr.LinearExpression)
+	if !ok {
+		return v // constant
+	}
+	r := make(expr.LinearExpression, len(l))
+	copy(r, l)
+	for i := range r {
+		if r[i].VID >= base {
+			r[i].VID += offset
+		}
+	}
+	return r
+}
+
+// Label implements frontend.Labeler.
+func (builder *builder) Label(v frontend.Variable, label string) (frontend.Variable, error) {
+	if _, isConstant := builder.constantValue(v); isConstant {
+		return nil, fmt.Errorf("label %q: can't label a constant", label)
+	}
+	le := builder.toVariable(v)
+	nbInputs := builder.cs.GetNbPublicVariables() + builder.cs.GetNbSecretVariables()
+	if len(le) != 1 || le[0].VID < nbInputs || !builder.isCstOne(le[0].Coeff) {
+		t := builder.newInternalVariable()
+		builder.cs.AddR1C(builder.newR1C(le, builder.cstOne(), t), builder.genericGate)
+		le = t
+	}
+	if err := builder.cs.AddWireLabel(label, le[0].VID); err != nil {
+		return nil, err
+	}
+	return le, nil
+}
@@ -28,6 +28,10 @@ import (
 
 // AssertIsEqual fails if i1 != i2
 func (builder *builder) AssertIsEqual(i1, i2 frontend.Variable) {
+	if cond, ok := builder.conditions.Current(); ok {
+		// cond * (i1 - i2) == 0
+		i1, i2 = builder.Mul(cond, builder.Sub(i1, i2)), 0
+	}
 
 	c1, i1Constant := builder.constantValue(i1)
 	c2, i2Constant := builder.constantValue(i2)
@@ -91,11 +95,20 @@ func (builder *builder) AssertIsDifferent(i1, i2 frontend.Variable) {
 	} else if t := s.(expr.Term); t.Coeff.IsZero() {
 		panic("AssertIsDifferent(x,x) will never be satisfied")
 	}
+	if cond, ok := builder.conditions.Current(); ok {
+		// s is replaced by 1 when cond == 0
+		s = builder.Select(cond, s, 1)
+	}
 	builder.Inverse(s)
 }
 
 // AssertIsBoolean fails if v != 0 ∥ v != 1
 func (builder *builder) AssertIsBoolean(i1 frontend.Variable) {
+	if _, ok := builder.conditions.Current(); ok && !builder.IsBoolean(i1) {
+		// gated v * (1 - v) == 0
+		builder.AssertIsEqual(builder.Mul(i1, builder.Sub(1, i1)), 0)
+		return
+	}
 	if c, ok := builder.constantValue(i1); ok {
 		if !(c.IsZero() || builder.cs.IsOne(c)) {
 			panic(fmt.Sprintf("assertIsBoolean failed: constant(%s)", builder.cs.String(c)))
@@ -130,6 +143,10 @@ func (builder *builder) AssertIsBoolean(i1 frontend.Variable) {
 
 // AssertIsLessOrEqual fails if  v > bound
 func (builder *builder) AssertIsLessOrEqual(v frontend.Variable, bound frontend.Variable) {
+	if cond, ok := builder.conditions.Current(); ok {
+		// v is replaced by 0 when cond == 0
+		v = builder.Mul(cond, v)
+	}
 	cv, vConst := builder.constantValue(v)
 	cb, bConst := builder.constantValue(bound)
 
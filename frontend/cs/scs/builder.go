@@ -17,6 +17,7 @@ limitations under the License.
 package scs
 
 import (
+	"fmt"
 	"math/big"
 	"reflect"
 	"sort"
@@ -25,6 +26,7 @@ import (
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/debug"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs"
 	"github.com/consensys/gnark/frontend/internal/expr"
 	"github.com/consensys/gnark/frontend/schema"
 	"github.com/consensys/gnark/internal/circuitdefer"
@@ -56,6 +58,9 @@ type builder struct {
 	// map for recording boolean constrained variables (to not constrain them twice)
 	mtBooleans map[expr.Term]struct{}
 
+	// conditions of the open conditional scopes (see When)
+	conditions cs.Conditions
+
 	// records multiplications constraint to avoid duplicate.
 	// see mulConstraintExist(...)
 	mMulInstructions map[uint64]int
@@ -114,6 +119,10 @@ func newBuilder(field *big.Int, config frontend.CompileConfig) *builder {
 		panic("not implemented")
 	}
 
+	if config.Budget.IsSet() {
+		b.cs = &budgetSystem{SparseR1CS: b.cs, tracker: cs.NewBudgetTracker(config.Budget)}
+	}
+
 	b.tOne = b.cs.One()
 	b.tMinusOne = b.cs.FromInterface(-1)
 
@@ -266,7 +275,8 @@ func (builder *builder) IsBoolean(v frontend.Variable) bool {
 
 // MarkBoolean sets (but do not constraint!) v to be boolean
 // This is useful in scenarios where a variable is known to be boolean through a constraint
-// that is not api.AssertIsBoolean. If v is a constant, this is a no-op.
+// that is not api.AssertIsBoolean. If v is a constant, or in a conditional scope (see When),
+// this is a no-op.
 func (builder *builder) MarkBoolean(v frontend.Variable) {
 	if _, ok := builder.constantValue(v); ok {
 		if !builder.IsBoolean(v) {
@@ -274,6 +284,9 @@ func (builder *builder) MarkBoolean(v frontend.Variable) {
 		}
 		return
 	}
+	if _, ok := builder.conditions.Current(); ok {
+		return // the boolean constraints of a conditional scope may not hold
+	}
 	builder.mtBooleans[v.(expr.Term)] = struct{}{}
 }
 
@@ -298,6 +311,9 @@ func (builder *builder) Compile() (constraint.ConstraintSystem, error) {
 		}
 	}
 
+	if s, ok := builder.cs.(*budgetSystem); ok {
+		return s.SparseR1CS, nil
+	}
 	return builder.cs, nil
 }
 
@@ -690,3 +706,90 @@ func (builder *builder) ToCanonicalVariable(v frontend.Variable) frontend.Canoni
 		return term
 	}
 }
+
+// Scope implements frontend.Scoper.
+func (builder *builder) Scope(name string) func() {
+	builder.cs.PushScope(name)
+	return builder.cs.PopScope
+}
+
+// When implements frontend.Conditioner.
+func (builder *builder) When(cond frontend.Variable) func() {
+	return builder.conditions.Push(builder, cond)
+}
+
+// Condition implements frontend.Conditioner.
+func (builder *builder) Condition() (frontend.Variable, bool) {
+	return builder.conditions.Current()
+}
+
+// Parallel implements frontend.Parallelizer.
+func (builder *builder) Parallel(sections ...frontend.Section) ([][]frontend.Variable, error) {
+	internal, secret, public := builder.cs.GetNbVariables()
+	base := internal + secret + public
+	children, outputs, err := cs.TraceSections(sections, builder.fork)
+	if err != nil {
+		return nil, err
+	}
+	// the sections are merged in order, so that the result doesn't depend on the scheduling
+	for i, child := range children {
+		offset, err := constraint.Merge(builder.cs, child.cs, base)
+		if err != nil {
+			return nil, fmt.Errorf("section %d: %w", i, err)
+		}
+		for j := range outputs[i] {
+			outputs[i][j] = shiftWires(outputs[i][j], base, offset)
+		}
+		for t := range child.mtBooleans {
+			builder.MarkBoolean(shiftWires(t, base, offset))
+		}
+	}
+	return outputs, nil
+}
+
+// fork returns a sub-builder tracing a section of the circuit (see Parallel). The budget is
+// enforced when the section is merged.
+func (builder *builder) fork() *builder {
+	config := builder.config
+	config.Capacity = 0
+	config.Budget = frontend.Budget{}
+	child := newBuilder(builder.Field(), config)
+	child.cs.GetSystem().Fork(builder.cs.GetSystem())
+	child.conditions = builder.conditions.Fork()
+	return child
+}
+
+// shiftWires returns v with the wires from base shifted by offset.
+func shiftWires(v frontend.Variable, base, offset int) frontend.Variable {
+	t, ok := v.(expr.Term)
+	if !ok {
+		return v // constant
+	}
+	if t.VID >= base {
+		t.VID += offset
+	}
+	return t
+}
+
+// Label implements frontend.Labeler.
+func (builder *builder) Label(v frontend.Variable, label string) (frontend.Variable, error) {
+	if _, isConstant := builder.constantValue(v); isConstant {
+		return nil, fmt.Errorf("label %q: can't label a constant", label)
+	}
+	t := v.(expr.Term)
+	nbInputs := builder.cs.GetNbPublicVariables() + builder.cs.GetNbSecretVariables()
+	if t.VID < nbInputs || !builder.cs.IsOne(t.Coeff) {
+		r := builder.newInternalVariable()
+		builder.addPlonkConstraint(sparseR1C{
+			xa: t.VID,
+			xc: r.VID,
+			qL: t.Coeff,
+			qO: builder.tMinusOne,
+		})
+		t = r
+	}
+	if err := builder.cs.AddWireLabel(label, t.VID); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
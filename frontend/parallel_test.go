@@ -0,0 +1,274 @@
+package frontend_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/debug"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/std/rangecheck"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+const nbSections = 16
+
+type parallelCircuit struct {
+	X   [nbSections]frontend.Variable
+	Sum frontend.Variable `gnark:",public"`
+
+	sequential bool
+}
+
+// section computes the low byte of x⁵ and checks that it is odd.
+func section(i int, x frontend.Variable) frontend.Section {
+	return func(api frontend.API) ([]frontend.Variable, error) {
+		defer api.Compiler().(frontend.Scoper).Scope(fmt.Sprintf("section %d", i))()
+		x5 := api.Mul(x, x, x, x, x)
+		b := bits.ToBinary(api, x5, bits.WithNbDigits(api.Compiler().FieldBitLen()))
+		api.AssertIsEqual(b[0], 1)
+		low := bits.FromBinary(api, b[:8])
+		api.Println("low byte", low)
+		if labeler, ok := api.Compiler().(frontend.Labeler); ok {
+			var err error
+			if low, err = labeler.Label(low, "low"); err != nil {
+				return nil, err
+			}
+		}
+		return []frontend.Variable{low, x5}, nil
+	}
+}
+
+func (c *parallelCircuit) Define(api frontend.API) error {
+	sections := make([]frontend.Section, len(c.X))
+	for i := range c.X {
+		sections[i] = section(i, c.X[i])
+	}
+
+	outputs := make([][]frontend.Variable, len(sections))
+	if c.sequential {
+		for i := range sections {
+			var err error
+			if outputs[i], err = sections[i](api); err != nil {
+				return err
+			}
+		}
+	} else {
+		var err error
+		if outputs, err = api.Compiler().(frontend.Parallelizer).Parallel(sections...); err != nil {
+			return err
+		}
+	}
+
+	sum := frontend.Variable(0)
+	for i := range outputs {
+		sum = api.Add(sum, outputs[i][0])
+		api.AssertIsDifferent(outputs[i][1], 0)
+	}
+	api.AssertIsEqual(sum, c.Sum)
+	return nil
+}
+
+func parallelWitness() *parallelCircuit {
+	var assignment parallelCircuit
+	sum := 0
+	for i := range assignment.X {
+		x := 2*i + 3
+		assignment.X[i] = x
+		sum += (x * x * x * x * x) & 0xff
+	}
+	assignment.Sum = sum
+	return &assignment
+}
+
+func TestParallel(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	assert.ProverSucceeded(&parallelCircuit{}, parallelWitness(), test.WithCurves(ecc.BN254))
+
+	wrong := parallelWitness()
+	wrong.X[3] = 4 // x⁵ is even
+	assert.ProverFailed(&parallelCircuit{}, wrong, test.WithCurves(ecc.BN254))
+}
+
+func TestParallelEqualsSequential(t *testing.T) {
+	if debug.Debug {
+		t.Skip("the stacks of the debug information are collected in the goroutines tracing the sections")
+	}
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		assert := require.New(t)
+
+		parallel, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &parallelCircuit{})
+		assert.NoError(err)
+		sequential, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &parallelCircuit{sequential: true})
+		assert.NoError(err)
+
+		var bParallel, bSequential bytes.Buffer
+		_, err = parallel.WriteTo(&bParallel)
+		assert.NoError(err)
+		_, err = sequential.WriteTo(&bSequential)
+		assert.NoError(err)
+		assert.True(bytes.Equal(bParallel.Bytes(), bSequential.Bytes()), "the parallel and sequential compilations differ")
+
+		_, ok := parallel.GetLabeledWire("section 3/low")
+		assert.True(ok)
+		assert.Equal("section 5", parallel.GetSystem().Logs[5].Scope)
+
+		w, err := frontend.NewWitness(parallelWitness(), ecc.BN254.ScalarField())
+		assert.NoError(err)
+		_, err = parallel.Solve(w)
+		assert.NoError(err)
+	}
+}
+
+type failingSectionCircuit struct {
+	X frontend.Variable
+}
+
+var errSection = errors.New("section failed")
+
+func (c *failingSectionCircuit) Define(api frontend.API) error {
+	_, err := api.Compiler().(frontend.Parallelizer).Parallel(
+		func(api frontend.API) ([]frontend.Variable, error) {
+			return []frontend.Variable{api.Mul(c.X, c.X)}, nil
+		},
+		func(api frontend.API) ([]frontend.Variable, error) {
+			return nil, errSection
+		},
+	)
+	return err
+}
+
+func TestParallelError(t *testing.T) {
+	assert := require.New(t)
+
+	_, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &failingSectionCircuit{})
+	assert.ErrorIs(err, errSection)
+	assert.ErrorContains(err, "section 1")
+}
+
+const nbEmulatedSections = 4
+
+type emulatedSectionsCircuit struct {
+	A, B, C    [nbEmulatedSections]emulated.Element[emparams.Secp256k1Fp]
+	X          [nbEmulatedSections]frontend.Variable
+	Sum        frontend.Variable `gnark:",public"`
+	sequential bool
+}
+
+// emulatedSection checks that AB = C in the emulated field, and that x fits in 16 bits.
+func (c *emulatedSectionsCircuit) emulatedSection(i int) frontend.Section {
+	return func(api frontend.API) ([]frontend.Variable, error) {
+		f, err := emulated.NewField[emparams.Secp256k1Fp](api)
+		if err != nil {
+			return nil, err
+		}
+		f.AssertIsEqual(f.Mul(&c.A[i], &c.B[i]), &c.C[i])
+		rangecheck.New(api).Check(c.X[i], 16)
+		return []frontend.Variable{api.Mul(c.X[i], c.X[i])}, nil
+	}
+}
+
+func (c *emulatedSectionsCircuit) Define(api frontend.API) error {
+	sections := make([]frontend.Section, nbEmulatedSections)
+	for i := range sections {
+		sections[i] = c.emulatedSection(i)
+	}
+
+	outputs := make([][]frontend.Variable, len(sections))
+	if c.sequential {
+		for i := range sections {
+			var err error
+			if outputs[i], err = sections[i](api); err != nil {
+				return err
+			}
+		}
+	} else {
+		var err error
+		if outputs, err = api.Compiler().(frontend.Parallelizer).Parallel(sections...); err != nil {
+			return err
+		}
+	}
+
+	sum := frontend.Variable(0)
+	for i := range outputs {
+		sum = api.Add(sum, outputs[i][0])
+	}
+	api.AssertIsEqual(sum, c.Sum)
+	return nil
+}
+
+func emulatedSectionsWitness() *emulatedSectionsCircuit {
+	var assignment emulatedSectionsCircuit
+	p := emparams.Secp256k1Fp{}.Modulus()
+	for i := 0; i < nbEmulatedSections; i++ {
+		a := new(big.Int).Sub(p, big.NewInt(int64(i+2)))
+		b := new(big.Int).Lsh(big.NewInt(int64(i+3)), 200)
+		ab := new(big.Int).Mul(a, b)
+		assignment.A[i] = emulated.ValueOf[emparams.Secp256k1Fp](a)
+		assignment.B[i] = emulated.ValueOf[emparams.Secp256k1Fp](b)
+		assignment.C[i] = emulated.ValueOf[emparams.Secp256k1Fp](ab.Mod(ab, p))
+		assignment.X[i] = 1000*i + 7
+	}
+	assignment.setSum()
+	return &assignment
+}
+
+// setSum sets the sum of the squares of X.
+func (c *emulatedSectionsCircuit) setSum() {
+	sum := new(big.Int)
+	for i := range c.X {
+		x := big.NewInt(int64(c.X[i].(int)))
+		sum.Add(sum, x.Mul(x, x))
+	}
+	c.Sum = sum
+}
+
+// The gadgets keeping a state and deferring their checks (the emulated field, the range
+// checker) can be used in the sections: the parallel and sequential compilations accept and
+// reject the same witnesses.
+func TestParallelEmulated(t *testing.T) {
+	if os.Getenv("DISABLE_VARUNA_RANGE_CHECK_METHODS") == "true" {
+		t.Skip("the range checker based on commitments can't be used in sections")
+	}
+	assert := require.New(t)
+
+	// the Varuna range checker only supports R1CS
+	parallel, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &emulatedSectionsCircuit{})
+	assert.NoError(err)
+	sequential, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &emulatedSectionsCircuit{sequential: true})
+	assert.NoError(err)
+
+	wrongProduct := emulatedSectionsWitness()
+	wrongProduct.C[2] = emulated.ValueOf[emparams.Secp256k1Fp](12345)
+	outOfRange := emulatedSectionsWitness()
+	outOfRange.X[1] = 1 << 40 // wider than the limbs of any decomposition of 16 bits
+	outOfRange.setSum()
+
+	for _, c := range []struct {
+		assignment *emulatedSectionsCircuit
+		valid      bool
+	}{{emulatedSectionsWitness(), true}, {wrongProduct, false}, {outOfRange, false}} {
+		w, err := frontend.NewWitness(c.assignment, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		_, errParallel := parallel.Solve(w)
+		_, errSequential := sequential.Solve(w)
+		if c.valid {
+			assert.NoError(errParallel)
+			assert.NoError(errSequential)
+		} else {
+			assert.Error(errParallel)
+			assert.Error(errSequential)
+		}
+	}
+}
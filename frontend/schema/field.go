@@ -25,6 +25,11 @@ type Field struct {
 	Type       FieldType
 	SubFields  []Field // will be set only if it's a struct, or an array of struct
 	ArraySize  int
+
+	// JSONHook and JSONArrayHook are set if the type of the field defines its JSON
+	// representation in witness assignments.
+	JSONHook      JSONHook      `json:"-"`
+	JSONArrayHook JSONArrayHook `json:"-"`
 }
 
 // FieldType represents the type a field is allowed to have in a gnark Schema
@@ -0,0 +1,307 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// JSONHook is implemented by gadget types which define their own JSON representation in
+// witness assignments (see witness.ToJSON and witness.FromJSON) instead of the default one,
+// an object with a value per leaf (for example a hex integer for an emulated element instead
+// of its limbs).
+//
+// The hook is called on a zero value of the type.
+type JSONHook interface {
+	// GnarkMarshalJSON returns the JSON representation of an object, given the values of
+	// its leaves (in schema order).
+	GnarkMarshalJSON(leaves []*big.Int) ([]byte, error)
+
+	// GnarkUnmarshalJSON returns the values of the leaves of the object (in schema order)
+	// represented by data.
+	GnarkUnmarshalJSON(data []byte) ([]*big.Int, error)
+}
+
+// JSONArrayHook is implemented by gadget types which define the JSON representation of arrays
+// and slices of the type (for example a hex string for a slice of bytes). If the type also
+// implements JSONHook, it is used for the elements outside of arrays.
+type JSONArrayHook interface {
+	// GnarkMarshalJSONArray returns the JSON representation of an array of objects, given
+	// the values of their leaves (in schema order).
+	GnarkMarshalJSONArray(leaves []*big.Int) ([]byte, error)
+
+	// GnarkUnmarshalJSONArray returns the values of the leaves of the array of objects
+	// (in schema order) represented by data.
+	GnarkUnmarshalJSONArray(data []byte) ([]*big.Int, error)
+}
+
+var tRawMessage = reflect.TypeOf(json.RawMessage{})
+
+// jsonCodec returns the JSON hook functions of the field, if any.
+func (f *Field) jsonCodec() (marshal func([]*big.Int) ([]byte, error), unmarshal func([]byte) ([]*big.Int, error), ok bool) {
+	if f.JSONHook != nil {
+		return f.JSONHook.GnarkMarshalJSON, f.JSONHook.GnarkUnmarshalJSON, true
+	}
+	if f.Type == Array && len(f.SubFields) == 1 && f.SubFields[0].JSONArrayHook != nil {
+		return f.SubFields[0].JSONArrayHook.GnarkMarshalJSONArray, f.SubFields[0].JSONArrayHook.GnarkUnmarshalJSONArray, true
+	}
+	return nil, nil, false
+}
+
+// leafVisibilities returns the visibility of the leaves of the field, in schema order.
+func (f *Field) leafVisibilities(r []Visibility) []Visibility {
+	visibility := f.Visibility
+	if visibility == Unset {
+		visibility = Secret
+	}
+	switch f.Type {
+	case Leaf:
+		return append(r, visibility)
+	case Struct:
+		for i := range f.SubFields {
+			r = f.SubFields[i].leafVisibilities(r)
+		}
+	case Array:
+		for j := 0; j < f.ArraySize; j++ {
+			if len(f.SubFields) == 0 {
+				r = append(r, visibility)
+			} else {
+				r = f.SubFields[0].leafVisibilities(r)
+			}
+		}
+	}
+	return r
+}
+
+// setJSONHooks records the JSON hooks implemented by value on the field f.
+func setJSONHooks(f *Field, value interface{}) {
+	_, isHook := value.(JSONHook)
+	_, isArrayHook := value.(JSONArrayHook)
+	if !isHook && !isArrayHook {
+		return
+	}
+	// the schema doesn't keep references to the circuit, hooks are called on a zero value.
+	zero := reflect.New(reflect.TypeOf(value).Elem()).Interface()
+	if isHook {
+		f.JSONHook = zero.(JSONHook)
+	}
+	if isArrayHook {
+		f.JSONArrayHook = zero.(JSONArrayHook)
+	}
+}
+
+// jsonHandlers are called by walkJSON on the leaves and the fields with a JSON hook.
+type jsonHandlers struct {
+	leaf func(visibility Visibility, name string, value reflect.Value) error
+	hook func(f *Field, name string, value reflect.Value) error
+}
+
+// walkJSON walks through an instance of the schema built with hooks, in the same order as Walk.
+func walkJSON(fields []Field, parentName string, v reflect.Value, h *jsonHandlers) error {
+	for i := range fields {
+		name := getFullName(parentName, fields[i].Name, fields[i].NameTag)
+		if err := walkJSONField(&fields[i], name, v.Field(i), h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func walkJSONField(f *Field, name string, v reflect.Value, h *jsonHandlers) error {
+	if _, _, ok := f.jsonCodec(); ok {
+		return h.hook(f, name, v)
+	}
+	visibility := f.Visibility
+	if visibility == Unset {
+		visibility = Secret
+	}
+	switch f.Type {
+	case Leaf:
+		return h.leaf(visibility, name, v)
+	case Struct:
+		return walkJSON(f.SubFields, name, v, h)
+	case Array:
+		if v.Kind() == reflect.Slice {
+			return walkJSONSlice(f, name, v, h)
+		}
+		for j := 0; j < f.ArraySize; j++ {
+			elemName := name + "_" + strconv.Itoa(j)
+			var err error
+			if len(f.SubFields) == 0 {
+				err = h.leaf(visibility, elemName, v.Index(j))
+			} else {
+				err = walkJSONField(&f.SubFields[0], elemName, v.Index(j), h)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// walkJSONSlice walks through an array of hooked objects, which is a slice in the instance. A nil
+// slice is walked as an array of empty values, and the slice is set only if one of its elements
+// is.
+func walkJSONSlice(f *Field, name string, v reflect.Value, h *jsonHandlers) error {
+	if !v.IsNil() && v.Len() != f.ArraySize {
+		return fmt.Errorf("%s: expected %d values, got %d", name, f.ArraySize, v.Len())
+	}
+	s := reflect.MakeSlice(v.Type(), f.ArraySize, f.ArraySize)
+	reflect.Copy(s, v)
+	empty := true
+	for j := 0; j < f.ArraySize; j++ {
+		if err := walkJSONField(&f.SubFields[0], name+"_"+strconv.Itoa(j), s.Index(j), h); err != nil {
+			return err
+		}
+		empty = empty && s.Index(j).IsZero()
+	}
+	if !empty {
+		v.Set(s)
+	}
+	return nil
+}
+
+// EncodeJSON returns the JSON encoding of a witness following the schema, using the JSON hooks
+// of the gadgets (see JSONHook). public and secret are the values of the leaves (of type
+// leafType) in witness order; secret may be nil to encode the public part only.
+func (s Schema) EncodeJSON(leafType reflect.Type, public, secret []any, indent bool) ([]byte, error) {
+	if len(public) != s.NbPublic || (secret != nil && len(secret) != s.NbSecret) {
+		return nil, fmt.Errorf("schema expects %d public and %d secret values", s.NbPublic, s.NbSecret)
+	}
+	instance := s.instantiate(leafType, true, true)
+
+	var iPublic, iSecret int
+	next := func(visibility Visibility) (any, bool) {
+		switch {
+		case visibility == Public:
+			iPublic++
+			return public[iPublic-1], true
+		case visibility == Secret && secret != nil:
+			iSecret++
+			return secret[iSecret-1], true
+		}
+		return nil, false
+	}
+
+	h := jsonHandlers{
+		leaf: func(visibility Visibility, _ string, value reflect.Value) error {
+			if v, ok := next(visibility); ok {
+				value.Set(reflect.ValueOf(v))
+			}
+			return nil
+		},
+		hook: func(f *Field, name string, value reflect.Value) error {
+			marshal, _, _ := f.jsonCodec()
+			var leaves []*big.Int
+			for _, visibility := range f.leafVisibilities(nil) {
+				v, ok := next(visibility)
+				if !ok {
+					// hooked objects are encoded only if all their leaves are.
+					return nil
+				}
+				b := utils.FromInterface(v)
+				leaves = append(leaves, &b)
+			}
+			data, err := marshal(leaves)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			value.SetBytes(data)
+			return nil
+		},
+	}
+	if err := walkJSON(s.Fields, "", reflect.ValueOf(instance).Elem(), &h); err != nil {
+		return nil, err
+	}
+
+	if indent {
+		return json.MarshalIndent(instance, "  ", "    ")
+	}
+	return json.Marshal(instance)
+}
+
+// DecodeJSON parses the JSON encoding of a witness following the schema, using the JSON hooks
+// of the gadgets (see JSONHook). It returns the values of the public and secret leaves in witness
+// order; leaves are of type leafType (which must implement json.Unmarshaler) or *big.Int.
+//
+// It returns an error if a public value is missing, and nil secret values if a secret value
+// is missing.
+func (s Schema) DecodeJSON(leafType reflect.Type, data []byte) (public, secret []any, err error) {
+	ptrType := reflect.PtrTo(leafType)
+
+	// we instantiate an object matching the schema, with leaf type == pointer to field element
+	// to have nil for zero values
+	instance := s.instantiate(ptrType, true, true)
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(instance); err != nil {
+		return nil, nil, err
+	}
+
+	public = make([]any, 0, s.NbPublic)
+	secret = make([]any, 0, s.NbSecret)
+	var missingSecret, missingPublic string
+	add := func(visibility Visibility, name string, v any) {
+		switch {
+		case v == nil && visibility == Public && missingPublic == "":
+			missingPublic = name
+		case v == nil && visibility == Secret && missingSecret == "":
+			missingSecret = name
+		case v != nil && visibility == Public:
+			public = append(public, v)
+		case v != nil && visibility == Secret:
+			secret = append(secret, v)
+		}
+	}
+
+	h := jsonHandlers{
+		leaf: func(visibility Visibility, name string, value reflect.Value) error {
+			if value.IsNil() {
+				add(visibility, name, nil)
+			} else {
+				add(visibility, name, reflect.Indirect(value).Interface())
+			}
+			return nil
+		},
+		hook: func(f *Field, name string, value reflect.Value) error {
+			_, unmarshal, _ := f.jsonCodec()
+			visibilities := f.leafVisibilities(nil)
+			raw := value.Bytes()
+			if len(raw) == 0 || string(raw) == "null" {
+				for _, visibility := range visibilities {
+					add(visibility, name, nil)
+				}
+				return nil
+			}
+			leaves, err := unmarshal(raw)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			if len(leaves) != len(visibilities) {
+				return fmt.Errorf("%s: expected %d values, got %d", name, len(visibilities), len(leaves))
+			}
+			for i := range leaves {
+				add(visibilities[i], name, leaves[i])
+			}
+			return nil
+		},
+	}
+	if err := walkJSON(s.Fields, "", reflect.ValueOf(instance).Elem(), &h); err != nil {
+		return nil, nil, err
+	}
+
+	if missingPublic != "" {
+		return nil, nil, fmt.Errorf("missing assignment for %s", missingPublic)
+	}
+	if missingSecret != "" {
+		secret = nil
+	}
+	return public, secret, nil
+}
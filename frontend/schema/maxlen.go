@@ -0,0 +1,90 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// An object implementing a max length hook knows how to resize itself to the
+// length given by the maxlen tag option of its field (see [TagOptMaxLen]), when
+// parsed at compile time or when building a witness.
+type MaxLenHook interface {
+	GnarkMaxLenHook(maxLen int) error
+}
+
+// maxLen returns the value of the maxlen option, if set, and the options without it.
+func (o tagOptions) maxLen() (int, bool, tagOptions, error) {
+	if len(o) == 0 {
+		return 0, false, o, nil
+	}
+	optList := strings.Split(string(o), ",")
+	for i := range optList {
+		opt := strings.TrimSpace(optList[i])
+		if !strings.HasPrefix(opt, string(TagOptMaxLen)+"=") {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimPrefix(opt, string(TagOptMaxLen)+"="))
+		if err != nil || n < 0 {
+			return 0, false, o, fmt.Errorf("invalid %s option %q", TagOptMaxLen, opt)
+		}
+		rest := append(optList[:i:i], optList[i+1:]...)
+		return n, true, tagOptions(strings.Join(rest, ",")), nil
+	}
+	return 0, false, o, nil
+}
+
+// Pad resizes the value v of a field tagged with the maxlen option; v must be settable.
+//
+// If v implements MaxLenHook, the hook is called. Otherwise v must be a slice, which is
+// extended to maxLen elements; the nil leaves (of type tLeaf) of the new elements are set
+// to 0, so that padded assignments are valid witnesses.
+func Pad(v reflect.Value, tLeaf reflect.Type, maxLen int) error {
+	if v.CanAddr() && v.Addr().CanInterface() {
+		if h, ok := v.Addr().Interface().(MaxLenHook); ok {
+			return h.GnarkMaxLenHook(maxLen)
+		}
+	}
+	if v.Kind() != reflect.Slice {
+		return fmt.Errorf("%s option set on a %s, expected a slice", TagOptMaxLen, v.Type().String())
+	}
+	n := v.Len()
+	if n > maxLen {
+		return fmt.Errorf("%d elements exceed the maximum length %d", n, maxLen)
+	}
+	if n == maxLen {
+		return nil
+	}
+	padded := reflect.MakeSlice(v.Type(), maxLen, maxLen)
+	reflect.Copy(padded, v)
+	for i := n; i < maxLen; i++ {
+		if err := zeroLeaves(padded.Index(i), tLeaf); err != nil {
+			return err
+		}
+	}
+	v.Set(padded)
+	return nil
+}
+
+// zeroLeaves sets the nil leaves of v to 0, when the leaf type is an interface accepting
+// integers.
+func zeroLeaves(v reflect.Value, tLeaf reflect.Type) error {
+	zero := reflect.ValueOf(0)
+	if tLeaf.Kind() != reflect.Interface || !zero.Type().AssignableTo(tLeaf) {
+		return nil
+	}
+	if v.Type() == tLeaf {
+		if v.IsNil() {
+			v.Set(zero)
+		}
+		return nil
+	}
+	_, err := Walk(v.Addr().Interface(), tLeaf, func(_ LeafInfo, value reflect.Value) error {
+		if value.IsNil() && value.CanSet() {
+			value.Set(zero)
+		}
+		return nil
+	})
+	return err
+}
@@ -59,9 +59,14 @@ func (s Schema) Instantiate(leafType reflect.Type, omitEmptyTag ...bool) interfa
 	if len(omitEmptyTag) == 1 {
 		omitEmpty = omitEmptyTag[0]
 	}
+	return s.instantiate(leafType, omitEmpty, false)
+}
 
+// instantiate is Instantiate; if jsonHooks is set, the fields with a JSON hook are
+// json.RawMessage.
+func (s Schema) instantiate(leafType reflect.Type, omitEmpty, jsonHooks bool) interface{} {
 	// first, let's replace the Field by reflect.StructField
-	is := toStructField(s.Fields, leafType, omitEmpty)
+	is := toStructField(s.Fields, leafType, omitEmpty, jsonHooks)
 
 	// now create the corresponding type
 	typ := reflect.StructOf(is)
@@ -123,7 +128,7 @@ func (s Schema) WriteSequence(w io.Writer) error {
 }
 
 // toStructField recurse through Field and builds corresponding reflect.StructField
-func toStructField(fields []Field, leafType reflect.Type, omitEmpty bool) []reflect.StructField {
+func toStructField(fields []Field, leafType reflect.Type, omitEmpty, jsonHooks bool) []reflect.StructField {
 	r := make([]reflect.StructField, len(fields))
 
 	for i, f := range fields {
@@ -131,20 +136,32 @@ func toStructField(fields []Field, leafType reflect.Type, omitEmpty bool) []refl
 			Name: f.Name,
 			Tag:  structTag(f.NameTag, f.Visibility, omitEmpty),
 		}
+		if _, _, ok := f.jsonCodec(); ok && jsonHooks {
+			r[i].Type = tRawMessage
+			if omitEmpty && f.Visibility == Unset {
+				// the hooked object is a leaf of the JSON encoding.
+				r[i].Tag = structTag(f.NameTag, Secret, omitEmpty)
+			}
+			continue
+		}
 		switch f.Type {
 		case Leaf:
 			r[i].Type = leafType
 		case Array:
-			r[i].Type = arrayElementType(f.ArraySize, f.SubFields, leafType, omitEmpty)
+			r[i].Type = arrayElementType(f.ArraySize, f.SubFields, leafType, omitEmpty, jsonHooks)
+			if r[i].Type.Kind() == reflect.Slice && omitEmpty && f.Visibility == Unset {
+				// so is an array of hooked objects.
+				r[i].Tag = structTag(f.NameTag, Secret, omitEmpty)
+			}
 		case Struct:
-			r[i].Type = reflect.StructOf(toStructField(f.SubFields, leafType, omitEmpty))
+			r[i].Type = reflect.StructOf(toStructField(f.SubFields, leafType, omitEmpty, jsonHooks))
 		}
 	}
 
 	return r
 }
 
-func arrayElementType(n int, fields []Field, leafType reflect.Type, omitEmpty bool) reflect.Type {
+func arrayElementType(n int, fields []Field, leafType reflect.Type, omitEmpty, jsonHooks bool) reflect.Type {
 	// we know parent is an array.
 	// we check first element of fields
 	// if it's a struct or a leaf, we're done.
@@ -155,11 +172,20 @@ func arrayElementType(n int, fields []Field, leafType reflect.Type, omitEmpty bo
 		return reflect.ArrayOf(n, leafType)
 	}
 
+	// arrays of hooked objects are slices, so that omitempty drops the ones which aren't encoded
+	if _, _, ok := fields[0].jsonCodec(); ok && jsonHooks {
+		return reflect.SliceOf(tRawMessage)
+	}
+
 	switch fields[0].Type {
 	case Struct:
-		return reflect.ArrayOf(n, reflect.StructOf(toStructField(fields[0].SubFields, leafType, omitEmpty)))
+		return reflect.ArrayOf(n, reflect.StructOf(toStructField(fields[0].SubFields, leafType, omitEmpty, jsonHooks)))
 	case Array:
-		return reflect.ArrayOf(n, arrayElementType(fields[0].ArraySize, fields[0].SubFields, leafType, omitEmpty))
+		elem := arrayElementType(fields[0].ArraySize, fields[0].SubFields, leafType, omitEmpty, jsonHooks)
+		if elem.Kind() == reflect.Slice {
+			return reflect.SliceOf(elem)
+		}
+		return reflect.ArrayOf(n, elem)
 	}
 	panic("invalid array type")
 }
@@ -246,6 +272,16 @@ func parse(r []Field, input interface{}, target reflect.Type, parentFullName, pa
 					nameTag = ""
 				}
 				opts = tagOptions(strings.TrimSpace(string(opts)))
+				maxLen, hasMaxLen, rest, err := opts.maxLen()
+				if err != nil {
+					return r, fmt.Errorf("%s: %w", getFullName(parentGoName, name, nameTag), err)
+				}
+				if hasMaxLen {
+					if err := Pad(tValue.FieldByIndex(f.Index), target, maxLen); err != nil {
+						return r, fmt.Errorf("%s: %w", getFullName(parentGoName, name, nameTag), err)
+					}
+				}
+				opts = rest
 				switch {
 				case opts.contains(TagOptSecret):
 					visibility = Secret
@@ -290,10 +326,14 @@ func parse(r []Field, input interface{}, target reflect.Type, parentFullName, pa
 					ih.GnarkInitHook()
 				}
 				var err error
+				n := len(subFields)
 				subFields, err = parse(subFields, value, target, getFullName(parentFullName, name, nameTag), name, nameTag, visibility, nbPublic, nbSecret)
 				if err != nil {
 					return r, err
 				}
+				if len(subFields) > n {
+					setJSONHooks(&subFields[n], value)
+				}
 			}
 		}
 
@@ -365,10 +405,14 @@ func parse(r []Field, input interface{}, target reflect.Type, parentFullName, pa
 				if ih, hasInitHook := ival.(InitHook); hasInitHook {
 					ih.GnarkInitHook()
 				}
+				n := len(subFields)
 				subFields, err = parse(subFields, ival, target, fqn, fqn, parentTagName, parentVisibility, nbPublic, nbSecret)
 				if err != nil {
 					return nil, err
 				}
+				if len(subFields) > n {
+					setJSONHooks(&subFields[n], ival)
+				}
 			}
 		}
 		if len(subFields) == 0 {
@@ -190,6 +190,46 @@ func TestVariableInitHook(t *testing.T) {
 	assert.Equal(s.NbSecret, 10) // X: 2*2, Y: 2*2, Z: 2
 }
 
+type maxLenCircuit struct {
+	X []variable          `gnark:",public,maxlen=4"`
+	Y []circuitGrandChild `gnark:"y,maxlen=2"`
+}
+
+func TestMaxLen(t *testing.T) {
+	assert := require.New(t)
+
+	c := &maxLenCircuit{X: []variable{1, 2}}
+	s, err := New(c, tVariable)
+	assert.NoError(err)
+	assert.Equal(4, s.NbPublic)
+	assert.Equal(2*5, s.NbSecret) // Y: 2*(E, F, M, P)
+	assert.Equal([]variable{1, 2, 0, 0}, c.X)
+
+	// walking the padded assignment gives the same values
+	c = &maxLenCircuit{X: []variable{1, 2}, Y: []circuitGrandChild{{E: 3}}}
+	var values []variable
+	count, err := Walk(c, tVariable, func(_ LeafInfo, v reflect.Value) error {
+		values = append(values, v.Interface())
+		return nil
+	})
+	assert.NoError(err)
+	assert.Equal(LeafCount{Public: 4, Secret: 10}, count)
+	assert.Equal([]variable{1, 2, 0, 0, 3, nil, nil, nil, nil, 0, 0, 0, 0, 0}, values)
+
+	_, err = New(&maxLenCircuit{X: make([]variable, 5)}, tVariable)
+	assert.Error(err, "too many elements")
+
+	_, err = New(&struct {
+		X [2]variable `gnark:",maxlen=2"`
+	}{}, tVariable)
+	assert.Error(err, "maxlen on an array")
+
+	_, err = Walk(&struct {
+		X []variable `gnark:",maxlen=-1"`
+	}{}, tVariable, nil)
+	assert.Error(err, "invalid maxlen")
+}
+
 func BenchmarkLargeSchema(b *testing.B) {
 	const n1 = 1 << 12
 	const n2 = 1 << 12
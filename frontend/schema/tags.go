@@ -15,7 +15,9 @@ import (
 //   - [TagOptInherit] ("inherit"): element's visibility is inherited from its
 //     parent visibility. Is useful for defining custom types to allow consistent
 //     visibility;
-//   - [TagOptOmit] ("-"): do not insert the element into a witness.
+//   - [TagOptOmit] ("-"): do not insert the element into a witness;
+//   - [TagOptMaxLen] ("maxlen=N"): the element is a slice of variable length,
+//     padded to N elements (see [MaxLenHook]).
 //
 // # Examples
 //
@@ -70,6 +72,17 @@ import (
 //	type ListCircuit struct {
 //	    X List `gnark:",secret"`
 //	}
+//
+// Slices of variable length are allocated with a fixed number of elements
+// using the "maxlen" option. The assignments are padded with zeroes up to the
+// given length when building the witness:
+//
+//	type MessageCircuit struct {
+//	    Msg []frontend.Variable `gnark:",public,maxlen=64"`
+//	}
+//
+// Types implementing [MaxLenHook] (for example varslice.Slice, which also
+// records the actual length in the witness) define how they are padded.
 type TagOpt string
 
 const (
@@ -77,6 +90,7 @@ const (
 	TagOptSecret  TagOpt = "secret"  // secret witness element
 	TagOptInherit TagOpt = "inherit" // inherit the visibility of the witness element from its parent.
 	TagOptOmit    TagOpt = "-"       // do not parse the field as witness element
+	TagOptMaxLen  TagOpt = "maxlen"  // pad the slice to the given length ("maxlen=N")
 )
 
 const (
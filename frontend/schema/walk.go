@@ -196,6 +196,15 @@ func (w *walker) StructField(sf reflect.StructField, v reflect.Value) error {
 		case opts.contains(TagOptPublic):
 			info.Visibility = Public
 		}
+		maxLen, ok, _, err := opts.maxLen()
+		if err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		if ok {
+			if err := Pad(v, w.target, maxLen); err != nil {
+				return fmt.Errorf("%s: %w", sf.Name, err)
+			}
+		}
 	}
 
 	if parentVisibility != Unset && parentVisibility != info.Visibility {
@@ -0,0 +1,304 @@
+// Package typed provides typed wrappers of frontend.Variable for booleans and bounded
+// integers.
+//
+// A [Bool] or a [Uint] is only obtained through a constructor which constrains its value
+// once (a boolean constraint, or a range check), and the operations on them keep the
+// invariant, so that gadgets taking typed values as inputs don't have to check them again:
+//
+//	t := typed.New(api, rangecheck.New(api))
+//	a := typed.NewUint[typed.Bits8](t, c.A) // range checked
+//	b := typed.NewUint[typed.Bits8](t, c.B)
+//	s := typed.Add(t, a, b)                 // s < 2⁹, not range checked
+//	s = typed.Reduce(t, s)                  // s = a + b mod 2⁸
+//
+// The bound of a Uint is a type parameter implementing [Width]. The arithmetic operations
+// track the number of bits of the result, which may exceed the width (see [Uint.Overflow]);
+// the results are reduced automatically before they overflow the native field.
+//
+// The range checks are delegated to the frontend.Rangechecker given to [New], usually the one
+// of gnark/std/rangecheck. The hint of the package is registered with the ones of gnark/std
+// (see std.RegisterHints), or given to the solver with [GetHints].
+package typed
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+)
+
+// API creates and operates on typed values in a circuit.
+type API struct {
+	api      frontend.API
+	rchecker frontend.Rangechecker
+}
+
+// New returns a new API for creating typed values in the circuit, range checking the Uint
+// values with rchecker.
+func New(api frontend.API, rchecker frontend.Rangechecker) *API {
+	return &API{api: api, rchecker: rchecker}
+}
+
+// Bool is a variable constrained to be 0 or 1.
+type Bool struct {
+	v frontend.Variable
+}
+
+// NewBool returns v as a Bool, constraining it to be boolean.
+func (t *API) NewBool(v frontend.Variable) Bool {
+	t.api.AssertIsBoolean(v)
+	return Bool{v: v}
+}
+
+// BoolConstant returns the constant b as a Bool.
+func BoolConstant(b bool) Bool {
+	if b {
+		return Bool{v: 1}
+	}
+	return Bool{v: 0}
+}
+
+// Variable returns the value of b.
+func (b Bool) Variable() frontend.Variable {
+	if b.v == nil {
+		return 0
+	}
+	return b.v
+}
+
+// And returns a ∧ b.
+func (t *API) And(a, b Bool) Bool {
+	return Bool{v: t.api.And(a.Variable(), b.Variable())}
+}
+
+// Or returns a ∨ b.
+func (t *API) Or(a, b Bool) Bool {
+	return Bool{v: t.api.Or(a.Variable(), b.Variable())}
+}
+
+// Xor returns a ⊕ b.
+func (t *API) Xor(a, b Bool) Bool {
+	return Bool{v: t.api.Xor(a.Variable(), b.Variable())}
+}
+
+// Not returns ¬a.
+func (t *API) Not(a Bool) Bool {
+	return Bool{v: t.api.Sub(1, a.Variable())}
+}
+
+// IsZero returns 1 if v is zero, 0 otherwise.
+func (t *API) IsZero(v frontend.Variable) Bool {
+	return Bool{v: t.api.IsZero(v)}
+}
+
+// SelectBool returns a if cond is true, b otherwise.
+func (t *API) SelectBool(cond, a, b Bool) Bool {
+	return Bool{v: t.api.Select(cond.Variable(), a.Variable(), b.Variable())}
+}
+
+// AssertBool fails if a ≠ b.
+func (t *API) AssertBool(a, b Bool) {
+	t.api.AssertIsEqual(a.Variable(), b.Variable())
+}
+
+// Width is the bound of a Uint: the values of type Uint[W] have at most W.NbBits() bits.
+// Custom widths are defined with empty structs:
+//
+//	type Bits20 struct{}
+//
+//	func (Bits20) NbBits() int { return 20 }
+type Width interface {
+	NbBits() int
+}
+
+// Bits8 is the width of 8-bit integers.
+type Bits8 struct{}
+
+// Bits16 is the width of 16-bit integers.
+type Bits16 struct{}
+
+// Bits32 is the width of 32-bit integers.
+type Bits32 struct{}
+
+// Bits64 is the width of 64-bit integers.
+type Bits64 struct{}
+
+func (Bits8) NbBits() int  { return 8 }
+func (Bits16) NbBits() int { return 16 }
+func (Bits32) NbBits() int { return 32 }
+func (Bits64) NbBits() int { return 64 }
+
+func nbBitsOf[W Width]() int {
+	var w W
+	return w.NbBits()
+}
+
+// Uint is an unsigned integer bounded by the width W. The results of the arithmetic
+// operations may exceed the width, up to a tracked number of bits (see Overflow).
+type Uint[W Width] struct {
+	v      frontend.Variable
+	nbBits int // v < 2^nbBits
+}
+
+// NewUint returns v as a Uint[W], range checking it to W.NbBits() bits.
+func NewUint[W Width](t *API, v frontend.Variable) Uint[W] {
+	nbBits := nbBitsOf[W]()
+	if c, ok := t.api.Compiler().ConstantValue(v); ok {
+		if c.BitLen() > nbBits {
+			panic(fmt.Sprintf("constant %s doesn't fit in %d bits", c.String(), nbBits))
+		}
+		return Uint[W]{v: c, nbBits: nbBits}
+	}
+	t.rchecker.Check(v, nbBits)
+	return Uint[W]{v: v, nbBits: nbBits}
+}
+
+// UintConstant returns the constant c as a Uint[W]. It panics if c doesn't fit in the width.
+func UintConstant[W Width](c uint64) Uint[W] {
+	nbBits := nbBitsOf[W]()
+	if nbBits < 64 && c>>nbBits != 0 {
+		panic(fmt.Sprintf("constant %d doesn't fit in %d bits", c, nbBits))
+	}
+	return Uint[W]{v: c, nbBits: nbBits}
+}
+
+// FromBool returns b as a Uint[W].
+func FromBool[W Width](b Bool) Uint[W] {
+	return Uint[W]{v: b.Variable(), nbBits: 1}
+}
+
+// Variable returns the value of a.
+func (a Uint[W]) Variable() frontend.Variable {
+	if a.v == nil {
+		return 0
+	}
+	return a.v
+}
+
+// Overflow returns the number of bits of a beyond the width W; it is 0 if a is known to be
+// in the range of W.
+func (a Uint[W]) Overflow() int {
+	if over := a.bound() - nbBitsOf[W](); over > 0 {
+		return over
+	}
+	return 0
+}
+
+// bound returns the bound on the number of bits of a, the width for zero values.
+func (a Uint[W]) bound() int {
+	if a.v == nil {
+		return nbBitsOf[W]()
+	}
+	return a.nbBits
+}
+
+// maxBound returns the largest of the bounds of a and b.
+func maxBound[W Width](a, b Uint[W]) int {
+	if a.bound() > b.bound() {
+		return a.bound()
+	}
+	return b.bound()
+}
+
+// Add returns a + b. The result has one bit more than the largest operand.
+func Add[W Width](t *API, a, b Uint[W]) Uint[W] {
+	if !t.fits(maxBound(a, b) + 1) {
+		a, b = Reduce(t, a), Reduce(t, b)
+	}
+	nbBits := maxBound(a, b) + 1
+	t.mustFit(nbBits)
+	return Uint[W]{v: t.api.Add(a.Variable(), b.Variable()), nbBits: nbBits}
+}
+
+// Mul returns a * b. The number of bits of the result is the sum of the numbers of bits of
+// the operands.
+func Mul[W Width](t *API, a, b Uint[W]) Uint[W] {
+	if !t.fits(a.bound() + b.bound()) {
+		a, b = Reduce(t, a), Reduce(t, b)
+	}
+	nbBits := a.bound() + b.bound()
+	t.mustFit(nbBits)
+	return Uint[W]{v: t.api.Mul(a.Variable(), b.Variable()), nbBits: nbBits}
+}
+
+// Select returns a if cond is true, b otherwise.
+func Select[W Width](t *API, cond Bool, a, b Uint[W]) Uint[W] {
+	return Uint[W]{v: t.api.Select(cond.Variable(), a.Variable(), b.Variable()), nbBits: maxBound(a, b)}
+}
+
+// IsEqual returns 1 if a = b, 0 otherwise. The operands must not overflow the field, which
+// the operations ensure.
+func IsEqual[W Width](t *API, a, b Uint[W]) Bool {
+	return t.IsZero(t.api.Sub(a.Variable(), b.Variable()))
+}
+
+// Reduce returns a mod 2^W.NbBits(). It is a no-op if a doesn't overflow.
+func Reduce[W Width](t *API, a Uint[W]) Uint[W] {
+	if a.Overflow() == 0 {
+		return a
+	}
+	nbBits := nbBitsOf[W]()
+	if c, ok := t.api.Compiler().ConstantValue(a.Variable()); ok {
+		mask := new(big.Int).Lsh(big.NewInt(1), uint(nbBits))
+		mask.Sub(mask, big.NewInt(1))
+		return Uint[W]{v: new(big.Int).And(c, mask), nbBits: nbBits}
+	}
+	res, err := t.api.Compiler().NewHint(reduceHint, 2, nbBits, a.Variable())
+	if err != nil {
+		panic(fmt.Sprintf("reduce hint: %v", err))
+	}
+	q, r := res[0], res[1]
+	t.rchecker.Check(r, nbBits)
+	t.rchecker.Check(q, a.Overflow())
+	t.api.AssertIsEqual(a.Variable(), t.api.Add(t.api.Mul(q, new(big.Int).Lsh(big.NewInt(1), uint(nbBits))), r))
+	return Uint[W]{v: r, nbBits: nbBits}
+}
+
+// Check returns a, range checked to the width W: the circuit fails if a overflowed. It is a
+// no-op if a doesn't overflow.
+func Check[W Width](t *API, a Uint[W]) Uint[W] {
+	if a.Overflow() == 0 {
+		return a
+	}
+	nbBits := nbBitsOf[W]()
+	t.rchecker.Check(a.Variable(), nbBits)
+	return Uint[W]{v: a.Variable(), nbBits: nbBits}
+}
+
+// AssertUint fails if a ≠ b.
+func AssertUint[W Width](t *API, a, b Uint[W]) {
+	t.api.AssertIsEqual(a.Variable(), b.Variable())
+}
+
+// fits returns true if the integers of nbBits bits don't overflow the native field.
+func (t *API) fits(nbBits int) bool {
+	return nbBits < t.api.Compiler().FieldBitLen()
+}
+
+func (t *API) mustFit(nbBits int) {
+	if !t.fits(nbBits) {
+		panic(fmt.Sprintf("result of %d bits overflows the native field", nbBits))
+	}
+}
+
+// GetHints returns all hints used in this package.
+func GetHints() []solver.Hint {
+	return []solver.Hint{reduceHint}
+}
+
+// reduceHint returns the quotient and remainder of inputs[1] by 2^inputs[0].
+func reduceHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 2 || len(outputs) != 2 {
+		return fmt.Errorf("expected 2 inputs and 2 outputs")
+	}
+	if !inputs[0].IsUint64() {
+		return fmt.Errorf("invalid number of bits")
+	}
+	nbBits := uint(inputs[0].Uint64())
+	outputs[0].Rsh(inputs[1], nbBits)
+	mask := new(big.Int).Lsh(big.NewInt(1), nbBits)
+	mask.Sub(mask, big.NewInt(1))
+	outputs[1].And(inputs[1], mask)
+	return nil
+}
@@ -0,0 +1,116 @@
+package typed_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/typed"
+	"github.com/consensys/gnark/std"
+	"github.com/consensys/gnark/std/rangecheck"
+	"github.com/consensys/gnark/test"
+)
+
+type uintCircuit struct {
+	A, B, C     frontend.Variable
+	Sum, Choice frontend.Variable `gnark:",public"`
+	Equal       frontend.Variable `gnark:",public"`
+}
+
+func (c *uintCircuit) Define(api frontend.API) error {
+	t := typed.New(api, rangecheck.New(api))
+	a := typed.NewUint[typed.Bits8](t, c.A)
+	b := typed.NewUint[typed.Bits8](t, c.B)
+	cond := t.NewBool(c.C)
+
+	sum := typed.Add(t, a, b)
+	if sum.Overflow() != 1 {
+		panic("the sum should overflow by one bit")
+	}
+	typed.AssertUint(t, typed.Reduce(t, sum), typed.NewUint[typed.Bits8](t, c.Sum))
+
+	choice := typed.Select(t, t.Not(cond), a, b)
+	api.AssertIsEqual(choice.Variable(), c.Choice)
+	t.AssertBool(typed.IsEqual(t, a, b), t.NewBool(c.Equal))
+	return nil
+}
+
+// the varuna range checker only supports the R1CS builder
+func disableVaruna(t *testing.T) {
+	t.Setenv("DISABLE_VARUNA_RANGE_CHECK_METHODS", "true")
+}
+
+func TestUint(t *testing.T) {
+	disableVaruna(t)
+	assert := test.NewAssert(t)
+	std.RegisterHints()
+
+	assert.ProverSucceeded(&uintCircuit{}, &uintCircuit{A: 200, B: 100, C: 1, Sum: 44, Choice: 100, Equal: 0}, test.WithCurves(ecc.BN254))
+	assert.ProverSucceeded(&uintCircuit{}, &uintCircuit{A: 7, B: 7, C: 0, Sum: 14, Choice: 7, Equal: 1}, test.WithCurves(ecc.BN254))
+
+	// out of range inputs
+	assert.ProverFailed(&uintCircuit{}, &uintCircuit{A: 1 << 40, B: 44, C: 1, Sum: 44, Choice: 44, Equal: 0}, test.WithCurves(ecc.BN254))
+	assert.ProverFailed(&uintCircuit{}, &uintCircuit{A: 200, B: 100, C: 2, Sum: 44, Choice: 100, Equal: 0}, test.WithCurves(ecc.BN254))
+	// wrong results
+	assert.ProverFailed(&uintCircuit{}, &uintCircuit{A: 200, B: 100, C: 1, Sum: 300, Choice: 100, Equal: 0}, test.WithCurves(ecc.BN254))
+	assert.ProverFailed(&uintCircuit{}, &uintCircuit{A: 200, B: 100, C: 1, Sum: 44, Choice: 200, Equal: 0}, test.WithCurves(ecc.BN254))
+}
+
+type productCircuit struct {
+	In      [6]frontend.Variable
+	Product frontend.Variable `gnark:",public"`
+}
+
+func (c *productCircuit) Define(api frontend.API) error {
+	t := typed.New(api, rangecheck.New(api))
+	product := typed.UintConstant[typed.Bits64](1)
+	for i := range c.In {
+		// the product is reduced before it overflows the field
+		product = typed.Mul(t, product, typed.NewUint[typed.Bits64](t, c.In[i]))
+	}
+	typed.AssertUint(t, typed.Check(t, typed.Reduce(t, product)), typed.NewUint[typed.Bits64](t, c.Product))
+	return nil
+}
+
+func TestAutomaticReduction(t *testing.T) {
+	disableVaruna(t)
+	assert := test.NewAssert(t)
+	std.RegisterHints()
+
+	var witness productCircuit
+	product := uint64(1)
+	for i := range witness.In {
+		v := uint64(0xfedcba9876543210) + uint64(i)
+		witness.In[i] = v
+		product *= v
+	}
+	witness.Product = product
+	assert.ProverSucceeded(&productCircuit{}, &witness, test.WithCurves(ecc.BN254))
+
+	witness.Product = product + 1
+	assert.ProverFailed(&productCircuit{}, &witness, test.WithCurves(ecc.BN254))
+}
+
+type boolCircuit struct {
+	A, B         frontend.Variable
+	And, Or, Xor frontend.Variable `gnark:",public"`
+}
+
+func (c *boolCircuit) Define(api frontend.API) error {
+	t := typed.New(api, rangecheck.New(api))
+	a, b := t.NewBool(c.A), t.NewBool(c.B)
+	t.AssertBool(t.And(a, b), t.NewBool(c.And))
+	t.AssertBool(t.Or(a, b), t.NewBool(c.Or))
+	t.AssertBool(t.Xor(a, b), t.NewBool(c.Xor))
+	t.AssertBool(t.SelectBool(a, b, typed.BoolConstant(false)), t.And(a, b))
+	return nil
+}
+
+func TestBool(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	for _, v := range [][2]int{{0, 0}, {0, 1}, {1, 0}, {1, 1}} {
+		assert.ProverSucceeded(&boolCircuit{}, &boolCircuit{A: v[0], B: v[1], And: v[0] & v[1], Or: v[0] | v[1], Xor: v[0] ^ v[1]}, test.WithCurves(ecc.BN254))
+	}
+	assert.ProverFailed(&boolCircuit{}, &boolCircuit{A: 2, B: 0, And: 0, Or: 1, Xor: 1}, test.WithCurves(ecc.BN254))
+}
@@ -0,0 +1,33 @@
+package circuits
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// Cube checks that Y = X³ and, if WithCommitment is set, commits to X and Y (BSB22). It isn't
+// registered in Circuits: the tests of the backends use it directly as a small circuit to prove.
+type Cube struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+
+	WithCommitment bool
+}
+
+func (c *Cube) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X, c.X), c.Y)
+	if !c.WithCommitment {
+		return nil
+	}
+	committer, ok := api.(frontend.Committer)
+	if !ok {
+		return errors.New("the builder doesn't support commitments")
+	}
+	commitment, err := committer.Commit(c.X, c.Y)
+	if err != nil {
+		return err
+	}
+	api.AssertIsDifferent(commitment, c.X)
+	return nil
+}
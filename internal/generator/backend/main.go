@@ -163,7 +163,11 @@ func main() {
 				{File: filepath.Join(groth16Dir, "setup.go"), Templates: []string{"groth16/groth16.setup.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16Dir, "commitment.go"), Templates: []string{"groth16/groth16.commitment.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16Dir, "marshal.go"), Templates: []string{"groth16/groth16.marshal.go.tmpl", importCurve}},
+				{File: filepath.Join(groth16Dir, "disk.go"), Templates: []string{"groth16/groth16.disk.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16Dir, "marshal_test.go"), Templates: []string{"groth16/tests/groth16.marshal.go.tmpl", importCurve}},
+				{File: filepath.Join(groth16Dir, "disk_test.go"), Templates: []string{"groth16/tests/groth16.disk.go.tmpl", importCurve}},
+				{File: filepath.Join(groth16Dir, "prove_test.go"), Templates: []string{"groth16/tests/groth16.prove.go.tmpl", importCurve}},
+				{File: filepath.Join(groth16Dir, "verify_test.go"), Templates: []string{"groth16/tests/groth16.verify.go.tmpl", importCurve}},
 			}
 			if err := bgen.Generate(d, "groth16", "./template/zkpschemes/", entries...); err != nil {
 				panic(err) // TODO handle
@@ -192,9 +196,42 @@ func main() {
 				panic(err) // TODO handle
 			}
 
+			// groth16 aggregation
+			if d.Curve == "BN254" || d.Curve == "BLS12-381" {
+				groth16AggregationDir := filepath.Join(groth16Dir, "aggregation")
+				entries = []bavard.Entry{
+					{File: filepath.Join(groth16AggregationDir, "srs.go"), Templates: []string{"groth16/aggregation/srs.go.tmpl", importCurve}},
+					{File: filepath.Join(groth16AggregationDir, "transcript.go"), Templates: []string{"groth16/aggregation/transcript.go.tmpl", importCurve}},
+					{File: filepath.Join(groth16AggregationDir, "prove.go"), Templates: []string{"groth16/aggregation/prove.go.tmpl", importCurve}},
+					{File: filepath.Join(groth16AggregationDir, "verify.go"), Templates: []string{"groth16/aggregation/verify.go.tmpl", importCurve}},
+					{File: filepath.Join(groth16AggregationDir, "marshal.go"), Templates: []string{"groth16/aggregation/marshal.go.tmpl", importCurve}},
+					{File: filepath.Join(groth16AggregationDir, "aggregation_test.go"), Templates: []string{"groth16/aggregation/aggregation_test.go.tmpl", importCurve}},
+				}
+				if err := bgen.Generate(d, "aggregation", "./template/zkpschemes/", entries...); err != nil {
+					panic(err) // TODO handle
+				}
+			}
+
+			// .ptau readers
+			if d.Curve == "BN254" || d.Curve == "BLS12-381" {
+				srsData := struct {
+					templateData
+					CurveName string // suffix of the identifiers of the curve
+				}{d, strings.ReplaceAll(d.Curve, "-", "")}
+				srsFile := strings.ToLower(srsData.CurveName)
+				entries = []bavard.Entry{
+					{File: filepath.Join("../../../backend/srs", srsFile+".go"), Templates: []string{"srs/ptau.go.tmpl", "imports.go.tmpl"}},
+					{File: filepath.Join("../../../backend/srs", srsFile+"_test.go"), Templates: []string{"srs/tests/ptau.go.tmpl", "imports.go.tmpl"}},
+				}
+				if err := bgen.Generate(srsData, "srs", "./template/", entries...); err != nil {
+					panic(err)
+				}
+			}
+
 			// plonk
 			entries = []bavard.Entry{
 				{File: filepath.Join(plonkDir, "verify.go"), Templates: []string{"plonk/plonk.verify.go.tmpl", importCurve}},
+				{File: filepath.Join(plonkDir, "accumulator.go"), Templates: []string{"plonk/plonk.accumulator.go.tmpl", importCurve}},
 				{File: filepath.Join(plonkDir, "prove.go"), Templates: []string{"plonk/plonk.prove.go.tmpl", importCurve}},
 				{File: filepath.Join(plonkDir, "setup.go"), Templates: []string{"plonk/plonk.setup.go.tmpl", importCurve}},
 				{File: filepath.Join(plonkDir, "marshal.go"), Templates: []string{"plonk/plonk.marshal.go.tmpl", importCurve}},
@@ -209,6 +246,10 @@ func main() {
 				{File: filepath.Join(plonkFriDir, "verify.go"), Templates: []string{"plonkfri/plonk.verify.go.tmpl", importCurve}},
 				{File: filepath.Join(plonkFriDir, "prove.go"), Templates: []string{"plonkfri/plonk.prove.go.tmpl", importCurve}},
 				{File: filepath.Join(plonkFriDir, "setup.go"), Templates: []string{"plonkfri/plonk.setup.go.tmpl", importCurve}},
+				{File: filepath.Join(plonkFriDir, "fri.go"), Templates: []string{"plonkfri/plonk.fri.go.tmpl", importCurve}},
+				{File: filepath.Join(plonkFriDir, "marshal.go"), Templates: []string{"plonkfri/plonk.marshal.go.tmpl", importCurve}},
+				{File: filepath.Join(plonkFriDir, "fri_test.go"), Templates: []string{"plonkfri/tests/fri.go.tmpl", importCurve}},
+				{File: filepath.Join(plonkFriDir, "marshal_test.go"), Templates: []string{"plonkfri/tests/marshal.go.tmpl", importCurve}},
 			}
 			if err := bgen.Generate(d, "plonkfri", "./template/zkpschemes/", entries...); err != nil {
 				panic(err)
@@ -15,6 +15,7 @@
 package utils
 
 import (
+	"errors"
 	"math"
 	"math/big"
 	"reflect"
@@ -116,3 +117,20 @@ func Uint64SliceSliceToIntSliceSlice(in [][]uint64) [][]int {
 	}
 	return res
 }
+
+// BigIntFromJSON parses an integer encoded in JSON either as a number or as a string (decimal,
+// or prefixed as in (big.Int).SetString(input, 0), e.g. "0x2a").
+func BigIntFromJSON(data []byte) (*big.Int, error) {
+	s := string(data)
+	var ok bool
+	r := new(big.Int)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		_, ok = r.SetString(s[1:len(s)-1], 0)
+	} else {
+		_, ok = r.SetString(s, 10)
+	}
+	if !ok {
+		return nil, errors.New("invalid integer " + s)
+	}
+	return r, nil
+}
@@ -12,6 +12,9 @@ func Parallelize(nbIterations int, work func(int, int), maxCpus ...int) {
 	if len(maxCpus) == 1 {
 		nbTasks = maxCpus[0]
 	}
+	if nbTasks < 1 {
+		nbTasks = 1
+	}
 	nbIterationsPerCpus := nbIterations / nbTasks
 
 	// more CPUs than tasks: a CPU will work on exactly one iteration
@@ -158,18 +158,29 @@ func (p *Profile) Top() string {
 
 // RecordConstraint add a sample (with count == 1) to all the active profiling sessions.
 func RecordConstraint() {
+	recordConstraint("")
+}
+
+// RecordScopedConstraint is RecordConstraint for a constraint added in a scope (see
+// frontend.Scoper). The samples are labelled with the scope path, so that the profile
+// can be filtered with pprof -tagfocus scope=<path>.
+func RecordScopedConstraint(scope string) {
+	recordConstraint(scope)
+}
+
+func recordConstraint(scope string) {
 	if n := atomic.LoadUint32(&activeSessions); n == 0 {
 		return // do nothing, no active session.
 	}
 
 	// collect the stack and send it async to the worker
 	pc := make([]uintptr, 20)
-	n := runtime.Callers(3, pc)
+	n := runtime.Callers(4, pc)
 	if n == 0 {
 		return
 	}
 	pc = pc[:n]
-	chCommands <- command{pc: pc}
+	chCommands <- command{pc: pc, scope: scope}
 }
 
 func (p *Profile) getLocation(frame *runtime.Frame) *profile.Location {
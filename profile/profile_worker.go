@@ -20,6 +20,7 @@ var onceInit sync.Once
 type command struct {
 	p      *Profile
 	pc     []uintptr
+	scope  string
 	remove bool
 }
 
@@ -45,17 +46,20 @@ func worker() {
 		}
 
 		// it's a sampling of event
-		collectSample(c.pc)
+		collectSample(c.pc, c.scope)
 	}
 
 }
 
 // collectSample must be called from the worker go routine
-func collectSample(pc []uintptr) {
+func collectSample(pc []uintptr, scope string) {
 	// for each session we may have a distinct sample, since ids of functions and locations may mismatch
 	samples := make([]*profile.Sample, len(sessions))
 	for i := 0; i < len(samples); i++ {
 		samples[i] = &profile.Sample{Value: []int64{1}} // for now, we just collect new constraints count
+		if scope != "" {
+			samples[i].Label = map[string][]string{"scope": {scope}}
+		}
 	}
 
 	frames := runtime.CallersFrames(pc)
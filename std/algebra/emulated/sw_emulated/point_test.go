@@ -726,6 +726,46 @@ func TestIsOnCurve3(t *testing.T) {
 	assert.NoError(err)
 }
 
+type ConditionalIsOnCurveTest[T, S emulated.FieldParams] struct {
+	Enabled frontend.Variable
+	Q       AffinePoint[T]
+}
+
+func (c *ConditionalIsOnCurveTest[T, S]) Define(api frontend.API) error {
+	cr, err := New[T, S](api, GetCurveParams[T]())
+	if err != nil {
+		return err
+	}
+	defer api.Compiler().(frontend.Conditioner).When(c.Enabled)()
+	cr.AssertIsOnCurve(&c.Q)
+	return nil
+}
+
+func TestConditionalIsOnCurve(t *testing.T) {
+	// the varuna range checker only supports the R1CS builder
+	t.Setenv("DISABLE_VARUNA_RANGE_CHECK_METHODS", "true")
+	assert := test.NewAssert(t)
+	_, g := secp256k1.Generators()
+	var offCurve secp256k1.G1Affine
+	offCurve.X.Set(&g.X)
+	offCurve.Y.Double(&g.Y)
+
+	point := func(p secp256k1.G1Affine) AffinePoint[emulated.Secp256k1Fp] {
+		return AffinePoint[emulated.Secp256k1Fp]{
+			X: emulated.ValueOf[emulated.Secp256k1Fp](p.X),
+			Y: emulated.ValueOf[emulated.Secp256k1Fp](p.Y),
+		}
+	}
+	circuit := ConditionalIsOnCurveTest[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{}
+	assert.CheckCircuit(&circuit,
+		test.WithValidAssignment(&ConditionalIsOnCurveTest[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{Enabled: 1, Q: point(g)}),
+		// the check is gated when the condition is 0
+		test.WithValidAssignment(&ConditionalIsOnCurveTest[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{Enabled: 0, Q: point(offCurve)}),
+		test.WithInvalidAssignment(&ConditionalIsOnCurveTest[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{Enabled: 1, Q: point(offCurve)}),
+		test.WithCurves(testCurve), test.NoSerializationChecks(), test.NoProverChecks(),
+	)
+}
+
 type JointScalarMulBaseTest[T, S emulated.FieldParams] struct {
 	P, Q   AffinePoint[T]
 	S1, S2 emulated.Element[S]
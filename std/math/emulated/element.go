@@ -94,6 +94,41 @@ func (e *Element[T]) GnarkInitHook() {
 	}
 }
 
+// GnarkMarshalJSON implements schema.JSONHook. Elements are encoded in witness assignments as
+// hex strings instead of their limbs.
+func (e *Element[T]) GnarkMarshalJSON(limbs []*big.Int) ([]byte, error) {
+	var fp T
+	if len(limbs) != int(fp.NbLimbs()) {
+		return nil, fmt.Errorf("expected %d limbs, got %d", fp.NbLimbs(), len(limbs))
+	}
+	v := new(big.Int)
+	if err := recompose(limbs, fp.BitsPerLimb(), v); err != nil {
+		return nil, err
+	}
+	return []byte(fmt.Sprintf("\"0x%s\"", v.Text(16))), nil
+}
+
+// GnarkUnmarshalJSON implements schema.JSONHook. It decomposes the integer in data (JSON
+// number, decimal or hex string) into limbs. The integer must be in [0, p).
+func (e *Element[T]) GnarkUnmarshalJSON(data []byte) ([]*big.Int, error) {
+	var fp T
+	v, err := utils.BigIntFromJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	if v.Sign() < 0 || v.Cmp(fp.Modulus()) >= 0 {
+		return nil, fmt.Errorf("%s is not in the emulated field [0, %s)", v.String(), fp.Modulus().String())
+	}
+	limbs := make([]*big.Int, fp.NbLimbs())
+	for i := range limbs {
+		limbs[i] = new(big.Int)
+	}
+	if err := decompose(v, fp.BitsPerLimb(), limbs); err != nil {
+		return nil, err
+	}
+	return limbs, nil
+}
+
 // copy makes a deep copy of the element.
 func (e *Element[T]) copy() *Element[T] {
 	r := Element[T]{}
@@ -956,3 +956,52 @@ func testSqrt[T FieldParams](t *testing.T) {
 		assert.ProverSucceeded(&SqrtCircuit[T]{}, &SqrtCircuit[T]{X: ValueOf[T](X), Expected: ValueOf[T](exp)}, test.WithCurves(testCurve), test.NoSerializationChecks(), test.WithBackends(backend.GROTH16, backend.PLONK))
 	}, testName[T]())
 }
+
+type ConditionalCircuit[T FieldParams] struct {
+	Enabled frontend.Variable
+	A, B, C Element[T]
+}
+
+func (c *ConditionalCircuit[T]) Define(api frontend.API) error {
+	f, err := NewField[T](api)
+	if err != nil {
+		return err
+	}
+	closeScope := api.Compiler().(frontend.Conditioner).When(c.Enabled)
+	f.AssertIsEqual(&c.A, &c.B)
+	closeScope()
+	// A is used again outside of the conditional scope, where its width must be checked
+	f.AssertIsEqual(&c.A, &c.C)
+	return nil
+}
+
+func TestConditional(t *testing.T) {
+	// the varuna range checker only supports the R1CS builder, and its checks are only
+	// enforced by the prover
+	t.Setenv("DISABLE_VARUNA_RANGE_CHECK_METHODS", "true")
+	testConditional[Secp256k1Fp](t)
+	testConditional[BN254Fp](t)
+}
+
+func testConditional[T FieldParams](t *testing.T) {
+	var fp T
+	assert := test.NewAssert(t)
+	assert.Run(func(assert *test.Assert) {
+		a, _ := rand.Int(rand.Reader, fp.Modulus())
+		b, _ := rand.Int(rand.Reader, fp.Modulus())
+		var circuit ConditionalCircuit[T]
+		opts := []test.TestingOption{test.WithCurves(testCurve), test.NoSerializationChecks(), test.WithBackends(backend.GROTH16, backend.PLONK)}
+
+		assert.ProverSucceeded(&circuit, &ConditionalCircuit[T]{Enabled: 1, A: ValueOf[T](a), B: ValueOf[T](a), C: ValueOf[T](a)}, opts...)
+		assert.ProverSucceeded(&circuit, &ConditionalCircuit[T]{Enabled: 0, A: ValueOf[T](a), B: ValueOf[T](b), C: ValueOf[T](a)}, opts...)
+		assert.ProverFailed(&circuit, &ConditionalCircuit[T]{Enabled: 1, A: ValueOf[T](a), B: ValueOf[T](b), C: ValueOf[T](a)}, opts...)
+
+		// a limb of A out of range: it is first checked under the disabled condition of the
+		// scope, and must still be checked outside of it. The range checker decomposes in
+		// chunks and may accept a few more bits than checked, hence the large overflow.
+		overflow := new(big.Int).Lsh(big.NewInt(1), fp.BitsPerLimb()+32)
+		wide := ValueOf[T](0)
+		wide.Limbs[0] = overflow
+		assert.ProverFailed(&circuit, &ConditionalCircuit[T]{Enabled: 0, A: wide, B: ValueOf[T](b), C: ValueOf[T](overflow)}, opts...)
+	}, testName[T]())
+}
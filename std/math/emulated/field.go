@@ -178,6 +178,12 @@ func (f *Field[T]) enforceWidthConditional(a *Element[T]) (didConstrain bool) {
 		// constant values are constant
 		return false
 	}
+	// inside a conditional scope the width check is gated and may not hold, so the limbs are
+	// not recorded as constrained for the other uses of the element
+	gated := false
+	if conditioner, ok := f.api.Compiler().(frontend.Conditioner); ok {
+		_, gated = conditioner.Condition()
+	}
 	for i := range a.Limbs {
 		if !frontend.IsCanonical(a.Limbs[i]) {
 			// this is not a canonical variable, nor a constant. This may happen
@@ -200,7 +206,9 @@ func (f *Field[T]) enforceWidthConditional(a *Element[T]) (didConstrain bool) {
 				// that we should enforce width for the whole element. But we
 				// still iterate over all limbs just to mark them in the table.
 				didConstrain = true
-				f.constrainedLimbs[h] = struct{}{}
+				if !gated {
+					f.constrainedLimbs[h] = struct{}{}
+				}
 			}
 		} else {
 			// we have no way of knowing if the limb has been constrained. To be
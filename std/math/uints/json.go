@@ -0,0 +1,129 @@
+package uints
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// The types of this package define their JSON representation in witness assignments (see
+// schema.JSONHook): a U8 is a number, a slice or array of U8 is a hex string (or an array of
+// numbers) and U32 / U64 are numbers (or decimal or hex strings).
+
+// GnarkMarshalJSON implements schema.JSONHook.
+func (e *U8) GnarkMarshalJSON(leaves []*big.Int) ([]byte, error) {
+	if len(leaves) != 1 || leaves[0].BitLen() > 8 {
+		return nil, fmt.Errorf("invalid byte")
+	}
+	return []byte(leaves[0].String()), nil
+}
+
+// GnarkUnmarshalJSON implements schema.JSONHook.
+func (e *U8) GnarkUnmarshalJSON(data []byte) ([]*big.Int, error) {
+	return unmarshalLong(data, 1)
+}
+
+// GnarkMarshalJSONArray implements schema.JSONArrayHook; arrays of bytes are encoded as
+// hex strings.
+func (e *U8) GnarkMarshalJSONArray(leaves []*big.Int) ([]byte, error) {
+	b := make([]byte, len(leaves))
+	for i := range leaves {
+		if leaves[i].BitLen() > 8 {
+			return nil, fmt.Errorf("invalid byte at index %d", i)
+		}
+		b[i] = byte(leaves[i].Uint64())
+	}
+	return json.Marshal("0x" + hex.EncodeToString(b))
+}
+
+// GnarkUnmarshalJSONArray implements schema.JSONArrayHook; it accepts a 0x-prefixed hex
+// string or an array of numbers.
+func (e *U8) GnarkUnmarshalJSONArray(data []byte) ([]*big.Int, error) {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		if !strings.HasPrefix(s, "0x") {
+			return nil, fmt.Errorf("byte strings must be 0x-prefixed hex strings")
+		}
+		b, err := hex.DecodeString(s[2:])
+		if err != nil {
+			return nil, err
+		}
+		r := make([]*big.Int, len(b))
+		for i := range b {
+			r[i] = new(big.Int).SetUint64(uint64(b[i]))
+		}
+		return r, nil
+	}
+
+	var elements []json.RawMessage
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return nil, fmt.Errorf("expected a hex string or an array of bytes")
+	}
+	r := make([]*big.Int, len(elements))
+	for i := range elements {
+		v, err := unmarshalLong(elements[i], 1)
+		if err != nil {
+			return nil, err
+		}
+		r[i] = v[0]
+	}
+	return r, nil
+}
+
+// GnarkMarshalJSON implements schema.JSONHook.
+func (e *U32) GnarkMarshalJSON(leaves []*big.Int) ([]byte, error) {
+	return marshalLong(leaves, 4)
+}
+
+// GnarkUnmarshalJSON implements schema.JSONHook.
+func (e *U32) GnarkUnmarshalJSON(data []byte) ([]*big.Int, error) {
+	return unmarshalLong(data, 4)
+}
+
+// GnarkMarshalJSON implements schema.JSONHook.
+func (e *U64) GnarkMarshalJSON(leaves []*big.Int) ([]byte, error) {
+	return marshalLong(leaves, 8)
+}
+
+// GnarkUnmarshalJSON implements schema.JSONHook.
+func (e *U64) GnarkUnmarshalJSON(data []byte) ([]*big.Int, error) {
+	return unmarshalLong(data, 8)
+}
+
+// marshalLong encodes the little-endian bytes as a JSON number.
+func marshalLong(leaves []*big.Int, nbBytes int) ([]byte, error) {
+	if len(leaves) != nbBytes {
+		return nil, fmt.Errorf("expected %d bytes, got %d", nbBytes, len(leaves))
+	}
+	v := new(big.Int)
+	for i := nbBytes - 1; i >= 0; i-- {
+		if leaves[i].BitLen() > 8 {
+			return nil, fmt.Errorf("invalid byte at index %d", i)
+		}
+		v.Lsh(v, 8)
+		v.Add(v, leaves[i])
+	}
+	return []byte(v.String()), nil
+}
+
+// unmarshalLong decodes a JSON integer into nbBytes little-endian bytes.
+func unmarshalLong(data []byte, nbBytes int) ([]*big.Int, error) {
+	v, err := utils.BigIntFromJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	if v.Sign() < 0 || v.BitLen() > 8*nbBytes {
+		return nil, fmt.Errorf("%s doesn't fit in %d bytes", v.String(), nbBytes)
+	}
+	r := make([]*big.Int, nbBytes)
+	mask := big.NewInt(0xff)
+	for i := range r {
+		r[i] = new(big.Int).And(v, mask)
+		v.Rsh(v, 8)
+	}
+	return r, nil
+}
@@ -25,8 +25,13 @@ import (
 // package anyway in test.
 var _ = r1cs.NewBuilder
 
-// New returns a new range checker depending on the frontend capabilities.
+// New returns a new range checker depending on the frontend capabilities. The checks are
+// gated by the conditional scope in which they are called (see frontend.Conditioner).
 func New(api frontend.API) frontend.Rangechecker {
+	return conditionalChecker{api: api, rc: newChecker(api)}
+}
+
+func newChecker(api frontend.API) frontend.Rangechecker {
 	if rc, ok := api.(frontend.Rangechecker); ok {
 		return rc
 	}
@@ -42,6 +47,22 @@ func New(api frontend.API) frontend.Rangechecker {
 	return plainChecker{api: api}
 }
 
+// conditionalChecker checks 0 instead of v when the condition of the current conditional
+// scope is 0.
+type conditionalChecker struct {
+	api frontend.API
+	rc  frontend.Rangechecker
+}
+
+func (c conditionalChecker) Check(v frontend.Variable, bits int) {
+	if conditioner, ok := c.api.Compiler().(frontend.Conditioner); ok {
+		if cond, ok := conditioner.Condition(); ok {
+			v = c.api.Mul(cond, v)
+		}
+	}
+	c.rc.Check(v, bits)
+}
+
 // GetHints returns all hints used in this package
 func GetHints() []solver.Hint {
 	return []solver.Hint{DecomposeHint}
@@ -125,6 +125,9 @@ func (c *varunaChecker) handleVarunaRangeCheck(api frontend.API) error {
 		}
 		// store all limbs for counting
 		decomposed = append(decomposed, limbs...)
+		if r, ok := api.Compiler().(frontend.LookupRowsRecorder); ok {
+			r.RecordLookupRows(len(limbs))
+		}
 		// check that limbs are correct. We check the sizes of the limbs later
 		var composed frontend.Variable = 0
 		for j := range limbs {
@@ -1,6 +1,7 @@
 package export_utils
 
 import (
+	"io"
 	"math/big"
 	"os"
 
@@ -109,11 +110,7 @@ func SerializeAssignment(r1cs constraint.R1CS, solution *cs.R1CSSolution, filePa
 
 	{
 		fAssignment, _ := os.Create(filePath)
-		got, err := cbor.Marshal(&assignmentRaw)
-		if err != nil {
-			return err
-		}
-		if _, err := fAssignment.Write(got); err != nil {
+		if err := WriteAssignment(fAssignment, &assignmentRaw); err != nil {
 			return err
 		}
 		fAssignment.Close()
@@ -121,6 +118,64 @@ func SerializeAssignment(r1cs constraint.R1CS, solution *cs.R1CSSolution, filePa
 	return nil
 }
 
+// WriteAssignment writes the CBOR encoding of the assignment to w; this is the format of
+// SerializeAssignment, for assignments computed without the curve-typed solution (see
+// constraint/witnessgen).
+func WriteAssignment(w io.Writer, assignment *AssignmentRaw) error {
+	got, err := cbor.Marshal(assignment)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(got)
+	return err
+}
+
+// LabelsRaw is the side-car of the exported R1CS: the labels of the wires and the scopes of
+// the constraints (see frontend.Scoper), for debugging tools.
+type LabelsRaw struct {
+	Wires  map[int]string `json:"wires"`  /* wire index -> label */
+	Scopes []ScopeRaw     `json:"scopes"` /* ranges of constraints added in a scope */
+}
+
+type ScopeRaw struct {
+	Start int    `json:"start"` /* first constraint of the range */
+	End   int    `json:"end"`   /* end of the range, exclusive */
+	Scope string `json:"scope"` /* "/"-separated path of the scope */
+}
+
+func SerializeLabels(r1cs constraint.R1CS, filePath string) error {
+	system := r1cs.GetSystem()
+	labelsRaw := LabelsRaw{Wires: make(map[int]string, len(system.WireLabels))}
+	for label, wireID := range system.WireLabels {
+		labelsRaw.Wires[wireID] = label
+	}
+	for i, r := range system.ScopeRanges {
+		if r.Scope < 0 {
+			continue
+		}
+		end := system.NbConstraints
+		if i+1 < len(system.ScopeRanges) {
+			end = system.ScopeRanges[i+1].Start
+		}
+		labelsRaw.Scopes = append(labelsRaw.Scopes, ScopeRaw{r.Start, end, system.Scopes[r.Scope]})
+	}
+
+	fLabels, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	got, err := cbor.Marshal(&labelsRaw)
+	if err != nil {
+		fLabels.Close()
+		return err
+	}
+	if _, err := fLabels.Write(got); err != nil {
+		fLabels.Close()
+		return err
+	}
+	return fLabels.Close()
+}
+
 type LookupRaw struct {
 	Table       [][3]uint32     `json:"table"` /* Note the type of value is uint32, in case the baseLength shall not larger than 32 */
 	Constraints []ConstraintRaw `json:"constraints"`
@@ -0,0 +1,98 @@
+// Package varslice provides slices of variable length in circuits.
+//
+// A [Slice] is allocated in the circuit with a fixed maximum number of
+// elements, given by the "maxlen" struct tag option of its field, and a length
+// wire. When building the witness, the assignment is padded automatically and
+// its length is recorded:
+//
+//	type Circuit struct {
+//	    Msg varslice.Slice[uints.U8] `gnark:",maxlen=64"`
+//	}
+//
+//	// compile: Msg has 64 bytes and a length
+//	ccs, _ := frontend.Compile(field, r1cs.NewBuilder, &Circuit{})
+//	// witness: Msg is padded with zeroes, and its length is set to 5
+//	w, _ := frontend.NewWitness(&Circuit{Msg: varslice.Of(uints.NewU8Array([]byte("gnark"))...)}, field)
+//
+// In the circuit, the elements after the length are padding and must be masked
+// (see [Slice.Mask] and [Slice.ForEach]).
+package varslice
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/schema"
+	"github.com/consensys/gnark/std/selector"
+)
+
+// Slice is a slice of at most len(Values) elements; Len is the actual number of
+// elements.
+type Slice[T any] struct {
+	Len    frontend.Variable
+	Values []T
+}
+
+// Of returns the assignment of a slice with the given elements. The length is set
+// and the elements are padded when building the witness.
+func Of[T any](values ...T) Slice[T] {
+	return Slice[T]{Values: values}
+}
+
+// GnarkMaxLenHook implements schema.MaxLenHook. It sets the length to the number of
+// elements, if not already set, and pads the elements up to maxLen.
+func (s *Slice[T]) GnarkMaxLenHook(maxLen int) error {
+	if len(s.Values) > maxLen {
+		return fmt.Errorf("%d elements exceed the maximum length %d", len(s.Values), maxLen)
+	}
+	if s.Len == nil {
+		s.Len = len(s.Values)
+	}
+	return schema.Pad(reflect.ValueOf(&s.Values).Elem(), tVariable, maxLen)
+}
+
+var tVariable = reflect.TypeOf((*frontend.Variable)(nil)).Elem()
+
+// MaxLen returns the maximum number of elements of the slice.
+func (s *Slice[T]) MaxLen() int {
+	return len(s.Values)
+}
+
+// Mask returns mask[i] = 1 if i < Len and 0 otherwise, for i < MaxLen. It constrains
+// Len to be at most MaxLen; circuits using a Slice should call Mask (or ForEach) to
+// ensure it.
+func (s *Slice[T]) Mask(api frontend.API) []frontend.Variable {
+	switch len(s.Values) {
+	case 0:
+		api.AssertIsEqual(s.Len, 0)
+		return nil
+	case 1:
+		api.AssertIsBoolean(s.Len)
+		return []frontend.Variable{s.Len}
+	}
+	ones := make([]frontend.Variable, len(s.Values))
+	for i := range ones {
+		ones[i] = 1
+	}
+	return selector.Partition(api, s.Len, false, ones)
+}
+
+// ForEach calls f on each element of the slice, including the padding; active is 1
+// for the first Len elements and 0 for the padding.
+func (s *Slice[T]) ForEach(api frontend.API, f func(i int, v T, active frontend.Variable)) {
+	mask := s.Mask(api)
+	for i := range s.Values {
+		f(i, s.Values[i], mask[i])
+	}
+}
+
+// Masked returns the elements of the slice, with the padding set to 0.
+func Masked(api frontend.API, s *Slice[frontend.Variable]) []frontend.Variable {
+	mask := s.Mask(api)
+	r := make([]frontend.Variable, len(s.Values))
+	for i := range r {
+		r[i] = api.Mul(mask[i], s.Values[i])
+	}
+	return r
+}
@@ -0,0 +1,79 @@
+package varslice_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/varslice"
+	"github.com/consensys/gnark/test"
+)
+
+type sumCircuit struct {
+	In  varslice.Slice[frontend.Variable] `gnark:",maxlen=8"`
+	Sum frontend.Variable                 `gnark:",public"`
+}
+
+func (c *sumCircuit) Define(api frontend.API) error {
+	var sum frontend.Variable = 0
+	for _, v := range varslice.Masked(api, &c.In) {
+		sum = api.Add(sum, v)
+	}
+	api.AssertIsEqual(sum, c.Sum)
+	return nil
+}
+
+func TestSum(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	assert.ProverSucceeded(&sumCircuit{}, &sumCircuit{In: varslice.Of[frontend.Variable](1, 2, 3), Sum: 6}, test.WithCurves(ecc.BN254))
+	assert.ProverSucceeded(&sumCircuit{}, &sumCircuit{In: varslice.Of[frontend.Variable](), Sum: 0}, test.WithCurves(ecc.BN254))
+	assert.ProverSucceeded(&sumCircuit{}, &sumCircuit{In: varslice.Of[frontend.Variable](1, 2, 3, 4, 5, 6, 7, 8), Sum: 36}, test.WithCurves(ecc.BN254))
+
+	// the padding is ignored
+	assert.ProverSucceeded(&sumCircuit{}, &sumCircuit{In: varslice.Slice[frontend.Variable]{Len: 2, Values: []frontend.Variable{1, 2, 3}}, Sum: 3}, test.WithCurves(ecc.BN254))
+	assert.ProverFailed(&sumCircuit{}, &sumCircuit{In: varslice.Of[frontend.Variable](1, 2, 3), Sum: 3}, test.WithCurves(ecc.BN254))
+	// the length is bounded by the maximum length
+	assert.ProverFailed(&sumCircuit{}, &sumCircuit{In: varslice.Slice[frontend.Variable]{Len: 9, Values: []frontend.Variable{1}}, Sum: 1}, test.WithCurves(ecc.BN254))
+}
+
+type bytesCircuit struct {
+	Msg    varslice.Slice[uints.U8] `gnark:",maxlen=6"`
+	Tail   []frontend.Variable      `gnark:",maxlen=3"`
+	Count  frontend.Variable        `gnark:",public"`
+	Weight frontend.Variable        `gnark:",public"`
+}
+
+func (c *bytesCircuit) Define(api frontend.API) error {
+	var count, weight frontend.Variable = 0, 0
+	c.Msg.ForEach(api, func(_ int, v uints.U8, active frontend.Variable) {
+		count = api.Add(count, active)
+		weight = api.Add(weight, api.Mul(active, v.Val))
+	})
+	for _, v := range c.Tail {
+		weight = api.Add(weight, v)
+	}
+	api.AssertIsEqual(count, c.Count)
+	api.AssertIsEqual(weight, c.Weight)
+	return nil
+}
+
+func TestBytes(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	assert.ProverSucceeded(&bytesCircuit{}, &bytesCircuit{
+		Msg:    varslice.Of(uints.NewU8Array([]byte{1, 2, 3})...),
+		Tail:   []frontend.Variable{10},
+		Count:  3,
+		Weight: 16,
+	}, test.WithCurves(ecc.BN254))
+
+	// too many elements
+	_, err := frontend.NewWitness(&bytesCircuit{
+		Msg:    varslice.Of(uints.NewU8Array([]byte{1, 2, 3, 4, 5, 6, 7})...),
+		Count:  7,
+		Weight: 28,
+	}, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
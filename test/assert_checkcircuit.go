@@ -277,13 +277,13 @@ var (
 			pkBuilder, vkBuilder, proofBuilder func() any,
 			err error) {
 			pk, vk, err = plonkfri.Setup(ccs)
-			return pk, vk, func() any { return nil }, func() any { return nil }, func() any { return nil }, err
+			return pk, vk, func() any { return plonkfri.NewProvingKey(curve) }, func() any { return plonkfri.NewVerifyingKey(curve) }, func() any { return plonkfri.NewProof(curve) }, err
 		},
 		prove: func(ccs constraint.ConstraintSystem, pk any, fullWitness witness.Witness, opts ...backend.ProverOption) (proof any, err error) {
 			return plonkfri.Prove(ccs, pk.(plonkfri.ProvingKey), fullWitness, opts...)
 		},
 		verify: func(proof, vk any, publicWitness witness.Witness) error {
-			return plonkfri.Verify(proof, vk.(plonkfri.VerifyingKey), publicWitness)
+			return plonkfri.Verify(proof.(plonkfri.Proof), vk.(plonkfri.VerifyingKey), publicWitness)
 		},
 	}
 )
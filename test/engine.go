@@ -59,6 +59,8 @@ type engine struct {
 	kvstore.Store
 	blueprints        []constraint.Blueprint
 	internalVariables []*big.Int
+	scopes            []string   // paths of the open scopes
+	conditions        []*big.Int // conditions of the open conditional scopes
 }
 
 // TestEngineOption defines an option for the test engine.
@@ -438,6 +440,9 @@ func (e *engine) Cmp(i1, i2 frontend.Variable) frontend.Variable {
 
 func (e *engine) AssertIsEqual(i1, i2 frontend.Variable) {
 	atomic.AddUint64(&cptAssertIsEqual, 1)
+	if e.gatedOff() {
+		return
+	}
 	b1, b2 := e.toBigInt(i1), e.toBigInt(i2)
 	if b1.Cmp(b2) != 0 {
 		panic(fmt.Sprintf("[assertIsEqual] %s == %s", b1.String(), b2.String()))
@@ -445,6 +450,9 @@ func (e *engine) AssertIsEqual(i1, i2 frontend.Variable) {
 }
 
 func (e *engine) AssertIsDifferent(i1, i2 frontend.Variable) {
+	if e.gatedOff() {
+		return
+	}
 	b1, b2 := e.toBigInt(i1), e.toBigInt(i2)
 	if b1.Cmp(b2) == 0 {
 		panic(fmt.Sprintf("[assertIsDifferent] %s != %s", b1.String(), b2.String()))
@@ -452,11 +460,17 @@ func (e *engine) AssertIsDifferent(i1, i2 frontend.Variable) {
 }
 
 func (e *engine) AssertIsBoolean(i1 frontend.Variable) {
+	if e.gatedOff() {
+		return
+	}
 	b1 := e.toBigInt(i1)
 	e.mustBeBoolean(b1)
 }
 
 func (e *engine) AssertIsLessOrEqual(v frontend.Variable, bound frontend.Variable) {
+	if e.gatedOff() {
+		return
+	}
 
 	bValue := e.toBigInt(bound)
 
@@ -481,6 +495,9 @@ func (e *engine) Println(a ...frontend.Variable) {
 		sbb.WriteString(strconv.Itoa(line))
 		sbb.WriteByte(' ')
 	}
+	if scope := e.currentScope(); scope != "" {
+		sbb.WriteString("[" + scope + "] ")
+	}
 
 	for i := 0; i < len(a); i++ {
 		e.print(&sbb, a[i])
@@ -570,7 +587,7 @@ func (e *engine) IsBoolean(v frontend.Variable) bool {
 }
 
 func (e *engine) MarkBoolean(v frontend.Variable) {
-	if !e.IsBoolean(v) {
+	if !e.IsBoolean(v) && !e.gatedOff() {
 		panic("mark boolean a non-boolean value")
 	}
 }
@@ -673,6 +690,76 @@ func (e *engine) Commit(v ...frontend.Variable) (frontend.Variable, error) {
 	return res, nil
 }
 
+// Label implements frontend.Labeler. The test engine computes all the values, labels are
+// ignored.
+func (e *engine) Label(v frontend.Variable, _ string) (frontend.Variable, error) {
+	return v, nil
+}
+
+// Scope implements frontend.Scoper. The path of the current scope prefixes the logs of
+// the test engine.
+func (e *engine) Scope(name string) func() {
+	if scope := e.currentScope(); scope != "" {
+		name = scope + "/" + name
+	}
+	e.scopes = append(e.scopes, name)
+	return func() {
+		e.scopes = e.scopes[:len(e.scopes)-1]
+	}
+}
+
+// When implements frontend.Conditioner. The assertions are skipped when the condition of the
+// scope is 0.
+func (e *engine) When(cond frontend.Variable) func() {
+	e.AssertIsBoolean(cond)
+	c := new(big.Int).Set(e.toBigInt(cond))
+	if current, ok := e.Condition(); ok {
+		c.Mul(c, current.(*big.Int))
+	}
+	e.conditions = append(e.conditions, c)
+	n := len(e.conditions)
+	return func() {
+		if len(e.conditions) != n {
+			panic("conditional scopes must be closed in reverse order of opening")
+		}
+		e.conditions = e.conditions[:n-1]
+	}
+}
+
+// Condition implements frontend.Conditioner.
+func (e *engine) Condition() (frontend.Variable, bool) {
+	if len(e.conditions) == 0 {
+		return nil, false
+	}
+	return e.conditions[len(e.conditions)-1], true
+}
+
+// Parallel implements frontend.Parallelizer. The test engine runs the sections one after the
+// other, and makes the calls they defer at the end of the circuit.
+func (e *engine) Parallel(sections ...frontend.Section) ([][]frontend.Variable, error) {
+	outputs := make([][]frontend.Variable, len(sections))
+	for i := range sections {
+		var err error
+		if outputs[i], err = sections[i](e); err != nil {
+			return nil, fmt.Errorf("section %d: %w", i, err)
+		}
+	}
+	return outputs, nil
+}
+
+// gatedOff returns true if the assertions are disabled by a conditional scope.
+func (e *engine) gatedOff() bool {
+	c, ok := e.Condition()
+	return ok && c.(*big.Int).Sign() == 0
+}
+
+func (e *engine) currentScope() string {
+	if len(e.scopes) == 0 {
+		return ""
+	}
+	return e.scopes[len(e.scopes)-1]
+}
+
 func (e *engine) Defer(cb func(frontend.API) error) {
 	circuitdefer.Put(e, cb)
 }
@@ -39,7 +39,8 @@ const srsCachedSize = (1 << 14) + 3
 // NewKZGSRS uses ccs nb variables and nb constraints to initialize a kzg srs
 // for sizes < 2¹⁵, returns a pre-computed cached SRS
 //
-// /!\ warning /!\: this method is here for convenience only: in production, a SRS generated through MPC should be used.
+// /!\ warning /!\: this method is here for convenience only: in production, a SRS generated through MPC should be used,
+// for example one of a public ceremony read with the backend/srs package.
 func NewKZGSRS(ccs constraint.ConstraintSystem) (kzg.SRS, error) {
 
 	nbConstraints := ccs.GetNbConstraints()